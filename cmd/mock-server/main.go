@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"time"
 
+	"search-engine-service/internal/faults"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -50,6 +52,11 @@ type MockArticleResponse struct {
 func main() {
 	router := gin.Default()
 
+	// Fault injection, failing provider requests on demand
+	injector := faults.New()
+	router.Use(injector.Middleware())
+	injector.RegisterRoutes(router)
+
 	// JSON Provider (Videos)
 	router.GET("/api/videos", func(c *gin.Context) {
 		videos := []MockVideo{
@@ -166,6 +173,7 @@ func main() {
 				"videos":   "/api/videos",
 				"articles": "/api/articles",
 				"health":   "/health",
+				"faults":   "/faults",
 			},
 		})
 	})
@@ -174,6 +182,7 @@ func main() {
 	fmt.Printf("Mock server starting on port %s\n", port)
 	fmt.Printf("JSON Provider: http://localhost%s/api/videos\n", port)
 	fmt.Printf("XML Provider: http://localhost%s/api/articles\n", port)
+	fmt.Printf("Fault injection: http://localhost%s/faults\n", port)
 	
 	if err := router.Run(port); err != nil {
 		log.Fatal("Failed to start mock server:", err)
@@ -48,16 +48,38 @@ func main() {
 
 	// Initialize providers
 	providerConfig := providers.ProviderConfig{
-		JSONURL:   cfg.Providers.JSONURL,
-		XMLURL:    cfg.Providers.XMLURL,
-		Timeout:   cfg.Providers.Timeout,
-		RateLimit: cfg.Providers.RateLimit,
+		Instances:   cfg.Providers.Instances,
+		Timeout:     cfg.Providers.Timeout,
+		RateLimit:   cfg.Providers.RateLimit,
+		Concurrency: cfg.Providers.Concurrency,
+		Retry:       cfg.Providers.Retry,
+		Breaker:     cfg.Providers.Breaker,
+		Logger:      logger,
+	}
+	providerManager, err := providers.NewManager(providerConfig)
+	if err != nil {
+		log.Fatalf("Failed to initialize providers: %v", err)
 	}
-	providerManager := providers.NewManager(providerConfig)
 	
 	// Initialize services
-	searchService := services.NewSearchService(db, providerManager)
-	scoringService := services.NewScoringService()
+	searchService := services.NewSearchService(db, providerManager, cfg.Search)
+	scoringService := searchService.Scoring()
+
+	// Build the in-memory search index, searches retry lazily if this fails
+	if err := searchService.BuildIndex(); err != nil {
+		log.Printf("Failed to build search index: %v", err)
+	}
+
+	// Load the engagement priors before anything gets scored
+	if err := searchService.RefreshPriors(); err != nil {
+		log.Printf("Failed to compute engagement priors: %v", err)
+	}
+
+	// Keep the stored scores fresh in the background
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go searchService.AutoRescore(jobsCtx, cfg.Search.RescoreInterval)
+	go searchService.AutoRefresh(jobsCtx)
 
 	// Initialize API
 	apiHandler := api.NewHandler(searchService, scoringService)
@@ -103,6 +125,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	// Give outstanding requests a deadline for completion
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
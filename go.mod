@@ -8,7 +8,6 @@ require (
 	github.com/google/uuid v1.3.0
 	github.com/joho/godotenv v1.5.1
 	github.com/stretchr/testify v1.8.4
-	go.uber.org/dig v1.17.0
 	go.uber.org/zap v1.24.0
 	gorm.io/driver/mysql v1.5.2
 	gorm.io/gorm v1.25.5
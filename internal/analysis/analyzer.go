@@ -0,0 +1,101 @@
+package analysis
+
+import (
+	"strings"
+	"sync"
+)
+
+// Filter transforms the term of a token, an empty result drops the token
+type Filter func(term string) string
+
+// Analyzer runs tokens through a chain of filters, the same analyzer must be
+// used to index a document and to look up query terms in it
+type Analyzer struct {
+	name    string
+	filters []Filter
+}
+
+// New creates an analyzer applying the filters in order
+func New(name string, filters ...Filter) *Analyzer {
+	return &Analyzer{name: name, filters: filters}
+}
+
+// Name returns the name of the analyzer
+func (a *Analyzer) Name() string {
+	return a.name
+}
+
+// Analyze tokenizes and filters a text, dropped tokens leave a gap in the
+// positions so phrases still line up
+func (a *Analyzer) Analyze(text string) []Token {
+	return a.filter(Tokenize(text))
+}
+
+// AnalyzeTags tokenizes and filters a comma separated tag list
+func (a *Analyzer) AnalyzeTags(tags string) []Token {
+	return a.filter(TokenizeTags(tags))
+}
+
+// Term returns the analyzed form of a single word, empty if it is dropped
+func (a *Analyzer) Term(word string) string {
+	tokens := a.Analyze(word)
+	if len(tokens) != 1 {
+		return ""
+	}
+	return tokens[0].Term
+}
+
+// filter applies the filter chain to every token
+func (a *Analyzer) filter(tokens []Token) []Token {
+	result := tokens[:0]
+	for _, token := range tokens {
+		term := token.Term
+		for _, filter := range a.filters {
+			if term = filter(term); term == "" {
+				break
+			}
+		}
+		if term != "" {
+			token.Term = term
+			result = append(result, token)
+		}
+	}
+	return result
+}
+
+// Standard lowercases and folds terms without language specific rules, it
+// analyzes content in languages without a registered analyzer
+var Standard = New("standard", Lowercase, ASCIIFolding)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*Analyzer{
+		"en": English,
+		"tr": Turkish,
+	}
+)
+
+// Register makes an analyzer available for a language code
+func Register(language string, analyzer *Analyzer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[strings.ToLower(language)] = analyzer
+}
+
+// For returns the analyzer of a language code such as "en" or "tr-TR",
+// falling back to the standard analyzer
+func For(language string) *Analyzer {
+	language = strings.ToLower(language)
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if analyzer, ok := registry[language]; ok {
+		return analyzer
+	}
+	return Standard
+}
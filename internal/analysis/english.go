@@ -0,0 +1,256 @@
+package analysis
+
+// englishStopWords are common English words that carry no meaning on their own
+var englishStopWords = []string{
+	"a", "an", "and", "are", "as", "at", "be", "but", "by", "for", "if", "in",
+	"into", "is", "it", "no", "not", "of", "on", "or", "such", "that", "the",
+	"their", "then", "there", "these", "they", "this", "to", "was", "will", "with",
+}
+
+// English analyzes English text, stemming terms with the Porter algorithm
+var English = New("en", Lowercase, StopWords(englishStopWords...), PorterStem, ASCIIFolding)
+
+// PorterStem reduces an English word to its stem with the Porter algorithm,
+// so "running" and "runs" both become "run"
+func PorterStem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	s := &porterStemmer{b: []byte(word)}
+	s.step1a()
+	s.step1b()
+	s.step1c()
+	s.step2()
+	s.step3()
+	s.step4()
+	s.step5()
+	return string(s.b)
+}
+
+// porterStemmer holds the word being stemmed
+type porterStemmer struct {
+	b []byte
+}
+
+// consonant reports whether the letter at i is a consonant, 'y' is one
+// unless it follows a consonant
+func (s *porterStemmer) consonant(i int) bool {
+	switch s.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !s.consonant(i-1)
+	default:
+		return true
+	}
+}
+
+// measure counts the vowel-consonant sequences in the first n letters
+func (s *porterStemmer) measure(n int) int {
+	m := 0
+	i := 0
+	for i < n && s.consonant(i) {
+		i++
+	}
+	for i < n {
+		for i < n && !s.consonant(i) {
+			i++
+		}
+		if i >= n {
+			break
+		}
+		for i < n && s.consonant(i) {
+			i++
+		}
+		m++
+	}
+	return m
+}
+
+// hasVowel reports whether the first n letters contain a vowel
+func (s *porterStemmer) hasVowel(n int) bool {
+	for i := 0; i < n; i++ {
+		if !s.consonant(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// doubleConsonant reports whether the first n letters end with a double consonant
+func (s *porterStemmer) doubleConsonant(n int) bool {
+	return n >= 2 && s.b[n-1] == s.b[n-2] && s.consonant(n-1)
+}
+
+// cvc reports whether the first n letters end consonant-vowel-consonant,
+// the last consonant not being w, x or y
+func (s *porterStemmer) cvc(n int) bool {
+	if n < 3 || !s.consonant(n-3) || s.consonant(n-2) || !s.consonant(n-1) {
+		return false
+	}
+	switch s.b[n-1] {
+	case 'w', 'x', 'y':
+		return false
+	}
+	return true
+}
+
+// endsWith reports whether the word ends with the suffix
+func (s *porterStemmer) endsWith(suffix string) bool {
+	return len(s.b) >= len(suffix) && string(s.b[len(s.b)-len(suffix):]) == suffix
+}
+
+// stem returns the length of the word without the suffix
+func (s *porterStemmer) stem(suffix string) int {
+	return len(s.b) - len(suffix)
+}
+
+// replace swaps the suffix for the replacement
+func (s *porterStemmer) replace(suffix, replacement string) {
+	s.b = append(s.b[:s.stem(suffix)], replacement...)
+}
+
+// replaceLongest replaces the longest matching suffix of the rules when the
+// stem measure exceeds minMeasure, shorter suffixes are not tried
+func (s *porterStemmer) replaceLongest(rules [][2]string, minMeasure int) {
+	longest := -1
+	for i, rule := range rules {
+		if s.endsWith(rule[0]) && (longest < 0 || len(rule[0]) > len(rules[longest][0])) {
+			longest = i
+		}
+	}
+	if longest < 0 {
+		return
+	}
+
+	suffix, replacement := rules[longest][0], rules[longest][1]
+	if s.measure(s.stem(suffix)) > minMeasure {
+		s.replace(suffix, replacement)
+	}
+}
+
+// step1a removes plurals
+func (s *porterStemmer) step1a() {
+	switch {
+	case s.endsWith("sses"):
+		s.replace("sses", "ss")
+	case s.endsWith("ies"):
+		s.replace("ies", "i")
+	case s.endsWith("ss"):
+	case s.endsWith("s"):
+		s.replace("s", "")
+	}
+}
+
+// step1b removes -ed and -ing
+func (s *porterStemmer) step1b() {
+	if s.endsWith("eed") {
+		if s.measure(s.stem("eed")) > 0 {
+			s.replace("eed", "ee")
+		}
+		return
+	}
+
+	removed := false
+	for _, suffix := range []string{"ed", "ing"} {
+		if s.endsWith(suffix) && s.hasVowel(s.stem(suffix)) {
+			s.replace(suffix, "")
+			removed = true
+			break
+		}
+	}
+	if !removed {
+		return
+	}
+
+	n := len(s.b)
+	switch {
+	case s.endsWith("at"), s.endsWith("bl"), s.endsWith("iz"):
+		s.b = append(s.b, 'e')
+	case s.doubleConsonant(n) && s.b[n-1] != 'l' && s.b[n-1] != 's' && s.b[n-1] != 'z':
+		s.b = s.b[:n-1]
+	case s.measure(n) == 1 && s.cvc(n):
+		s.b = append(s.b, 'e')
+	}
+}
+
+// step1c turns a final y into i when the stem has a vowel
+func (s *porterStemmer) step1c() {
+	if s.endsWith("y") && s.hasVowel(s.stem("y")) {
+		s.b[len(s.b)-1] = 'i'
+	}
+}
+
+var porterStep2 = [][2]string{
+	{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"},
+	{"izer", "ize"}, {"bli", "ble"}, {"alli", "al"}, {"entli", "ent"},
+	{"eli", "e"}, {"ousli", "ous"}, {"ization", "ize"}, {"ation", "ate"},
+	{"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"},
+	{"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+	{"logi", "log"},
+}
+
+// step2 maps double suffixes to single ones
+func (s *porterStemmer) step2() {
+	s.replaceLongest(porterStep2, 0)
+}
+
+var porterStep3 = [][2]string{
+	{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"},
+	{"ical", "ic"}, {"ful", ""}, {"ness", ""},
+}
+
+// step3 removes -ful, -ness and similar suffixes
+func (s *porterStemmer) step3() {
+	s.replaceLongest(porterStep3, 0)
+}
+
+var porterStep4 = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+	"ent", "ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+}
+
+// step4 removes suffixes from stems with a measure above one
+func (s *porterStemmer) step4() {
+	longest := ""
+	for _, suffix := range porterStep4 {
+		if s.endsWith(suffix) && len(suffix) > len(longest) {
+			longest = suffix
+		}
+	}
+	if longest == "" {
+		return
+	}
+
+	n := s.stem(longest)
+	if s.measure(n) <= 1 {
+		return
+	}
+	// -ion is only removed after s or t
+	if longest == "ion" && (n == 0 || (s.b[n-1] != 's' && s.b[n-1] != 't')) {
+		return
+	}
+	s.b = s.b[:n]
+}
+
+// step5 removes a final e and reduces a final double l
+func (s *porterStemmer) step5() {
+	if s.endsWith("e") {
+		n := s.stem("e")
+		m := s.measure(n)
+		if m > 1 || (m == 1 && !s.cvc(n)) {
+			s.b = s.b[:n]
+		}
+	}
+
+	n := len(s.b)
+	if s.endsWith("ll") && s.measure(n) > 1 {
+		s.b = s.b[:n-1]
+	}
+}
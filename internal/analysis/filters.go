@@ -0,0 +1,82 @@
+package analysis
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Lowercase lowercases a term without language specific rules
+func Lowercase(term string) string {
+	return strings.ToLower(term)
+}
+
+// LowercaseSpecial returns a filter lowercasing terms with the casing rules
+// of a language, e.g. unicode.TurkishCase maps 'I' to 'ı' and 'İ' to 'i'
+func LowercaseSpecial(c unicode.SpecialCase) Filter {
+	return func(term string) string {
+		return strings.ToLowerSpecial(c, term)
+	}
+}
+
+// StopWords returns a filter dropping the given lowercased words
+func StopWords(words ...string) Filter {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return func(term string) string {
+		if set[term] {
+			return ""
+		}
+		return term
+	}
+}
+
+// foldings maps lowercase letters with diacritics to their ASCII equivalents
+var foldings = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae",
+	'ç': "c", 'ć': "c", 'č': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ł': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'œ': "oe",
+	'ř': "r",
+	'ś': "s", 'ş': "s", 'š': "s",
+	'ß': "ss",
+	'ť': "t", 'ţ': "t",
+	'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// ASCIIFolding replaces letters with diacritics by their ASCII equivalents
+// and drops combining marks, so "Çalışma" and "calisma" analyze alike
+func ASCIIFolding(term string) string {
+	ascii := true
+	for i := 0; i < len(term); i++ {
+		if term[i] >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return term
+	}
+
+	var b strings.Builder
+	for _, r := range term {
+		if folded, ok := foldings[r]; ok {
+			b.WriteString(folded)
+		} else if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
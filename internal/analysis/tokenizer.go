@@ -0,0 +1,72 @@
+// Package analysis turns text into the terms stored in and looked up from the
+// search index, selecting a language specific analyzer chain per document
+package analysis
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tagPositionGap separates the positions of consecutive tags so that a
+// phrase can never match across two different tags
+const tagPositionGap = 100
+
+// Token represents a single term extracted from a text
+type Token struct {
+	Term     string
+	Position int
+	Start    int // byte offset of the token in the source text
+	End      int
+}
+
+// Tokenize splits text into tokens on every character that is neither a
+// letter, a digit nor a combining mark, terms keep their original case
+func Tokenize(text string) []Token {
+	return tokenizeFrom(text, 0, 0)
+}
+
+// TokenizeTags tokenizes a comma separated tag list, leaving a position gap
+// between tags
+func TokenizeTags(tags string) []Token {
+	var tokens []Token
+	offset := 0
+	position := 0
+	for _, tag := range strings.Split(tags, ",") {
+		tagTokens := tokenizeFrom(tag, offset, position)
+		tokens = append(tokens, tagTokens...)
+		if len(tagTokens) > 0 {
+			position = tagTokens[len(tagTokens)-1].Position + tagPositionGap
+		}
+		offset += len(tag) + 1
+	}
+	return tokens
+}
+
+// tokenizeFrom tokenizes text, shifting byte offsets and positions by the given amounts
+func tokenizeFrom(text string, offset, position int) []Token {
+	var tokens []Token
+	start := -1
+	for i := 0; i <= len(text); {
+		r, size := utf8.RuneError, 1
+		if i < len(text) {
+			r, size = utf8.DecodeRuneInString(text[i:])
+		}
+		isWord := i < len(text) && (unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r))
+		if isWord && start < 0 {
+			start = i
+		}
+		if !isWord && start >= 0 {
+			tokens = append(tokens, Token{
+				Term:     text[start:i],
+				Position: position,
+				Start:    offset + start,
+				End:      offset + i,
+			})
+			position++
+			start = -1
+		}
+		i += size
+	}
+	return tokens
+}
@@ -0,0 +1,63 @@
+package analysis
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// turkishStopWords are common Turkish words that carry no meaning on their own
+var turkishStopWords = []string{
+	"acaba", "ama", "aslında", "az", "bazı", "belki", "bir", "biri", "birkaç",
+	"biz", "bu", "çok", "çünkü", "da", "daha", "de", "defa", "diye", "en",
+	"eğer", "gibi", "hem", "hep", "hepsi", "her", "hiç", "için", "ile", "ise",
+	"kez", "ki", "kim", "mı", "mi", "mu", "mü", "nasıl", "ne", "neden",
+	"nerede", "nereye", "niçin", "niye", "o", "sanki", "siz", "şey", "şu",
+	"tüm", "ve", "veya", "ya", "yani",
+}
+
+// Turkish analyzes Turkish text, lowercasing with the Turkish dotted and
+// dotless i rules before stripping inflectional suffixes
+var Turkish = New("tr", LowercaseSpecial(unicode.TurkishCase), StopWords(turkishStopWords...), TurkishStem, ASCIIFolding)
+
+// turkishSuffixGroups are the noun suffixes removed by TurkishStem, from the
+// outermost case endings over possessives to the plural, each group listing
+// its buffer consonant forms longest first. Bare vowel case endings are left
+// alone as they cannot be told apart from a vowel ending stem.
+var turkishSuffixGroups = [][]string{
+	// Case endings
+	{
+		"ndan", "nden", "lara", "lere", "dan", "den", "tan", "ten",
+		"nda", "nde", "yla", "yle", "nın", "nin", "nun", "nün",
+		"da", "de", "ta", "te", "ya", "ye", "yı", "yi", "yu", "yü",
+		"na", "ne", "ın", "in", "un", "ün",
+	},
+	// Possessives
+	{
+		"ımız", "imiz", "umuz", "ümüz", "ınız", "iniz", "unuz", "ünüz",
+		"sı", "si", "su", "sü", "ı", "i", "u", "ü",
+	},
+	// Plural
+	{"lar", "ler"},
+}
+
+// turkishMinStem is the shortest stem, in letters, a suffix may be removed from
+const turkishMinStem = 2
+
+// TurkishStem strips inflectional suffixes from a lowercased Turkish word,
+// so "kitaplarından" and "kitap" share the stem "kitap"
+func TurkishStem(word string) string {
+	for _, group := range turkishSuffixGroups {
+		for _, suffix := range group {
+			if !strings.HasSuffix(word, suffix) {
+				continue
+			}
+			stem := word[:len(word)-len(suffix)]
+			if utf8.RuneCountInString(stem) >= turkishMinStem {
+				word = stem
+				break
+			}
+		}
+	}
+	return word
+}
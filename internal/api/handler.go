@@ -10,6 +10,9 @@ type Handler struct {
 	SearchHandler    *handlers.SearchHandler
 	ProviderHandler  *handlers.ProviderHandler
 	DashboardHandler *handlers.DashboardHandler
+	SynonymHandler   *handlers.SynonymHandler
+	CurationHandler  *handlers.CurationHandler
+	RescoreHandler   *handlers.RescoreHandler
 }
 
 // NewHandler creates a new API handler
@@ -18,5 +21,8 @@ func NewHandler(searchService *services.SearchService, scoringService *services.
 		SearchHandler:    handlers.NewSearchHandler(searchService, scoringService),
 		ProviderHandler:  handlers.NewProviderHandler(searchService),
 		DashboardHandler: handlers.NewDashboardHandler(searchService, scoringService),
+		SynonymHandler:   handlers.NewSynonymHandler(searchService.Synonyms()),
+		CurationHandler:  handlers.NewCurationHandler(searchService.Curation()),
+		RescoreHandler:   handlers.NewRescoreHandler(searchService),
 	}
 } 
\ No newline at end of file
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CurationHandler handles the curation rule admin requests
+type CurationHandler struct {
+	curationService *services.CurationService
+}
+
+// NewCurationHandler creates a new curation handler
+func NewCurationHandler(curationService *services.CurationService) *CurationHandler {
+	return &CurationHandler{
+		curationService: curationService,
+	}
+}
+
+// curationRuleRequest is the request body of create and update
+type curationRuleRequest struct {
+	Name     string                   `json:"name"`
+	Match    models.CurationMatchType `json:"match" binding:"required"`
+	Query    string                   `json:"query" binding:"required"`
+	Pins     []models.CurationPin     `json:"pins"`
+	Boosts   []models.CurationBoost   `json:"boosts"`
+	Hidden   []uint                   `json:"hidden"`
+	StartsAt *time.Time               `json:"starts_at"`
+	EndsAt   *time.Time               `json:"ends_at"`
+}
+
+// rule converts the request into a curation rule
+func (r *curationRuleRequest) rule() *models.CurationRule {
+	return &models.CurationRule{
+		Name:     r.Name,
+		Match:    r.Match,
+		Query:    r.Query,
+		Pins:     r.Pins,
+		Boosts:   r.Boosts,
+		Hidden:   r.Hidden,
+		StartsAt: r.StartsAt,
+		EndsAt:   r.EndsAt,
+	}
+}
+
+// List handles requests to list all curation rules
+func (ch *CurationHandler) List(c *gin.Context) {
+	rules, err := ch.curationService.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get curation rules",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rules,
+	})
+}
+
+// Get handles requests to get a curation rule by ID
+func (ch *CurationHandler) Get(c *gin.Context) {
+	id, ok := ruleID(c)
+	if !ok {
+		return
+	}
+
+	rule, err := ch.curationService.Get(id)
+	if err != nil {
+		writeRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rule,
+	})
+}
+
+// Create handles requests to create a curation rule
+func (ch *CurationHandler) Create(c *gin.Context) {
+	var req curationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	rule := req.rule()
+	if err := ch.curationService.Create(rule); err != nil {
+		writeRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    rule,
+	})
+}
+
+// Update handles requests to replace a curation rule
+func (ch *CurationHandler) Update(c *gin.Context) {
+	id, ok := ruleID(c)
+	if !ok {
+		return
+	}
+
+	var req curationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	rule := req.rule()
+	if err := ch.curationService.Update(id, rule); err != nil {
+		writeRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rule,
+	})
+}
+
+// Delete handles requests to delete a curation rule
+func (ch *CurationHandler) Delete(c *gin.Context) {
+	id, ok := ruleID(c)
+	if !ok {
+		return
+	}
+
+	if err := ch.curationService.Delete(id); err != nil {
+		writeRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
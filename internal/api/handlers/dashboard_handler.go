@@ -75,12 +75,31 @@ func (dh *DashboardHandler) DashboardSearch(c *gin.Context) {
 		limit = 10
 	}
 
+	highlight, err := highlightFromQuery(c)
+	if err != nil {
+		writeSearchError(c, err)
+		return
+	}
+
 	// Use the search service to perform the search
-	result, err := dh.searchService.Search(query, models.ContentType(contentType), page, limit)
+	result, err := dh.searchService.Search(&models.SearchRequest{
+		Query: query,
+		Filters: models.SearchFilters{
+			Type:      models.ContentType(contentType),
+			Provider:  c.Query("provider"),
+			Language:  c.Query("language"),
+			TagsAll:   c.QueryArray("tag"),
+			Published: c.Query("published"),
+		},
+		Sort:      c.Query("sort"),
+		Order:     c.Query("order"),
+		Cursor:    c.Query("cursor"),
+		Page:      page,
+		Limit:     limit,
+		Highlight: highlight,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to perform search",
-		})
+		writeSearchError(c, err)
 		return
 	}
 
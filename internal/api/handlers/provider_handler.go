@@ -3,7 +3,9 @@ package handlers
 import (
 	"context"
 	"net/http"
+	"time"
 
+	"search-engine-service/internal/providers"
 	"search-engine-service/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -31,20 +33,58 @@ func (ph *ProviderHandler) GetProviders(c *gin.Context) {
 	})
 }
 
-// RefreshProviders fetches fresh content from all providers
+// RefreshProviders fetches fresh content from all providers and reports the
+// outcome of each: 200 when all succeeded, 207 when some failed and 502 when
+// all failed
 func (ph *ProviderHandler) RefreshProviders(c *gin.Context) {
 	ctx := context.Background()
 
-	if err := ph.searchService.RefreshContent(ctx); err != nil {
+	start := time.Now()
+	report, err := ph.searchService.RefreshContent(ctx)
+	if report == nil || (err != nil && report.Status() != providers.FetchFailed) {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to refresh content from providers",
 		})
 		return
 	}
 
+	status := report.Status()
+	code := http.StatusOK
+	message := "Content refreshed successfully"
+	switch status {
+	case providers.FetchPartial:
+		code = http.StatusMultiStatus
+		message = "Content refreshed, some providers failed"
+	case providers.FetchFailed:
+		code = http.StatusBadGateway
+		message = "All providers failed"
+	}
+
+	c.JSON(code, gin.H{
+		"success": status != providers.FetchFailed,
+		"message": message,
+		"data": gin.H{
+			"status":            status,
+			"total_fetched":     len(report.Contents()),
+			"providers_updated": len(report.Results) - report.Failed(),
+			"providers_failed":  report.Failed(),
+			"providers":         report.Results,
+			"duration":          time.Since(start).String(),
+		},
+	})
+}
+
+// GetProviderHealth returns the health and circuit breaker state of every
+// provider
+func (ph *ProviderHandler) GetProviderHealth(c *gin.Context) {
+	overall, health := ph.searchService.GetProviderHealth()
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "Content refreshed successfully",
+		"data": gin.H{
+			"overall_status": overall,
+			"providers":      health,
+		},
 	})
 }
 
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"search-engine-service/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RescoreHandler handles the rescoring admin requests
+type RescoreHandler struct {
+	searchService *services.SearchService
+}
+
+// NewRescoreHandler creates a new rescore handler
+func NewRescoreHandler(searchService *services.SearchService) *RescoreHandler {
+	return &RescoreHandler{
+		searchService: searchService,
+	}
+}
+
+// Rescore recomputes the stored scores of every content and reports the run
+func (rh *RescoreHandler) Rescore(c *gin.Context) {
+	report, err := rh.searchService.Rescore(c.Request.Context())
+	if errors.Is(err, services.ErrRescoreRunning) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "A rescore is already running",
+			"code":  "RESCORE_RUNNING",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to rescore content",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/query"
 	"search-engine-service/internal/services"
+	"search-engine-service/internal/suggest"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // SearchHandler handles search-related HTTP requests
@@ -59,12 +63,29 @@ func (sh *SearchHandler) Search(c *gin.Context) {
 		return
 	}
 
+	highlight, err := highlightFromQuery(c)
+	if err != nil {
+		writeSearchError(c, err)
+		return
+	}
+
 	// Perform search
-	result, err := sh.searchService.Search(query, contentType, page, limit)
+	result, err := sh.searchService.Search(&models.SearchRequest{
+		Query:     query,
+		Mode:      models.SearchMode(c.Query("mode")),
+		Profile:   c.Query("profile"),
+		Filters:   filtersFromQuery(c, contentType),
+		Sort:      c.Query("sort"),
+		Order:     c.Query("order"),
+		Cursor:    c.Query("cursor"),
+		Collapse:  c.Query("collapse") == "true",
+		Explain:   c.Query("explain") == "true",
+		Page:      page,
+		Limit:     limit,
+		Highlight: highlight,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to perform search",
-		})
+		writeSearchError(c, err)
 		return
 	}
 
@@ -74,6 +95,62 @@ func (sh *SearchHandler) Search(c *gin.Context) {
 	})
 }
 
+// filtersFromQuery returns the filters of a GET search, facet values selected
+// on a previous response become filters
+func filtersFromQuery(c *gin.Context, contentType models.ContentType) models.SearchFilters {
+	return models.SearchFilters{
+		Type:      contentType,
+		Provider:  c.Query("provider"),
+		Language:  c.Query("language"),
+		TagsAll:   c.QueryArray("tag"),
+		Published: c.Query("published"),
+	}
+}
+
+// Explain handles requests to explain the rank of a content for a search
+func (sh *SearchHandler) Explain(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Query("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid content ID",
+		})
+		return
+	}
+
+	contentType := models.ContentType(c.Query("type"))
+	if contentType != "" && contentType != models.ContentTypeVideo && contentType != models.ContentTypeText && contentType != "all" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid content type. Must be 'video', 'text', or 'all'",
+		})
+		return
+	}
+
+	explanation, err := sh.searchService.Explain(&models.SearchRequest{
+		Query:    c.Query("q"),
+		Mode:     models.SearchMode(c.Query("mode")),
+		Profile:  c.Query("profile"),
+		Filters:  filtersFromQuery(c, contentType),
+		Sort:     c.Query("sort"),
+		Order:    c.Query("order"),
+		Collapse: c.Query("collapse") == "true",
+	}, uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Content not found",
+			})
+			return
+		}
+		writeSearchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    explanation,
+	})
+}
+
 // GetContentByID handles requests to get a specific content by ID
 func (sh *SearchHandler) GetContentByID(c *gin.Context) {
 	idStr := c.Param("id")
@@ -105,6 +182,45 @@ func (sh *SearchHandler) GetContentByID(c *gin.Context) {
 	})
 }
 
+// GetRelatedContent handles requests for contents similar to a content
+func (sh *SearchHandler) GetRelatedContent(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid content ID",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 50 {
+		limit = 10
+	}
+	crossType := c.Query("cross_type") == "true"
+
+	related, err := sh.searchService.GetRelatedContent(uint(id), limit, crossType)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Content not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get related content",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"contents": related,
+		},
+	})
+}
+
 // GetPopularContent handles requests to get popular content
 func (sh *SearchHandler) GetPopularContent(c *gin.Context) {
 	limitStr := c.DefaultQuery("limit", "10")
@@ -127,16 +243,93 @@ func (sh *SearchHandler) GetPopularContent(c *gin.Context) {
 	})
 }
 
+// GetTrendingContent handles requests to get the contents whose engagement
+// grows fastest over a window
+func (sh *SearchHandler) GetTrendingContent(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	result, err := sh.searchService.GetTrendingContent(&models.TrendingRequest{
+		Window:  c.Query("window"),
+		Filters: filtersFromQuery(c, models.ContentType(c.Query("type"))),
+		Limit:   limit,
+	})
+	if err != nil {
+		var filterErr *models.FilterError
+		var paramErr *models.ParameterError
+		if errors.As(err, &filterErr) || errors.As(err, &paramErr) {
+			writeSearchError(c, err)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get trending content",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// GetSuggestions handles autocomplete requests for a partial query
+func (sh *SearchHandler) GetSuggestions(c *gin.Context) {
+	prefix := c.Query("q")
+	if prefix == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Query parameter 'q' is required",
+		})
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "10")
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 || limit > suggest.MaxSuggestions {
+		limit = 10
+	}
+
+	suggestions, err := sh.searchService.GetSuggestions(prefix, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get suggestions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"suggestions": suggestions,
+		},
+	})
+}
+
 // SearchWithFilters handles advanced search with filters
 func (sh *SearchHandler) SearchWithFilters(c *gin.Context) {
 	var request struct {
-		Query   string                 `json:"query"`
-		Filters map[string]interface{} `json:"filters"`
-		Page    int                    `json:"page"`
-		Limit   int                    `json:"limit"`
+		Query     string                   `json:"query"`
+		Mode      models.SearchMode        `json:"mode"`
+		Profile   string                   `json:"profile"`
+		Filters   models.SearchFilters     `json:"filters"`
+		Sort      string                   `json:"sort"`
+		Order     string                   `json:"order"`
+		Cursor    string                   `json:"cursor"`
+		Collapse  bool                     `json:"collapse"`
+		Explain   bool                     `json:"explain"`
+		Page      int                      `json:"page"`
+		Limit     int                      `json:"limit"`
+		Highlight *models.HighlightOptions `json:"highlight"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
+		var filterErr *models.FilterError
+		if errors.As(err, &filterErr) {
+			writeSearchError(c, filterErr)
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Invalid request body",
 		})
@@ -152,11 +345,22 @@ func (sh *SearchHandler) SearchWithFilters(c *gin.Context) {
 	}
 
 	// Perform search with filters
-	result, err := sh.searchService.SearchWithFilters(request.Query, request.Filters, request.Page, request.Limit)
+	result, err := sh.searchService.Search(&models.SearchRequest{
+		Query:     request.Query,
+		Mode:      request.Mode,
+		Profile:   request.Profile,
+		Filters:   request.Filters,
+		Sort:      request.Sort,
+		Order:     request.Order,
+		Cursor:    request.Cursor,
+		Collapse:  request.Collapse,
+		Explain:   request.Explain,
+		Page:      request.Page,
+		Limit:     request.Limit,
+		Highlight: request.Highlight,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to perform search",
-		})
+		writeSearchError(c, err)
 		return
 	}
 
@@ -164,4 +368,71 @@ func (sh *SearchHandler) SearchWithFilters(c *gin.Context) {
 		"success": true,
 		"data":    result,
 	})
-} 
\ No newline at end of file
+}
+
+// highlightFromQuery returns the highlighting options of a search request,
+// nil unless highlight=true
+func highlightFromQuery(c *gin.Context) (*models.HighlightOptions, error) {
+	if c.Query("highlight") != "true" {
+		return nil, nil
+	}
+
+	options := &models.HighlightOptions{
+		PreTag:  c.Query("highlight_pre_tag"),
+		PostTag: c.Query("highlight_post_tag"),
+	}
+	if value := c.Query("fragment_size"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, &models.ParameterError{Key: "fragment_size", Message: "must be an integer"}
+		}
+		options.FragmentSize = size
+	}
+	if value := c.Query("max_fragments"); value != "" {
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, &models.ParameterError{Key: "max_fragments", Message: "must be an integer"}
+		}
+		options.MaxFragments = count
+	}
+	return options, nil
+}
+
+// writeSearchError responds with a 400 pointing at the offending position for
+// invalid queries, a 400 for invalid filters or parameters and a 500 for any
+// other search failure
+func writeSearchError(c *gin.Context, err error) {
+	var syntaxErr *query.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":    syntaxErr.Error(),
+			"code":     "INVALID_QUERY",
+			"position": syntaxErr.Position,
+		})
+		return
+	}
+
+	var filterErr *models.FilterError
+	if errors.As(err, &filterErr) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": filterErr.Error(),
+			"code":  "INVALID_PARAMETER",
+			"field": filterErr.Key,
+		})
+		return
+	}
+
+	var paramErr *models.ParameterError
+	if errors.As(err, &paramErr) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": paramErr.Error(),
+			"code":  "INVALID_PARAMETER",
+			"field": paramErr.Key,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": "Failed to perform search",
+	})
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SynonymHandler handles the synonym rule admin requests
+type SynonymHandler struct {
+	synonymService *services.SynonymService
+}
+
+// NewSynonymHandler creates a new synonym handler
+func NewSynonymHandler(synonymService *services.SynonymService) *SynonymHandler {
+	return &SynonymHandler{
+		synonymService: synonymService,
+	}
+}
+
+// synonymRuleRequest is the request body of create and update
+type synonymRuleRequest struct {
+	Type        models.SynonymRuleType `json:"type" binding:"required"`
+	Input       string                 `json:"input"`
+	Synonyms    []string               `json:"synonyms"`
+	Replacement string                 `json:"replacement"`
+}
+
+// rule converts the request into a synonym rule
+func (r *synonymRuleRequest) rule() *models.SynonymRule {
+	return &models.SynonymRule{
+		Type:        r.Type,
+		Input:       r.Input,
+		Synonyms:    strings.Join(r.Synonyms, ","),
+		Replacement: r.Replacement,
+	}
+}
+
+// List handles requests to list all synonym rules
+func (sh *SynonymHandler) List(c *gin.Context) {
+	rules, err := sh.synonymService.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get synonym rules",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rules,
+	})
+}
+
+// Get handles requests to get a synonym rule by ID
+func (sh *SynonymHandler) Get(c *gin.Context) {
+	id, ok := ruleID(c)
+	if !ok {
+		return
+	}
+
+	rule, err := sh.synonymService.Get(id)
+	if err != nil {
+		writeRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rule,
+	})
+}
+
+// Create handles requests to create a synonym rule
+func (sh *SynonymHandler) Create(c *gin.Context) {
+	var req synonymRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	rule := req.rule()
+	if err := sh.synonymService.Create(rule); err != nil {
+		writeRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    rule,
+	})
+}
+
+// Update handles requests to replace a synonym rule
+func (sh *SynonymHandler) Update(c *gin.Context) {
+	id, ok := ruleID(c)
+	if !ok {
+		return
+	}
+
+	var req synonymRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	rule := req.rule()
+	if err := sh.synonymService.Update(id, rule); err != nil {
+		writeRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rule,
+	})
+}
+
+// Delete handles requests to delete a synonym rule
+func (sh *SynonymHandler) Delete(c *gin.Context) {
+	id, ok := ruleID(c)
+	if !ok {
+		return
+	}
+
+	if err := sh.synonymService.Delete(id); err != nil {
+		writeRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// ruleID parses the rule ID path parameter, responding with a 400 if invalid
+func ruleID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid rule ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// writeRuleError responds with a 400 for invalid rules, a 404 for unknown
+// rules and a 500 otherwise
+func writeRuleError(c *gin.Context, err error) {
+	var paramErr *models.ParameterError
+	if errors.As(err, &paramErr) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": paramErr.Error(),
+			"code":  "INVALID_PARAMETER",
+			"field": paramErr.Key,
+		})
+		return
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Rule not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": "Failed to update rules",
+	})
+}
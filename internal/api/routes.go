@@ -16,13 +16,17 @@ func SetupRoutes(router *gin.Engine, handler *Handler) {
 		{
 			search.GET("", handler.SearchHandler.Search)
 			search.POST("/filters", handler.SearchHandler.SearchWithFilters)
+			search.GET("/suggestions", handler.SearchHandler.GetSuggestions)
+			search.GET("/explain", handler.SearchHandler.Explain)
 		}
 
 		// Content routes
 		content := api.Group("/content")
 		{
 			content.GET("/:id", handler.SearchHandler.GetContentByID)
+			content.GET("/:id/related", handler.SearchHandler.GetRelatedContent)
 			content.GET("/popular", handler.SearchHandler.GetPopularContent)
+			content.GET("/trending", handler.SearchHandler.GetTrendingContent)
 		}
 
 		// Provider routes
@@ -31,6 +35,7 @@ func SetupRoutes(router *gin.Engine, handler *Handler) {
 			providers.GET("", handler.ProviderHandler.GetProviders)
 			providers.POST("/refresh", handler.ProviderHandler.RefreshProviders)
 			providers.GET("/stats", handler.ProviderHandler.GetContentStats)
+			providers.GET("/health", handler.ProviderHandler.GetProviderHealth)
 		}
 
 		// Dashboard routes
@@ -42,6 +47,30 @@ func SetupRoutes(router *gin.Engine, handler *Handler) {
 		}
 	}
 
+	// Admin routes
+	admin := router.Group("/api/v1/admin")
+	{
+		synonyms := admin.Group("/synonyms")
+		{
+			synonyms.GET("", handler.SynonymHandler.List)
+			synonyms.POST("", handler.SynonymHandler.Create)
+			synonyms.GET("/:id", handler.SynonymHandler.Get)
+			synonyms.PUT("/:id", handler.SynonymHandler.Update)
+			synonyms.DELETE("/:id", handler.SynonymHandler.Delete)
+		}
+
+		curations := admin.Group("/curations")
+		{
+			curations.GET("", handler.CurationHandler.List)
+			curations.POST("", handler.CurationHandler.Create)
+			curations.GET("/:id", handler.CurationHandler.Get)
+			curations.PUT("/:id", handler.CurationHandler.Update)
+			curations.DELETE("/:id", handler.CurationHandler.Delete)
+		}
+
+		admin.POST("/rescore", handler.RescoreHandler.Rescore)
+	}
+
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
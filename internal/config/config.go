@@ -1,9 +1,15 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+
+	"search-engine-service/internal/providers"
+	"search-engine-service/internal/scoring"
+	"search-engine-service/internal/trending"
 )
 
 type Config struct {
@@ -11,6 +17,7 @@ type Config struct {
 	Server      ServerConfig
 	Database    DatabaseConfig
 	Providers   ProvidersConfig
+	Search      SearchConfig
 	Cache       CacheConfig
 	Logging     LoggingConfig
 	Security    SecurityConfig
@@ -34,6 +41,68 @@ type ProvidersConfig struct {
 	XMLURL       string
 	Timeout      time.Duration
 	RateLimit    int
+	// Concurrency is how many providers are fetched at once
+	Concurrency int
+	// Retry and Breaker are the retry and circuit breaker policies of
+	// provider instances declaring none
+	Retry   providers.RetryPolicy
+	Breaker providers.BreakerPolicy
+	// Instances are the provider instances declared in the JSON file named
+	// by PROVIDERS_FILE, the JSON and XML providers above without one
+	Instances []providers.InstanceConfig
+}
+
+// SearchConfig holds the text relevance and ranking settings
+type SearchConfig struct {
+	BM25K1            float64
+	BM25B             float64
+	TitleWeight       float64
+	DescriptionWeight float64
+	TagsWeight        float64
+	// RelevanceWeight is the share of the BM25 relevance in the blended rank,
+	// the remainder goes to the popularity score
+	RelevanceWeight float64
+	// Terms at least this long match with one or two typos, zero disables fuzzy matching
+	FuzzyOneEditMinLength  int
+	FuzzyTwoEditsMinLength int
+	// SuggestionThreshold is the hit count below which "did you mean" is computed
+	SuggestionThreshold int
+	// SynonymReloadInterval is how long synonym rules are cached before they
+	// are reloaded from the database
+	SynonymReloadInterval time.Duration
+	// CurationReloadInterval is how long curation rules are cached before
+	// they are reloaded from the database
+	CurationReloadInterval time.Duration
+	// EmbeddingDimensions is the vector size of the built-in embedding model
+	EmbeddingDimensions int
+	// VectorCandidates is the number of nearest neighbours a vector or hybrid
+	// search retrieves, VectorMinSimilarity the cosine similarity below which
+	// they are dropped
+	VectorCandidates    int
+	VectorMinSimilarity float64
+	// RRFK is the rank constant of reciprocal-rank fusion, larger values
+	// flatten the difference between the top ranks
+	RRFK float64
+	// DuplicateMaxDistance is the largest number of differing fingerprint
+	// bits of two near-duplicate contents
+	DuplicateMaxDistance int
+	// ScoringProfiles are the compiled popularity scoring profiles, read
+	// from the JSON file named by SEARCH_SCORING_PROFILES_FILE
+	ScoringProfiles *scoring.Profiles
+	// RescoreInterval is how often the stored scores are recomputed, zero
+	// disables the background job, RescoreBatchSize the rows per batch
+	RescoreInterval  time.Duration
+	RescoreBatchSize int
+	// EngagementPriorStrength is how many average contents of its provider
+	// and type the engagement prior of a content weighs
+	EngagementPriorStrength float64
+	// TrendingWindows are the windows trending contents can be ranked by,
+	// TrendingDefaultWindow the one used when a request names none
+	TrendingWindows       []time.Duration
+	TrendingDefaultWindow time.Duration
+	// TrendingSmoothing is added to the starting engagement of trending
+	// growth rates so contents with little engagement cannot dominate
+	TrendingSmoothing float64
 }
 
 type CacheConfig struct {
@@ -51,7 +120,62 @@ type SecurityConfig struct {
 	CORSAllowedOrigins  string
 }
 
+// DefaultSearchConfig returns the default ranking settings
+func DefaultSearchConfig() SearchConfig {
+	return SearchConfig{
+		BM25K1:            1.2,
+		BM25B:             0.75,
+		TitleWeight:       3.0,
+		DescriptionWeight: 1.0,
+		TagsWeight:        2.0,
+		RelevanceWeight:   0.7,
+
+		FuzzyOneEditMinLength:  4,
+		FuzzyTwoEditsMinLength: 8,
+		SuggestionThreshold:    3,
+
+		SynonymReloadInterval:  30 * time.Second,
+		CurationReloadInterval: 30 * time.Second,
+
+		EmbeddingDimensions: 256,
+		VectorCandidates:    100,
+		VectorMinSimilarity: 0.2,
+		RRFK:                60,
+
+		DuplicateMaxDistance: 8,
+
+		ScoringProfiles:  scoring.Builtin(),
+		RescoreInterval:  time.Hour,
+		RescoreBatchSize: 500,
+
+		EngagementPriorStrength: 0.25,
+
+		TrendingWindows:       []time.Duration{time.Hour, 24 * time.Hour, 7 * 24 * time.Hour},
+		TrendingDefaultWindow: 24 * time.Hour,
+		TrendingSmoothing:     100,
+	}
+}
+
 func Load() (*Config, error) {
+	defaultSearch := DefaultSearchConfig()
+
+	scoringProfiles, err := loadScoringProfiles(getEnv("SEARCH_SCORING_PROFILES_FILE", ""), defaultSearch.ScoringProfiles)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultRetry := providers.DefaultRetryPolicy()
+	defaultBreaker := providers.DefaultBreakerPolicy()
+	jsonURL := getEnv("PROVIDER_JSON_URL", "http://localhost:3001/api/videos")
+	xmlURL := getEnv("PROVIDER_XML_URL", "http://localhost:3002/api/articles")
+	providerInstances, err := loadProviderInstances(getEnv("PROVIDERS_FILE", ""), providers.DefaultInstances(jsonURL, xmlURL), map[string]string{
+		providers.DefaultJSONName: os.Getenv("PROVIDER_JSON_URL"),
+		providers.DefaultXMLName:  os.Getenv("PROVIDER_XML_URL"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	config := &Config{
 		Environment: getEnv("ENVIRONMENT", "development"),
 		Server: ServerConfig{
@@ -66,10 +190,53 @@ func Load() (*Config, error) {
 			Name:     getEnv("DB_NAME", "search_engine"),
 		},
 		Providers: ProvidersConfig{
-			JSONURL:   getEnv("PROVIDER_JSON_URL", "http://localhost:3001/api/videos"),
-			XMLURL:    getEnv("PROVIDER_XML_URL", "http://localhost:3002/api/articles"),
-			Timeout:   getEnvAsDuration("PROVIDER_TIMEOUT", 30*time.Second),
-			RateLimit: getEnvAsInt("PROVIDER_RATE_LIMIT", 100),
+			JSONURL:     jsonURL,
+			XMLURL:      xmlURL,
+			Timeout:     getEnvAsDuration("PROVIDER_TIMEOUT", 30*time.Second),
+			RateLimit:   getEnvAsInt("PROVIDER_RATE_LIMIT", 100),
+			Concurrency: getEnvAsInt("PROVIDER_CONCURRENCY", 4),
+			Instances:   providerInstances,
+			Retry: providers.RetryPolicy{
+				MaxAttempts: getEnvAsInt("PROVIDER_RETRY_MAX_ATTEMPTS", defaultRetry.MaxAttempts),
+				BaseDelay:   providers.Duration(getEnvAsDuration("PROVIDER_RETRY_BASE_DELAY", time.Duration(defaultRetry.BaseDelay))),
+				MaxDelay:    providers.Duration(getEnvAsDuration("PROVIDER_RETRY_MAX_DELAY", time.Duration(defaultRetry.MaxDelay))),
+			},
+			Breaker: providers.BreakerPolicy{
+				FailureThreshold: getEnvAsInt("PROVIDER_BREAKER_FAILURE_THRESHOLD", defaultBreaker.FailureThreshold),
+				OpenTimeout:      providers.Duration(getEnvAsDuration("PROVIDER_BREAKER_OPEN_TIMEOUT", time.Duration(defaultBreaker.OpenTimeout))),
+			},
+		},
+		Search: SearchConfig{
+			BM25K1:            getEnvAsFloat("SEARCH_BM25_K1", defaultSearch.BM25K1),
+			BM25B:             getEnvAsFloat("SEARCH_BM25_B", defaultSearch.BM25B),
+			TitleWeight:       getEnvAsFloat("SEARCH_TITLE_WEIGHT", defaultSearch.TitleWeight),
+			DescriptionWeight: getEnvAsFloat("SEARCH_DESCRIPTION_WEIGHT", defaultSearch.DescriptionWeight),
+			TagsWeight:        getEnvAsFloat("SEARCH_TAGS_WEIGHT", defaultSearch.TagsWeight),
+			RelevanceWeight:   getEnvAsFloat("SEARCH_RELEVANCE_WEIGHT", defaultSearch.RelevanceWeight),
+
+			FuzzyOneEditMinLength:  getEnvAsInt("SEARCH_FUZZY_ONE_EDIT_MIN_LENGTH", defaultSearch.FuzzyOneEditMinLength),
+			FuzzyTwoEditsMinLength: getEnvAsInt("SEARCH_FUZZY_TWO_EDITS_MIN_LENGTH", defaultSearch.FuzzyTwoEditsMinLength),
+			SuggestionThreshold:    getEnvAsInt("SEARCH_SUGGESTION_THRESHOLD", defaultSearch.SuggestionThreshold),
+
+			SynonymReloadInterval:  getEnvAsDuration("SEARCH_SYNONYM_RELOAD_INTERVAL", defaultSearch.SynonymReloadInterval),
+			CurationReloadInterval: getEnvAsDuration("SEARCH_CURATION_RELOAD_INTERVAL", defaultSearch.CurationReloadInterval),
+
+			EmbeddingDimensions: getEnvAsInt("SEARCH_EMBEDDING_DIMENSIONS", defaultSearch.EmbeddingDimensions),
+			VectorCandidates:    getEnvAsInt("SEARCH_VECTOR_CANDIDATES", defaultSearch.VectorCandidates),
+			VectorMinSimilarity: getEnvAsFloat("SEARCH_VECTOR_MIN_SIMILARITY", defaultSearch.VectorMinSimilarity),
+			RRFK:                getEnvAsFloat("SEARCH_RRF_K", defaultSearch.RRFK),
+
+			DuplicateMaxDistance: getEnvAsInt("SEARCH_DUPLICATE_MAX_DISTANCE", defaultSearch.DuplicateMaxDistance),
+
+			ScoringProfiles:  scoringProfiles,
+			RescoreInterval:  getEnvAsDuration("SEARCH_RESCORE_INTERVAL", defaultSearch.RescoreInterval),
+			RescoreBatchSize: getEnvAsInt("SEARCH_RESCORE_BATCH_SIZE", defaultSearch.RescoreBatchSize),
+
+			EngagementPriorStrength: getEnvAsFloat("SEARCH_ENGAGEMENT_PRIOR_STRENGTH", defaultSearch.EngagementPriorStrength),
+
+			TrendingWindows:       getEnvAsWindows("SEARCH_TRENDING_WINDOWS", defaultSearch.TrendingWindows),
+			TrendingDefaultWindow: getEnvAsWindow("SEARCH_TRENDING_DEFAULT_WINDOW", defaultSearch.TrendingDefaultWindow),
+			TrendingSmoothing:     getEnvAsFloat("SEARCH_TRENDING_SMOOTHING", defaultSearch.TrendingSmoothing),
 		},
 		Cache: CacheConfig{
 			TTL:     getEnvAsDuration("CACHE_TTL", 5*time.Minute),
@@ -88,6 +255,45 @@ func Load() (*Config, error) {
 	return config, nil
 }
 
+// loadScoringProfiles reads and validates the scoring profiles file, the
+// built-in profiles apply without one
+func loadScoringProfiles(path string, defaultValue *scoring.Profiles) (*scoring.Profiles, error) {
+	if path == "" {
+		return defaultValue, nil
+	}
+
+	profilesConfig, err := scoring.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := scoring.CompileConfig(profilesConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scoring profiles %s: %w", path, err)
+	}
+	return profiles, nil
+}
+
+// loadProviderInstances reads the provider instances file, the default
+// instances apply without one. The non-empty URLs of urls, by instance name,
+// override the URLs of the file so an environment can repoint the default
+// providers without its own file.
+func loadProviderInstances(path string, defaultValue []providers.InstanceConfig, urls map[string]string) ([]providers.InstanceConfig, error) {
+	if path == "" {
+		return defaultValue, nil
+	}
+
+	instances, err := providers.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	for i := range instances {
+		if url := urls[instances[i].Name]; url != "" {
+			instances[i].URL = url
+		}
+	}
+	return instances, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -104,6 +310,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -111,4 +326,32 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 		}
 	}
 	return defaultValue
+}
+
+// getEnvAsWindow reads a trending window such as 24h or 7d
+func getEnvAsWindow(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if window, err := trending.ParseWindow(value); err == nil {
+			return window
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsWindows reads a comma-separated list of trending windows
+func getEnvAsWindows(key string, defaultValue []time.Duration) []time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var windows []time.Duration
+	for _, part := range strings.Split(value, ",") {
+		window, err := trending.ParseWindow(part)
+		if err != nil {
+			return defaultValue
+		}
+		windows = append(windows, window)
+	}
+	return windows
 } 
\ No newline at end of file
@@ -0,0 +1,225 @@
+// Package curation applies the editorial curation rules to search results.
+//
+// A rule triggers on a query, exactly or by pattern, and pins contents to
+// fixed positions, multiplies the scores of the contents it boosts or buries,
+// or hides contents from the results.
+package curation
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	"search-engine-service/internal/database/models"
+)
+
+// pattern is a compiled pattern rule
+type pattern struct {
+	re   *regexp.Regexp
+	rule *models.CurationRule
+}
+
+// Set is an immutable compiled set of rules, safe for concurrent use
+type Set struct {
+	exact    map[string][]*models.CurationRule
+	patterns []pattern
+	size     int
+}
+
+// Compile builds a rule set, invalid rules are skipped
+func Compile(rules []models.CurationRule) *Set {
+	s := &Set{exact: make(map[string][]*models.CurationRule)}
+
+	for i := range rules {
+		rule := rules[i]
+		if rule.Validate() != nil {
+			continue
+		}
+
+		switch rule.Match {
+		case models.CurationExact:
+			key := models.NormalizeQuery(rule.Query)
+			s.exact[key] = append(s.exact[key], &rule)
+		case models.CurationPattern:
+			s.patterns = append(s.patterns, pattern{re: regexp.MustCompile(`(?i)` + rule.Query), rule: &rule})
+		}
+		s.size++
+	}
+	return s
+}
+
+// Len returns the number of compiled rules
+func (s *Set) Len() int {
+	if s == nil {
+		return 0
+	}
+	return s.size
+}
+
+// Match returns the plan of the rules active at the given time that trigger
+// on the query
+func (s *Set) Match(q string, now time.Time) *Plan {
+	q = models.NormalizeQuery(q)
+	if s == nil || q == "" {
+		return newPlan(nil)
+	}
+
+	var rules []*models.CurationRule
+	for _, rule := range s.exact[q] {
+		if rule.Active(now) {
+			rules = append(rules, rule)
+		}
+	}
+	for _, p := range s.patterns {
+		if p.rule.Active(now) && p.re.MatchString(q) {
+			rules = append(rules, p.rule)
+		}
+	}
+	return newPlan(rules)
+}
+
+// Pin is a content pinned by a rule
+type Pin struct {
+	ID       uint
+	Position int
+	Rule     uint
+}
+
+// Boost is a boost of a rule applied to a content
+type Boost struct {
+	models.CurationBoost
+	Rule uint
+}
+
+// Plan is the combined actions of the rules triggered by a query. Rules
+// apply in ID order: the first rule hiding a content wins, and a content is
+// pinned by the first rule pinning it.
+type Plan struct {
+	// Rules lists the IDs of the triggered rules
+	Rules []uint
+	// Pins is ordered by position, ties by rule
+	Pins []Pin
+
+	hidden map[uint]uint
+	boosts []Boost
+}
+
+// newPlan combines the actions of the rules
+func newPlan(rules []*models.CurationRule) *Plan {
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+
+	p := &Plan{hidden: make(map[uint]uint)}
+	pinned := make(map[uint]bool)
+	for _, rule := range rules {
+		p.Rules = append(p.Rules, rule.ID)
+		for _, id := range rule.Hidden {
+			if _, ok := p.hidden[id]; !ok {
+				p.hidden[id] = rule.ID
+			}
+		}
+		for _, boost := range rule.Boosts {
+			p.boosts = append(p.boosts, Boost{CurationBoost: boost, Rule: rule.ID})
+		}
+		for _, pin := range rule.Pins {
+			if !pinned[pin.ID] {
+				pinned[pin.ID] = true
+				p.Pins = append(p.Pins, Pin{ID: pin.ID, Position: pin.Position, Rule: rule.ID})
+			}
+		}
+	}
+
+	sort.SliceStable(p.Pins, func(i, j int) bool { return p.Pins[i].Position < p.Pins[j].Position })
+	return p
+}
+
+// Empty reports whether no rule triggered
+func (p *Plan) Empty() bool {
+	return len(p.Rules) == 0
+}
+
+// Hidden returns the rule hiding a content
+func (p *Plan) Hidden(id uint) (rule uint, ok bool) {
+	rule, ok = p.hidden[id]
+	return rule, ok
+}
+
+// HiddenIDs returns the hidden contents in ascending order
+func (p *Plan) HiddenIDs() []uint {
+	ids := make([]uint, 0, len(p.hidden))
+	for id := range p.hidden {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Boosts returns the boosts applying to a content
+func (p *Plan) Boosts(content *models.Content) []Boost {
+	var boosts []Boost
+	for _, boost := range p.boosts {
+		if boost.Matches(content) {
+			boosts = append(boosts, boost)
+		}
+	}
+	return boosts
+}
+
+// Factor returns the combined factor of the boosts
+func Factor(boosts []Boost) float64 {
+	factor := 1.0
+	for _, boost := range boosts {
+		factor *= boost.Factor
+	}
+	return factor
+}
+
+// Pinned returns the pin of a content
+func (p *Plan) Pinned(id uint) (Pin, bool) {
+	for _, pin := range p.Pins {
+		if pin.ID == id {
+			return pin, true
+		}
+	}
+	return Pin{}, false
+}
+
+// Place moves the pinned hits to their positions. Pinned contents missing
+// from the hits are taken from extra, pins of contents found in neither or
+// hidden are skipped. A position past the end places the hit last, a pin
+// whose position is taken goes right after the hit holding it.
+func (p *Plan) Place(hits, extra []models.SearchHit) []models.SearchHit {
+	if len(p.Pins) == 0 {
+		return hits
+	}
+
+	pinned := make(map[uint]models.SearchHit, len(p.Pins))
+	for _, list := range [][]models.SearchHit{extra, hits} {
+		for _, hit := range list {
+			if _, ok := p.Pinned(hit.ID); ok {
+				hit.Pinned = true
+				pinned[hit.ID] = hit
+			}
+		}
+	}
+
+	placed := make([]models.SearchHit, 0, len(hits)+len(extra))
+	for _, hit := range hits {
+		if !pinned[hit.ID].Pinned {
+			placed = append(placed, hit)
+		}
+	}
+	last := -1
+	for _, pin := range p.Pins {
+		hit, ok := pinned[pin.ID]
+		if _, hidden := p.hidden[pin.ID]; !ok || hidden {
+			continue
+		}
+		position := max(pin.Position-1, last+1)
+		position = min(position, len(placed))
+		last = position
+		placed = append(placed, models.SearchHit{})
+		copy(placed[position+1:], placed[position:])
+		placed[position] = hit
+	}
+	return placed
+}
@@ -71,6 +71,9 @@ func Init(cfg config.DatabaseConfig) (*gorm.DB, error) {
 func AutoMigrate(db *gorm.DB) error {
 	return db.AutoMigrate(
 		&models.Content{},
+		&models.SynonymRule{},
+		&models.CurationRule{},
+		&models.MetricSnapshot{},
 	)
 }
 
@@ -3,6 +3,8 @@ package models
 import (
 	"time"
 
+	"search-engine-service/internal/query"
+
 	"gorm.io/gorm"
 )
 
@@ -40,6 +42,20 @@ type Content struct {
 	EngagementScore float64 `json:"engagement_score" gorm:"type:decimal(10,4);default:0"`
 	FinalScore     float64 `json:"final_score" gorm:"type:decimal(10,4);default:0"`
 	
+	// ScoringProfile and ScoringVersion identify the profile the scores were
+	// calculated with
+	ScoringProfile string `json:"scoring_profile" gorm:"size:50"`
+	ScoringVersion int    `json:"scoring_version" gorm:"index;default:0"`
+	
+	// Semantic search fields
+	Embedding      Vector `json:"-" gorm:"type:blob"`
+	EmbeddingModel string `json:"-" gorm:"size:50"`
+	
+	// Near-duplicate detection, ClusterID is the ID of the canonical content
+	// of the duplicates cluster, the content's own ID when it has none
+	Fingerprint uint64 `json:"-" gorm:"default:0"`
+	ClusterID   uint   `json:"cluster_id" gorm:"index;default:0"`
+	
 	// Metadata
 	Tags        string    `json:"tags" gorm:"type:text"`
 	Language    string    `json:"language" gorm:"size:10;default:'en'"`
@@ -54,15 +70,64 @@ func (Content) TableName() string {
 	return "contents"
 }
 
+// SearchHit represents a single search result together with its ranking scores
+type SearchHit struct {
+	Content
+	RelevanceScore  float64 `json:"relevance_score"`
+	PopularityScore float64 `json:"popularity_score"`
+	Score           float64 `json:"score"`
+
+	// Highlights holds the highlighted fragments per field when requested
+	Highlights map[string][]string `json:"highlights,omitempty"`
+
+	// Duplicates counts the other matching contents of the hit's cluster in a
+	// collapsed search, AlternateURLs lists their URLs
+	Duplicates    int      `json:"duplicates,omitempty"`
+	AlternateURLs []string `json:"alternate_urls,omitempty"`
+
+	// Pinned reports the hit was placed by a curation rule
+	Pinned bool `json:"pinned,omitempty"`
+
+	// Explanation explains the score of the hit when requested
+	Explanation *Explanation `json:"explanation,omitempty"`
+}
+
+// NewSearchHits wraps contents into search hits without relevance information
+func NewSearchHits(contents []Content) []SearchHit {
+	hits := make([]SearchHit, len(contents))
+	for i := range contents {
+		hits[i] = SearchHit{Content: contents[i]}
+	}
+	return hits
+}
+
 // SearchResult represents a search result with pagination
 type SearchResult struct {
-	Contents    []Content `json:"contents"`
-	Total       int64     `json:"total"`
-	Page        int       `json:"page"`
-	Limit       int       `json:"limit"`
-	TotalPages  int       `json:"total_pages"`
-	HasNext     bool      `json:"has_next"`
-	HasPrevious bool      `json:"has_previous"`
+	Contents    []SearchHit `json:"contents"`
+	Total       int64       `json:"total"`
+	Page        int         `json:"page"`
+	Limit       int         `json:"limit"`
+	TotalPages  int         `json:"total_pages"`
+	HasNext     bool        `json:"has_next"`
+	HasPrevious bool        `json:"has_previous"`
+	NextCursor  string      `json:"next_cursor,omitempty"`
+	PrevCursor  string      `json:"prev_cursor,omitempty"`
+	DidYouMean  string      `json:"did_you_mean,omitempty"`
+	Facets      *Facets     `json:"facets,omitempty"`
+
+	// Rewrites lists the synonym rules applied to the query
+	Rewrites []QueryRewrite `json:"rewrites,omitempty"`
+}
+
+// EngagementStats sums the engagement of the contents of a provider and type
+type EngagementStats struct {
+	Provider    string
+	Type        ContentType
+	Contents    int64
+	Views       int64
+	Likes       int64
+	Reactions   int64
+	ReadingTime int64
 }
 
 // ContentRepository interface defines the methods for content operations
@@ -72,8 +137,17 @@ type ContentRepository interface {
 	Delete(id uint) error
 	FindByID(id uint) (*Content, error)
 	FindByProviderID(provider, providerID string) (*Content, error)
-	Search(query string, contentType ContentType, page, limit int) (*SearchResult, error)
+	Search(q query.Node, filters *SearchFilters, page PageRequest) (*SearchResult, error)
+	FindByIDs(ids []uint, filters *SearchFilters) ([]Content, error)
+	FindRankFields(ids []uint, filters *SearchFilters) ([]Content, error)
+	FindFacetFields(q query.Node, filters *SearchFilters) ([]Content, error)
+	FindAfterID(afterID uint, limit int) ([]Content, error)
 	GetPopular(limit int) ([]Content, error)
-	UpdateScores() error
+	UpdateScores(contents []Content) error
+	FindEngagementStats() ([]EngagementStats, error)
+	UpdateEmbeddings(contents []Content) error
+	FindFingerprints() ([]Content, error)
+	UpdateClusters(contents []Content) error
+	FindDuplicates(q query.Node, filters *SearchFilters, clusterIDs []uint) ([]Content, error)
 	BulkUpsert(contents []Content) error
 } 
\ No newline at end of file
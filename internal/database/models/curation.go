@@ -0,0 +1,227 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CurationMatchType is how a curation rule is triggered by a query
+type CurationMatchType string
+
+const (
+	// CurationExact triggers on the query itself, ignoring case and spacing
+	CurationExact CurationMatchType = "exact"
+	// CurationPattern triggers on queries matching a case-insensitive
+	// regular expression
+	CurationPattern CurationMatchType = "pattern"
+)
+
+// BoostTarget is what a curation boost applies to
+type BoostTarget string
+
+const (
+	BoostTargetID       BoostTarget = "id"
+	BoostTargetProvider BoostTarget = "provider"
+	BoostTargetTag      BoostTarget = "tag"
+)
+
+// CurationPin places a content at a fixed 1-based position of the results
+type CurationPin struct {
+	ID       uint `json:"id"`
+	Position int  `json:"position"`
+}
+
+// CurationBoost multiplies the score of the contents it targets, a factor
+// below 1 buries them
+type CurationBoost struct {
+	Target BoostTarget `json:"target"`
+	Value  string      `json:"value"`
+	Factor float64     `json:"factor"`
+}
+
+// Matches reports whether the boost targets the content
+func (b *CurationBoost) Matches(content *Content) bool {
+	switch b.Target {
+	case BoostTargetID:
+		return b.Value == strconv.FormatUint(uint64(content.ID), 10)
+	case BoostTargetProvider:
+		return b.Value == content.Provider
+	case BoostTargetTag:
+		return hasTag(content.Tags, b.Value)
+	default:
+		return false
+	}
+}
+
+// CurationPins is the pins of a rule, stored as JSON
+type CurationPins []CurationPin
+
+// CurationBoosts is the boosts of a rule, stored as JSON
+type CurationBoosts []CurationBoost
+
+// IDList is a list of content IDs, stored as JSON
+type IDList []uint
+
+// Value encodes the pins for the database
+func (p CurationPins) Value() (driver.Value, error) {
+	return jsonValue(p)
+}
+
+// Scan decodes pins read from the database
+func (p *CurationPins) Scan(value interface{}) error {
+	return scanJSON(value, p)
+}
+
+// Value encodes the boosts for the database
+func (b CurationBoosts) Value() (driver.Value, error) {
+	return jsonValue(b)
+}
+
+// Scan decodes boosts read from the database
+func (b *CurationBoosts) Scan(value interface{}) error {
+	return scanJSON(value, b)
+}
+
+// Value encodes the IDs for the database
+func (l IDList) Value() (driver.Value, error) {
+	return jsonValue(l)
+}
+
+// Scan decodes IDs read from the database
+func (l *IDList) Scan(value interface{}) error {
+	return scanJSON(value, l)
+}
+
+// jsonValue encodes a column value as JSON
+func jsonValue(v interface{}) (driver.Value, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(buf), nil
+}
+
+// scanJSON decodes a JSON column value read from the database
+func scanJSON(value interface{}, dest interface{}) error {
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(value, dest)
+	case string:
+		return json.Unmarshal([]byte(value), dest)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", value, dest)
+	}
+}
+
+// CurationRule pins, boosts, buries or hides contents in the results of the
+// queries it triggers on, managed through the admin API
+type CurationRule struct {
+	ID    uint              `json:"id" gorm:"primaryKey"`
+	Name  string            `json:"name" gorm:"size:255"`
+	Match CurationMatchType `json:"match" gorm:"size:20;not null"`
+	Query string            `json:"query" gorm:"size:255;not null"`
+
+	Pins   CurationPins   `json:"pins" gorm:"type:text"`
+	Boosts CurationBoosts `json:"boosts" gorm:"type:text"`
+	Hidden IDList         `json:"hidden" gorm:"type:text"`
+
+	// StartsAt and EndsAt optionally limit when the rule applies, EndsAt is
+	// exclusive
+	StartsAt  *time.Time `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for CurationRule
+func (CurationRule) TableName() string {
+	return "curation_rules"
+}
+
+// Active reports whether the rule applies at the given time
+func (r *CurationRule) Active(now time.Time) bool {
+	if r.StartsAt != nil && now.Before(*r.StartsAt) {
+		return false
+	}
+	return r.EndsAt == nil || now.Before(*r.EndsAt)
+}
+
+// Validate checks the rule has a valid trigger and at least one valid action
+func (r *CurationRule) Validate() error {
+	switch r.Match {
+	case CurationExact:
+		if NormalizeQuery(r.Query) == "" {
+			return &ParameterError{Key: "query", Message: "is required"}
+		}
+	case CurationPattern:
+		if strings.TrimSpace(r.Query) == "" {
+			return &ParameterError{Key: "query", Message: "is required"}
+		}
+		if _, err := regexp.Compile(r.Query); err != nil {
+			return &ParameterError{Key: "query", Message: "must be a valid regular expression"}
+		}
+	default:
+		return &ParameterError{Key: "match", Message: "must be 'exact' or 'pattern'"}
+	}
+
+	if len(r.Pins) == 0 && len(r.Boosts) == 0 && len(r.Hidden) == 0 {
+		return &ParameterError{Key: "pins", Message: "a rule needs at least one pin, boost or hidden content"}
+	}
+	for _, pin := range r.Pins {
+		if pin.ID == 0 {
+			return &ParameterError{Key: "pins", Message: "must have a content id"}
+		}
+		if pin.Position < 1 {
+			return &ParameterError{Key: "pins", Message: "position must be at least 1"}
+		}
+	}
+	for _, boost := range r.Boosts {
+		switch boost.Target {
+		case BoostTargetID:
+			if id, err := strconv.ParseUint(boost.Value, 10, 32); err != nil || id == 0 {
+				return &ParameterError{Key: "boosts", Message: "id boosts need a content id value"}
+			}
+		case BoostTargetProvider, BoostTargetTag:
+			if strings.TrimSpace(boost.Value) == "" {
+				return &ParameterError{Key: "boosts", Message: "must have a value"}
+			}
+		default:
+			return &ParameterError{Key: "boosts", Message: "target must be 'id', 'provider' or 'tag'"}
+		}
+		if boost.Factor <= 0 {
+			return &ParameterError{Key: "boosts", Message: "factor must be positive"}
+		}
+	}
+	for _, id := range r.Hidden {
+		if id == 0 {
+			return &ParameterError{Key: "hidden", Message: "must be content ids"}
+		}
+	}
+
+	if r.StartsAt != nil && r.EndsAt != nil && !r.EndsAt.After(*r.StartsAt) {
+		return &ParameterError{Key: "ends_at", Message: "must be after starts_at"}
+	}
+	return nil
+}
+
+// NormalizeQuery lowercases a query and collapses its whitespace, the form
+// curation triggers are matched against
+func NormalizeQuery(q string) string {
+	return strings.Join(strings.Fields(strings.ToLower(q)), " ")
+}
+
+// CurationRepository interface defines the methods for curation rule operations
+type CurationRepository interface {
+	Create(rule *CurationRule) error
+	Update(rule *CurationRule) error
+	Delete(id uint) error
+	FindByID(id uint) (*CurationRule, error)
+	FindAll() ([]CurationRule, error)
+}
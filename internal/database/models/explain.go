@@ -0,0 +1,123 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Explanation is a node of the tree explaining how a score was computed
+type Explanation struct {
+	Value       float64       `json:"value"`
+	Description string        `json:"description"`
+	Details     []Explanation `json:"details,omitempty"`
+}
+
+// Explain creates an explanation node
+func Explain(value float64, description string, details ...Explanation) Explanation {
+	return Explanation{Value: value, Description: description, Details: details}
+}
+
+// SearchExplanation explains the rank of a content for a search
+type SearchExplanation struct {
+	ID uint `json:"id"`
+	// Matched reports whether the content is among the results, Rank is its
+	// 1-based position among all of them
+	Matched  bool           `json:"matched"`
+	Rank     int            `json:"rank,omitempty"`
+	Total    int64          `json:"total"`
+	Rewrites []QueryRewrite `json:"rewrites,omitempty"`
+	// Curation lists the IDs of the curation rules triggered by the query
+	Curation    []uint        `json:"curation_rules,omitempty"`
+	Filters     []FilterCheck `json:"filters"`
+	Explanation Explanation   `json:"explanation"`
+}
+
+// FilterCheck reports whether a content passes an active filter
+type FilterCheck struct {
+	Filter string      `json:"filter"`
+	Value  interface{} `json:"value"`
+	Actual interface{} `json:"actual"`
+	Passed bool        `json:"passed"`
+}
+
+// Check evaluates the active filters against a content the way the database
+// applies them
+func (f *SearchFilters) Check(content *Content, now time.Time) []FilterCheck {
+	checks := []FilterCheck{}
+	add := func(filter string, value, actual interface{}, passed bool) {
+		checks = append(checks, FilterCheck{Filter: filter, Value: value, Actual: actual, Passed: passed})
+	}
+
+	if f.Type != "" && f.Type != "all" {
+		add("type", f.Type, content.Type, content.Type == f.Type)
+	}
+	if f.Provider != "" {
+		add("provider", f.Provider, content.Provider, content.Provider == f.Provider)
+	}
+	if f.Language != "" {
+		add("language", f.Language, content.Language, content.Language == f.Language)
+	}
+	if f.Published != "" {
+		if after, before, ok := PublishedRange(f.Published, now); ok {
+			passed := (after.IsZero() || !content.PublishedAt.Before(after)) &&
+				(before.IsZero() || content.PublishedAt.Before(before))
+			add("published", f.Published, content.PublishedAt, passed)
+		}
+	}
+	if f.PublishedAfter != nil {
+		add("published_after", *f.PublishedAfter, content.PublishedAt, !content.PublishedAt.Before(*f.PublishedAfter))
+	}
+	if f.PublishedBefore != nil {
+		add("published_before", *f.PublishedBefore, content.PublishedAt, content.PublishedAt.Before(*f.PublishedBefore))
+	}
+	if f.MinViews > 0 {
+		add("min_views", f.MinViews, content.Views, content.Views >= f.MinViews)
+	}
+	if f.MinReactions > 0 {
+		add("min_reactions", f.MinReactions, content.Reactions, content.Reactions >= f.MinReactions)
+	}
+	if len(f.DurationBetween) == 2 {
+		add("duration_between", f.DurationBetween, content.Duration,
+			content.Duration >= f.DurationBetween[0] && content.Duration <= f.DurationBetween[1])
+	}
+	if len(f.ReadingTimeBetween) == 2 {
+		add("reading_time_between", f.ReadingTimeBetween, content.ReadingTime,
+			content.ReadingTime >= f.ReadingTimeBetween[0] && content.ReadingTime <= f.ReadingTimeBetween[1])
+	}
+	if len(f.TagsAny) > 0 {
+		passed := false
+		for _, tag := range f.TagsAny {
+			passed = passed || hasTag(content.Tags, tag)
+		}
+		add("tags_any", f.TagsAny, content.Tags, passed)
+	}
+	if len(f.TagsAll) > 0 {
+		passed := true
+		for _, tag := range f.TagsAll {
+			passed = passed && hasTag(content.Tags, tag)
+		}
+		add("tags_all", f.TagsAll, content.Tags, passed)
+	}
+	if f.MinScore > 0 {
+		add("min_score", f.MinScore, content.FinalScore, content.FinalScore >= f.MinScore)
+	}
+	return checks
+}
+
+// hasTag reports whether a comma separated tag list carries a tag, ignoring
+// case and the spaces around the tags
+func hasTag(tags, tag string) bool {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	for _, t := range strings.Split(strings.ReplaceAll(strings.ToLower(tags), " ", ""), ",") {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// String describes a failed check
+func (c FilterCheck) String() string {
+	return fmt.Sprintf("%s %v, got %v", c.Filter, c.Value, c.Actual)
+}
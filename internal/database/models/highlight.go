@@ -0,0 +1,52 @@
+package models
+
+// Highlight option limits
+const (
+	MinFragmentSize = 20
+	MaxFragmentSize = 1000
+	MaxFragments    = 10
+)
+
+// HighlightOptions controls the snippets returned for each search hit
+type HighlightOptions struct {
+	PreTag       string `json:"pre_tag"`
+	PostTag      string `json:"post_tag"`
+	FragmentSize int    `json:"fragment_size"` // approximate fragment length in bytes
+	MaxFragments int    `json:"max_fragments"` // fragments per field
+}
+
+// DefaultHighlightOptions returns the default highlighting options
+func DefaultHighlightOptions() HighlightOptions {
+	return HighlightOptions{
+		PreTag:       "<mark>",
+		PostTag:      "</mark>",
+		FragmentSize: 150,
+		MaxFragments: 3,
+	}
+}
+
+// WithDefaults returns the options with unset values taken from the defaults
+func (o HighlightOptions) WithDefaults() HighlightOptions {
+	defaults := DefaultHighlightOptions()
+	if o.PreTag == "" && o.PostTag == "" {
+		o.PreTag, o.PostTag = defaults.PreTag, defaults.PostTag
+	}
+	if o.FragmentSize == 0 {
+		o.FragmentSize = defaults.FragmentSize
+	}
+	if o.MaxFragments == 0 {
+		o.MaxFragments = defaults.MaxFragments
+	}
+	return o
+}
+
+// Validate checks the fragment limits
+func (o *HighlightOptions) Validate() error {
+	if o.FragmentSize < MinFragmentSize || o.FragmentSize > MaxFragmentSize {
+		return &ParameterError{Key: "fragment_size", Message: "must be between 20 and 1000"}
+	}
+	if o.MaxFragments < 1 || o.MaxFragments > MaxFragments {
+		return &ParameterError{Key: "max_fragments", Message: "must be between 1 and 10"}
+	}
+	return nil
+}
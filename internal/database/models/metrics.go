@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// MetricSnapshot records the engagement counters of a content at a refresh,
+// the history trending is computed from
+type MetricSnapshot struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ContentID  uint      `json:"content_id" gorm:"not null;index:idx_snapshot_content_time"`
+	Views      int       `json:"views"`
+	Likes      int       `json:"likes"`
+	Reactions  int       `json:"reactions"`
+	CapturedAt time.Time `json:"captured_at" gorm:"not null;index:idx_snapshot_content_time;index"`
+}
+
+// TableName specifies the table name for MetricSnapshot
+func (MetricSnapshot) TableName() string {
+	return "metric_snapshots"
+}
+
+// Engagement is the total engagement the snapshot counts
+func (s *MetricSnapshot) Engagement() float64 {
+	return float64(s.Views + s.Likes + s.Reactions)
+}
+
+// TrendMetrics describes how fast the engagement of a content grew over a
+// window, per hour
+type TrendMetrics struct {
+	// Delta is the engagement gained over the window
+	Delta float64 `json:"delta"`
+	// Velocity is the engagement gained per hour
+	Velocity float64 `json:"velocity"`
+	// Acceleration is the change of the velocity from the previous window,
+	// per hour
+	Acceleration float64 `json:"acceleration"`
+	// GrowthRate is the engagement gained relative to the engagement at the
+	// start of the window
+	GrowthRate float64 `json:"growth_rate"`
+}
+
+// TrendingHit is a trending content with its trend in every window
+type TrendingHit struct {
+	Content
+	Trend map[string]TrendMetrics `json:"trend"`
+}
+
+// TrendingResult lists the trending contents of a window
+type TrendingResult struct {
+	Window   string        `json:"window"`
+	Contents []TrendingHit `json:"contents"`
+}
+
+// TrendingRequest represents a trending content request
+type TrendingRequest struct {
+	// Window names the window trending contents are ranked by, empty for the
+	// default window
+	Window  string
+	Filters SearchFilters
+	Limit   int
+}
+
+// MetricsRepository interface defines the methods for metric snapshot operations
+type MetricsRepository interface {
+	CreateBatch(snapshots []MetricSnapshot) error
+	FindSince(since time.Time, filters *SearchFilters) ([]MetricSnapshot, error)
+	FindForContents(ids []uint, since time.Time) ([]MetricSnapshot, error)
+	DeleteBefore(before time.Time) (int64, error)
+}
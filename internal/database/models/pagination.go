@@ -0,0 +1,122 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SortField selects the order of search results
+type SortField string
+
+const (
+	SortRelevance SortField = "relevance"
+	SortScore     SortField = "score"
+	SortNewest    SortField = "newest"
+	SortViews     SortField = "views"
+	SortReactions SortField = "reactions"
+)
+
+// SortOrder is the direction of a sort
+type SortOrder string
+
+const (
+	OrderAsc  SortOrder = "asc"
+	OrderDesc SortOrder = "desc"
+)
+
+// ParameterError reports an invalid search parameter
+type ParameterError struct {
+	Key     string
+	Message string
+}
+
+func (e *ParameterError) Error() string {
+	return fmt.Sprintf("invalid parameter '%s': %s", e.Key, e.Message)
+}
+
+// ParseSort validates the requested sort field and order, applying the
+// defaults for empty values
+func ParseSort(field, order string, hasQuery bool) (SortField, SortOrder, error) {
+	sortField := SortField(field)
+	switch sortField {
+	case "":
+		// Relevance needs a query, otherwise rank by stored score
+		sortField = SortScore
+		if hasQuery {
+			sortField = SortRelevance
+		}
+	case SortRelevance, SortScore, SortNewest, SortViews, SortReactions:
+	default:
+		return "", "", &ParameterError{Key: "sort", Message: "must be 'relevance', 'score', 'newest', 'views' or 'reactions'"}
+	}
+
+	sortOrder := SortOrder(order)
+	switch sortOrder {
+	case "":
+		sortOrder = OrderDesc
+	case OrderAsc, OrderDesc:
+	default:
+		return "", "", &ParameterError{Key: "order", Message: "must be 'asc' or 'desc'"}
+	}
+
+	return sortField, sortOrder, nil
+}
+
+// Cursor marks a position in a sorted result list by the sort key and ID of
+// a result, Backward cursors page towards the start of the list
+type Cursor struct {
+	Sort     SortField `json:"s"`
+	Order    SortOrder `json:"o"`
+	Value    float64   `json:"v,omitempty"`
+	Time     time.Time `json:"t"`
+	ID       uint      `json:"id"`
+	Backward bool      `json:"b,omitempty"`
+	// ScoredAt is the time the scores of a relevance ranking were computed
+	// at, later pages are scored at the same time so the ranking holds still
+	ScoredAt *time.Time `json:"n,omitempty"`
+}
+
+// Encode returns the opaque token of the cursor
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor token and checks it belongs to the given sort
+func DecodeCursor(token string, sort SortField, order SortOrder) (*Cursor, error) {
+	invalid := &ParameterError{Key: "cursor", Message: "malformed cursor"}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalid
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == 0 {
+		return nil, invalid
+	}
+	if cursor.Sort != sort || cursor.Order != order {
+		return nil, &ParameterError{Key: "cursor", Message: "cursor was issued for a different sort"}
+	}
+	return &cursor, nil
+}
+
+// PageRequest selects a page of sorted results, either by page number or,
+// when After is set, by keyset from a cursor
+type PageRequest struct {
+	Sort  SortField
+	Order SortOrder
+	Page  int
+	Limit int
+	After *Cursor
+}
+
+// ScoreTime returns the time the results are scored at: the time of the
+// cursor continuing a relevance ranking, otherwise now
+func (p PageRequest) ScoreTime(now time.Time) time.Time {
+	if p.After != nil && p.After.ScoredAt != nil {
+		return *p.After.ScoredAt
+	}
+	return now
+}
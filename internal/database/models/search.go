@@ -0,0 +1,233 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SearchRequest holds the parameters of a search, a non-empty Cursor takes
+// precedence over Page, a nil Highlight disables highlighting and an empty
+// Profile selects the default scoring profile
+type SearchRequest struct {
+	Query     string
+	Mode      SearchMode
+	Profile   string
+	Filters   SearchFilters
+	Sort      string
+	Order     string
+	Cursor    string
+	Collapse  bool
+	Explain   bool
+	Page      int
+	Limit     int
+	Highlight *HighlightOptions
+}
+
+// SearchMode selects how a query is matched
+type SearchMode string
+
+const (
+	// SearchModeKeyword matches the query terms against the inverted index
+	SearchModeKeyword SearchMode = "keyword"
+	// SearchModeVector finds the contents nearest to the query embedding
+	SearchModeVector SearchMode = "vector"
+	// SearchModeHybrid fuses the keyword and vector rankings
+	SearchModeHybrid SearchMode = "hybrid"
+)
+
+// ParseSearchMode validates a search mode, defaulting to keyword
+func ParseSearchMode(mode string) (SearchMode, error) {
+	switch searchMode := SearchMode(mode); searchMode {
+	case "":
+		return SearchModeKeyword, nil
+	case SearchModeKeyword, SearchModeVector, SearchModeHybrid:
+		return searchMode, nil
+	default:
+		return "", &ParameterError{Key: "mode", Message: "must be 'keyword', 'vector' or 'hybrid'"}
+	}
+}
+
+// SearchFilters restricts a search to matching contents
+type SearchFilters struct {
+	Type               ContentType `json:"type"`
+	Provider           string      `json:"provider"`
+	Language           string      `json:"language"`
+	Published          string      `json:"published"`
+	PublishedAfter     *time.Time  `json:"published_after"`
+	PublishedBefore    *time.Time  `json:"published_before"`
+	MinViews           int         `json:"min_views"`
+	MinReactions       int         `json:"min_reactions"`
+	DurationBetween    []int       `json:"duration_between"`
+	ReadingTimeBetween []int       `json:"reading_time_between"`
+	TagsAny            []string    `json:"tags_any"`
+	TagsAll            []string    `json:"tags_all"`
+	MinScore           float64     `json:"min_score"`
+
+	// CanonicalOnly restricts the search to the canonical content of each
+	// duplicates cluster, set by collapsed searches rather than by clients
+	CanonicalOnly bool `json:"-"`
+	// ExcludeIDs leaves the given contents out of the search, set by the
+	// curation rules hiding them rather than by clients
+	ExcludeIDs []uint `json:"-"`
+}
+
+// FilterError reports an invalid filter
+type FilterError struct {
+	Key     string
+	Message string
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("invalid filter '%s': %s", e.Key, e.Message)
+}
+
+// UnmarshalJSON decodes the filters, rejecting unknown keys and values of
+// the wrong type
+func (f *SearchFilters) UnmarshalJSON(data []byte) error {
+	// The alias drops this method so decoding does not recurse
+	type searchFilters SearchFilters
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var decoded searchFilters
+	if err := decoder.Decode(&decoded); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return &FilterError{Key: typeErr.Field, Message: fmt.Sprintf("cannot be a JSON %s", typeErr.Value)}
+		}
+		if key, ok := unknownField(err); ok {
+			return &FilterError{Key: key, Message: "unknown filter"}
+		}
+		return err
+	}
+
+	*f = SearchFilters(decoded)
+	return nil
+}
+
+// unknownField extracts the key from the error returned by a decoder that
+// disallows unknown fields
+func unknownField(err error) (string, bool) {
+	const prefix = "json: unknown field "
+	message := err.Error()
+	if !strings.HasPrefix(message, prefix) {
+		return "", false
+	}
+	return strings.Trim(strings.TrimPrefix(message, prefix), `"`), true
+}
+
+// Validate checks the filter values
+func (f *SearchFilters) Validate() error {
+	if f.Type != "" && f.Type != "all" && f.Type != ContentTypeVideo && f.Type != ContentTypeText {
+		return &FilterError{Key: "type", Message: "must be 'video', 'text', or 'all'"}
+	}
+	if f.Published != "" {
+		if _, _, ok := PublishedRange(f.Published, time.Now()); !ok {
+			return &FilterError{Key: "published", Message: "must be 'last_week', 'last_month', 'last_quarter' or 'older'"}
+		}
+	}
+	if f.PublishedAfter != nil && f.PublishedBefore != nil && !f.PublishedAfter.Before(*f.PublishedBefore) {
+		return &FilterError{Key: "published_after", Message: "must be before 'published_before'"}
+	}
+	if f.MinViews < 0 {
+		return &FilterError{Key: "min_views", Message: "must not be negative"}
+	}
+	if f.MinReactions < 0 {
+		return &FilterError{Key: "min_reactions", Message: "must not be negative"}
+	}
+	if err := validateRange("duration_between", f.DurationBetween); err != nil {
+		return err
+	}
+	if err := validateRange("reading_time_between", f.ReadingTimeBetween); err != nil {
+		return err
+	}
+	if f.MinScore < 0 {
+		return &FilterError{Key: "min_score", Message: "must not be negative"}
+	}
+	return nil
+}
+
+// validateRange checks an optional inclusive [min, max] range
+func validateRange(key string, bounds []int) error {
+	if bounds == nil {
+		return nil
+	}
+	if len(bounds) != 2 {
+		return &FilterError{Key: key, Message: "must be a [min, max] pair"}
+	}
+	if bounds[0] < 0 || bounds[0] > bounds[1] {
+		return &FilterError{Key: key, Message: "must satisfy 0 <= min <= max"}
+	}
+	return nil
+}
+
+// Publication date buckets
+const (
+	PublishedLastWeek    = "last_week"
+	PublishedLastMonth   = "last_month"
+	PublishedLastQuarter = "last_quarter"
+	PublishedOlder       = "older"
+)
+
+// publishedBuckets lists the cumulative publication date buckets, "older"
+// holds everything beyond the last quarter
+var publishedBuckets = []struct {
+	name   string
+	maxAge time.Duration
+}{
+	{PublishedLastWeek, 7 * 24 * time.Hour},
+	{PublishedLastMonth, 30 * 24 * time.Hour},
+	{PublishedLastQuarter, 90 * 24 * time.Hour},
+}
+
+// PublishedRange returns the publication date bounds of a bucket, a zero
+// time leaves that side unbounded
+func PublishedRange(name string, now time.Time) (after, before time.Time, ok bool) {
+	for _, bucket := range publishedBuckets {
+		if bucket.name == name {
+			return now.Add(-bucket.maxAge), time.Time{}, true
+		}
+	}
+	if name == PublishedOlder {
+		return time.Time{}, now.Add(-publishedBuckets[len(publishedBuckets)-1].maxAge), true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// PublishedBuckets returns the buckets a publication date falls into
+func PublishedBuckets(publishedAt, now time.Time) []string {
+	age := now.Sub(publishedAt)
+
+	var names []string
+	for _, bucket := range publishedBuckets {
+		if age <= bucket.maxAge {
+			names = append(names, bucket.name)
+		}
+	}
+	if len(names) == 0 {
+		names = append(names, PublishedOlder)
+	}
+	return names
+}
+
+// FacetValue is a facet value with its number of matching contents, Param is
+// the search parameter that filters on the value
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+	Param string `json:"param"`
+}
+
+// Facets holds hit counts over the full match set of a search
+type Facets struct {
+	Type      []FacetValue `json:"type"`
+	Provider  []FacetValue `json:"provider"`
+	Language  []FacetValue `json:"language"`
+	Tags      []FacetValue `json:"tags"`
+	Published []FacetValue `json:"published"`
+}
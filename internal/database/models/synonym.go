@@ -0,0 +1,87 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// SynonymRuleType is the kind of a synonym rule
+type SynonymRuleType string
+
+const (
+	// SynonymEquivalent makes every synonym of the rule match all the others
+	SynonymEquivalent SynonymRuleType = "equivalent"
+	// SynonymExpansion makes the input also match the synonyms, but not the
+	// other way around
+	SynonymExpansion SynonymRuleType = "expansion"
+	// SynonymRewrite replaces the input phrase of a query with the replacement
+	// before the query is parsed
+	SynonymRewrite SynonymRuleType = "rewrite"
+)
+
+// SynonymRule is a query rewrite rule managed through the admin API
+type SynonymRule struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	Type        SynonymRuleType `json:"type" gorm:"size:20;not null"`
+	Input       string          `json:"input" gorm:"size:255"`
+	Synonyms    string          `json:"synonyms" gorm:"type:text"` // comma separated
+	Replacement string          `json:"replacement" gorm:"size:255"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// TableName specifies the table name for SynonymRule
+func (SynonymRule) TableName() string {
+	return "synonym_rules"
+}
+
+// SynonymList returns the trimmed, lowercased synonyms of the rule
+func (r *SynonymRule) SynonymList() []string {
+	var synonyms []string
+	for _, synonym := range strings.Split(r.Synonyms, ",") {
+		if synonym = strings.ToLower(strings.TrimSpace(synonym)); synonym != "" {
+			synonyms = append(synonyms, synonym)
+		}
+	}
+	return synonyms
+}
+
+// Validate checks the rule has the fields its type needs
+func (r *SynonymRule) Validate() error {
+	switch r.Type {
+	case SynonymEquivalent:
+		if len(r.SynonymList()) < 2 {
+			return &ParameterError{Key: "synonyms", Message: "equivalent rules need at least two synonyms"}
+		}
+	case SynonymExpansion:
+		if strings.TrimSpace(r.Input) == "" {
+			return &ParameterError{Key: "input", Message: "expansion rules need an input"}
+		}
+		if len(r.SynonymList()) == 0 {
+			return &ParameterError{Key: "synonyms", Message: "expansion rules need at least one synonym"}
+		}
+	case SynonymRewrite:
+		if strings.TrimSpace(r.Input) == "" {
+			return &ParameterError{Key: "input", Message: "rewrite rules need an input"}
+		}
+	default:
+		return &ParameterError{Key: "type", Message: "must be 'equivalent', 'expansion' or 'rewrite'"}
+	}
+	return nil
+}
+
+// QueryRewrite reports a rule applied to a search query
+type QueryRewrite struct {
+	Type SynonymRuleType `json:"type"`
+	From string          `json:"from"`
+	To   []string        `json:"to"`
+}
+
+// SynonymRepository interface defines the methods for synonym rule operations
+type SynonymRepository interface {
+	Create(rule *SynonymRule) error
+	Update(rule *SynonymRule) error
+	Delete(id uint) error
+	FindByID(id uint) (*SynonymRule, error)
+	FindAll() ([]SynonymRule, error)
+}
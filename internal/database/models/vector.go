@@ -0,0 +1,49 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Vector is a dense embedding, stored as little-endian float32 values
+type Vector []float32
+
+// Value encodes the vector for the database
+func (v Vector) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	buf := make([]byte, 4*len(v))
+	for i, x := range v {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(x))
+	}
+	return buf, nil
+}
+
+// Scan decodes a vector read from the database
+func (v *Vector) Scan(value interface{}) error {
+	var buf []byte
+	switch value := value.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		buf = value
+	case string:
+		buf = []byte(value)
+	default:
+		return fmt.Errorf("cannot scan %T into a vector", value)
+	}
+
+	if len(buf)%4 != 0 {
+		return fmt.Errorf("invalid vector length %d", len(buf))
+	}
+	vector := make(Vector, len(buf)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	*v = vector
+	return nil
+}
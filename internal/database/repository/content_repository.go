@@ -2,13 +2,24 @@ package repository
 
 import (
 	"math"
-	"strings"
 
 	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/query"
 
 	"gorm.io/gorm"
 )
 
+// idBatchSize is the number of IDs bound per IN list, well under the 65,535
+// placeholders a MySQL prepared statement accepts
+const idBatchSize = 1000
+
+// rankColumns are the columns ranking, curating, collapsing and faceting
+// index matches read
+const rankColumns = "id, type, provider, url, views, likes, duration, reading_time, reactions, cluster_id, tags, language, published_at"
+
+// embeddingColumns are only read when building the vector index
+var embeddingColumns = []string{"embedding", "embedding_model"}
+
 type ContentRepositoryImpl struct {
 	db *gorm.DB
 }
@@ -47,7 +58,7 @@ func (r *ContentRepositoryImpl) FindByProviderID(provider, providerID string) (*
 	return &content, nil
 }
 
-func (r *ContentRepositoryImpl) Search(query string, contentType models.ContentType, page, limit int) (*models.SearchResult, error) {
+func (r *ContentRepositoryImpl) Search(q query.Node, filters *models.SearchFilters, page models.PageRequest) (*models.SearchResult, error) {
 	var contents []models.Content
 	var total int64
 
@@ -55,18 +66,13 @@ func (r *ContentRepositoryImpl) Search(query string, contentType models.ContentT
 	dbQuery := r.db.Model(&models.Content{})
 
 	// Add search conditions
-	if query != "" {
-		searchQuery := "%" + strings.ToLower(query) + "%"
-		dbQuery = dbQuery.Where(
-			"LOWER(title) LIKE ? OR LOWER(description) LIKE ? OR LOWER(tags) LIKE ?",
-			searchQuery, searchQuery, searchQuery,
-		)
+	if q != nil {
+		condition, args := buildQueryCondition(q)
+		dbQuery = dbQuery.Where(condition, args...)
 	}
 
-	// Add content type filter
-	if contentType != "" && contentType != "all" {
-		dbQuery = dbQuery.Where("type = ?", contentType)
-	}
+	// Add filters
+	dbQuery = applyFilters(dbQuery, filters)
 
 	// Count total
 	if err := dbQuery.Count(&total).Error; err != nil {
@@ -74,40 +80,222 @@ func (r *ContentRepositoryImpl) Search(query string, contentType models.ContentT
 	}
 
 	// Calculate pagination
-	offset := (page - 1) * limit
-	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	totalPages := int(math.Ceil(float64(total) / float64(page.Limit)))
+	column := sortColumn(page.Sort)
+
+	if page.After == nil {
+		offset := (page.Page - 1) * page.Limit
+
+		// Get results
+		err := dbQuery.Order(orderClause(column, page.Order, false)).
+			Offset(offset).
+			Limit(page.Limit).
+			Find(&contents).Error
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.SearchResult{
+			Contents:    models.NewSearchHits(contents),
+			Total:       total,
+			Page:        page.Page,
+			Limit:       page.Limit,
+			TotalPages:  totalPages,
+			HasNext:     page.Page < totalPages,
+			HasPrevious: page.Page > 1,
+		}, nil
+	}
 
-	// Get results
-	err := dbQuery.Order("final_score DESC").
-		Offset(offset).
-		Limit(limit).
+	// Seek past the cursor, reading backwards for previous pages, and fetch
+	// one extra row to learn whether more results follow
+	cursor := page.After
+	condition, value := seekCondition(column, page.Order, cursor)
+	err := dbQuery.Where(condition, value, value, cursor.ID).
+		Order(orderClause(column, page.Order, cursor.Backward)).
+		Limit(page.Limit + 1).
 		Find(&contents).Error
 
 	if err != nil {
 		return nil, err
 	}
 
+	more := len(contents) > page.Limit
+	if more {
+		contents = contents[:page.Limit]
+	}
+	if cursor.Backward {
+		for i, j := 0, len(contents)-1; i < j; i, j = i+1, j-1 {
+			contents[i], contents[j] = contents[j], contents[i]
+		}
+	}
+
 	return &models.SearchResult{
-		Contents:    contents,
+		Contents:    models.NewSearchHits(contents),
 		Total:       total,
-		Page:        page,
-		Limit:       limit,
+		Limit:       page.Limit,
 		TotalPages:  totalPages,
-		HasNext:     page < totalPages,
-		HasPrevious: page > 1,
+		HasNext:     more || cursor.Backward,
+		HasPrevious: more || !cursor.Backward,
 	}, nil
 }
 
+// FindByIDs returns the given contents that pass the filters, without their
+// embeddings
+func (r *ContentRepositoryImpl) FindByIDs(ids []uint, filters *models.SearchFilters) ([]models.Content, error) {
+	return r.findByIDs(r.db.Omit(embeddingColumns...), ids, filters)
+}
+
+// FindRankFields returns the columns needed to rank the given contents that
+// pass the filters, so a whole match set can be ranked without loading the
+// text and embedding of every row
+func (r *ContentRepositoryImpl) FindRankFields(ids []uint, filters *models.SearchFilters) ([]models.Content, error) {
+	return r.findByIDs(r.db.Select(rankColumns), ids, filters)
+}
+
+// findByIDs runs the query for the given contents that pass the filters,
+// binding the IDs in batches
+func (r *ContentRepositoryImpl) findByIDs(dbQuery *gorm.DB, ids []uint, filters *models.SearchFilters) ([]models.Content, error) {
+	// Every batch starts from the same query
+	dbQuery = dbQuery.Session(&gorm.Session{})
+
+	var contents []models.Content
+	for start := 0; start < len(ids); start += idBatchSize {
+		batch := ids[start:min(start+idBatchSize, len(ids))]
+
+		var found []models.Content
+		err := applyFilters(dbQuery.Where("id IN ?", batch), filters).Find(&found).Error
+		if err != nil {
+			return nil, err
+		}
+		contents = append(contents, found...)
+	}
+	return contents, nil
+}
+
+// FindFacetFields returns the faceted fields of every content matching the
+// query and filters
+func (r *ContentRepositoryImpl) FindFacetFields(q query.Node, filters *models.SearchFilters) ([]models.Content, error) {
+	var contents []models.Content
+
+	dbQuery := r.db.Model(&models.Content{}).Select("id, type, provider, language, tags, published_at")
+	if q != nil {
+		condition, args := buildQueryCondition(q)
+		dbQuery = dbQuery.Where(condition, args...)
+	}
+	dbQuery = applyFilters(dbQuery, filters)
+
+	err := dbQuery.Find(&contents).Error
+	return contents, err
+}
+
+// FindAfterID returns up to limit contents with an ID greater than afterID,
+// ordered by ID, so callers can walk the table in keyset-paginated batches
+func (r *ContentRepositoryImpl) FindAfterID(afterID uint, limit int) ([]models.Content, error) {
+	var contents []models.Content
+	err := r.db.Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&contents).Error
+	return contents, err
+}
+
 func (r *ContentRepositoryImpl) GetPopular(limit int) ([]models.Content, error) {
 	var contents []models.Content
 	err := r.db.Order("final_score DESC").Limit(limit).Find(&contents).Error
 	return contents, err
 }
 
-func (r *ContentRepositoryImpl) UpdateScores() error {
-	// This method will be called periodically to update scores
-	// For now, we'll just return nil as scores are calculated on-the-fly
-	return nil
+// UpdateScores stores the scores of the given contents and the profile they
+// were scored with without touching their other columns
+func (r *ContentRepositoryImpl) UpdateScores(contents []models.Content) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range contents {
+			err := tx.Model(&models.Content{}).Where("id = ?", contents[i].ID).Updates(map[string]interface{}{
+				"base_score":       contents[i].BaseScore,
+				"type_multiplier":  contents[i].TypeMultiplier,
+				"freshness_score":  contents[i].FreshnessScore,
+				"engagement_score": contents[i].EngagementScore,
+				"final_score":      contents[i].FinalScore,
+				"scoring_profile":  contents[i].ScoringProfile,
+				"scoring_version":  contents[i].ScoringVersion,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindEngagementStats sums the engagement of the contents per provider and type
+func (r *ContentRepositoryImpl) FindEngagementStats() ([]models.EngagementStats, error) {
+	var stats []models.EngagementStats
+	err := r.db.Model(&models.Content{}).
+		Select("provider, type, COUNT(*) AS contents, SUM(views) AS views, SUM(likes) AS likes, " +
+			"SUM(reactions) AS reactions, SUM(reading_time) AS reading_time").
+		Group("provider, type").
+		Scan(&stats).Error
+	return stats, err
+}
+
+// UpdateEmbeddings stores the embeddings of the given contents without
+// touching their other columns
+func (r *ContentRepositoryImpl) UpdateEmbeddings(contents []models.Content) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range contents {
+			err := tx.Model(&models.Content{}).Where("id = ?", contents[i].ID).Updates(map[string]interface{}{
+				"embedding":       contents[i].Embedding,
+				"embedding_model": contents[i].EmbeddingModel,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindFingerprints returns the fields near-duplicate detection needs of
+// every content
+func (r *ContentRepositoryImpl) FindFingerprints() ([]models.Content, error) {
+	var contents []models.Content
+	err := r.db.Select("id, title, description, fingerprint, cluster_id, published_at").Order("id ASC").Find(&contents).Error
+	return contents, err
+}
+
+// UpdateClusters stores the fingerprints and clusters of the given contents
+// without touching their other columns
+func (r *ContentRepositoryImpl) UpdateClusters(contents []models.Content) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range contents {
+			err := tx.Model(&models.Content{}).Where("id = ?", contents[i].ID).Updates(map[string]interface{}{
+				"fingerprint": contents[i].Fingerprint,
+				"cluster_id":  contents[i].ClusterID,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindDuplicates returns the non-canonical contents of the given clusters
+// that match the query and filters
+func (r *ContentRepositoryImpl) FindDuplicates(q query.Node, filters *models.SearchFilters, clusterIDs []uint) ([]models.Content, error) {
+	var contents []models.Content
+	if len(clusterIDs) == 0 {
+		return contents, nil
+	}
+
+	dbQuery := r.db.Model(&models.Content{}).Select("id, url, cluster_id").
+		Where("cluster_id IN ? AND cluster_id <> id", clusterIDs)
+	if q != nil {
+		condition, args := buildQueryCondition(q)
+		dbQuery = dbQuery.Where(condition, args...)
+	}
+	dbQuery = applyFilters(dbQuery, filters)
+
+	err := dbQuery.Order("id ASC").Find(&contents).Error
+	return contents, err
 }
 
 func (r *ContentRepositoryImpl) BulkUpsert(contents []models.Content) error {
@@ -117,20 +305,22 @@ func (r *ContentRepositoryImpl) BulkUpsert(contents []models.Content) error {
 
 	// Use transaction for bulk operations
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		for _, content := range contents {
+		for i := range contents {
+			content := &contents[i]
+
 			// Check if content exists
 			var existing models.Content
 			err := tx.Where("provider = ? AND provider_id = ?", content.Provider, content.ProviderID).First(&existing).Error
 			
 			if err == gorm.ErrRecordNotFound {
 				// Create new content
-				if err := tx.Create(&content).Error; err != nil {
+				if err := tx.Create(content).Error; err != nil {
 					return err
 				}
 			} else if err == nil {
 				// Update existing content
 				content.ID = existing.ID
-				if err := tx.Save(&content).Error; err != nil {
+				if err := tx.Save(content).Error; err != nil {
 					return err
 				}
 			} else {
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"search-engine-service/internal/database/models"
+
+	"gorm.io/gorm"
+)
+
+type CurationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCurationRepository(db *gorm.DB) models.CurationRepository {
+	return &CurationRepositoryImpl{db: db}
+}
+
+func (r *CurationRepositoryImpl) Create(rule *models.CurationRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *CurationRepositoryImpl) Update(rule *models.CurationRule) error {
+	return r.db.Save(rule).Error
+}
+
+func (r *CurationRepositoryImpl) Delete(id uint) error {
+	return r.db.Delete(&models.CurationRule{}, id).Error
+}
+
+func (r *CurationRepositoryImpl) FindByID(id uint) (*models.CurationRule, error) {
+	var rule models.CurationRule
+	err := r.db.First(&rule, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// FindAll returns every curation rule ordered by ID
+func (r *CurationRepositoryImpl) FindAll() ([]models.CurationRule, error) {
+	var rules []models.CurationRule
+	err := r.db.Order("id ASC").Find(&rules).Error
+	return rules, err
+}
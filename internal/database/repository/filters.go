@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"search-engine-service/internal/database/models"
+
+	"gorm.io/gorm"
+)
+
+// applyFilters adds the search filters to a query
+func applyFilters(dbQuery *gorm.DB, filters *models.SearchFilters) *gorm.DB {
+	if filters == nil {
+		return dbQuery
+	}
+
+	if filters.Type != "" && filters.Type != "all" {
+		dbQuery = dbQuery.Where("type = ?", filters.Type)
+	}
+	if filters.Provider != "" {
+		dbQuery = dbQuery.Where("provider = ?", filters.Provider)
+	}
+	if filters.Language != "" {
+		dbQuery = dbQuery.Where("language = ?", filters.Language)
+	}
+	if filters.PublishedAfter != nil {
+		dbQuery = dbQuery.Where("published_at >= ?", *filters.PublishedAfter)
+	}
+	if filters.PublishedBefore != nil {
+		dbQuery = dbQuery.Where("published_at < ?", *filters.PublishedBefore)
+	}
+	if filters.MinViews > 0 {
+		dbQuery = dbQuery.Where("views >= ?", filters.MinViews)
+	}
+	if filters.MinReactions > 0 {
+		dbQuery = dbQuery.Where("reactions >= ?", filters.MinReactions)
+	}
+	if len(filters.DurationBetween) == 2 {
+		dbQuery = dbQuery.Where("duration BETWEEN ? AND ?", filters.DurationBetween[0], filters.DurationBetween[1])
+	}
+	if len(filters.ReadingTimeBetween) == 2 {
+		dbQuery = dbQuery.Where("reading_time BETWEEN ? AND ?", filters.ReadingTimeBetween[0], filters.ReadingTimeBetween[1])
+	}
+	if len(filters.TagsAny) > 0 {
+		conditions := make([]string, len(filters.TagsAny))
+		args := make([]interface{}, len(filters.TagsAny))
+		for i, tag := range filters.TagsAny {
+			conditions[i] = tagCondition
+			args[i] = tagPattern(tag)
+		}
+		dbQuery = dbQuery.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+	for _, tag := range filters.TagsAll {
+		dbQuery = dbQuery.Where(tagCondition, tagPattern(tag))
+	}
+	if filters.MinScore > 0 {
+		dbQuery = dbQuery.Where("final_score >= ?", filters.MinScore)
+	}
+	if filters.CanonicalOnly {
+		dbQuery = dbQuery.Where("(cluster_id = 0 OR cluster_id = id)")
+	}
+	if len(filters.ExcludeIDs) > 0 {
+		dbQuery = dbQuery.Where("id NOT IN ?", filters.ExcludeIDs)
+	}
+	if filters.Published != "" {
+		if after, before, ok := models.PublishedRange(filters.Published, time.Now()); ok {
+			if !after.IsZero() {
+				dbQuery = dbQuery.Where("published_at >= ?", after)
+			}
+			if !before.IsZero() {
+				dbQuery = dbQuery.Where("published_at < ?", before)
+			}
+		}
+	}
+
+	return dbQuery
+}
+
+// tagCondition matches a whole tag within the comma separated tags column
+const tagCondition = "CONCAT(',', REPLACE(LOWER(tags), ' ', ''), ',') LIKE ?"
+
+// tagPattern returns the LIKE pattern matching a single tag
+func tagPattern(tag string) string {
+	return "%," + likeEscaper.Replace(strings.ToLower(strings.TrimSpace(tag))) + ",%"
+}
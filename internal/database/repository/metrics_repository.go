@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"time"
+
+	"search-engine-service/internal/database/models"
+
+	"gorm.io/gorm"
+)
+
+type MetricsRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewMetricsRepository(db *gorm.DB) models.MetricsRepository {
+	return &MetricsRepositoryImpl{db: db}
+}
+
+// CreateBatch stores the snapshots of a refresh
+func (r *MetricsRepositoryImpl) CreateBatch(snapshots []models.MetricSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(snapshots, 500).Error
+}
+
+// FindSince returns the snapshots captured since the given time of the
+// contents passing the filters, ordered by content and time
+func (r *MetricsRepositoryImpl) FindSince(since time.Time, filters *models.SearchFilters) ([]models.MetricSnapshot, error) {
+	var snapshots []models.MetricSnapshot
+
+	contentIDs := applyFilters(r.db.Model(&models.Content{}).Select("id"), filters)
+	err := r.db.Where("captured_at >= ? AND content_id IN (?)", since, contentIDs).
+		Order("content_id ASC, captured_at ASC").
+		Find(&snapshots).Error
+	return snapshots, err
+}
+
+// FindForContents returns the snapshots of the given contents captured since
+// the given time, ordered by content and time
+func (r *MetricsRepositoryImpl) FindForContents(ids []uint, since time.Time) ([]models.MetricSnapshot, error) {
+	var snapshots []models.MetricSnapshot
+	if len(ids) == 0 {
+		return snapshots, nil
+	}
+
+	err := r.db.Where("content_id IN ? AND captured_at >= ?", ids, since).
+		Order("content_id ASC, captured_at ASC").
+		Find(&snapshots).Error
+	return snapshots, err
+}
+
+// DeleteBefore removes the snapshots captured before the given time
+func (r *MetricsRepositoryImpl) DeleteBefore(before time.Time) (int64, error) {
+	result := r.db.Where("captured_at < ?", before).Delete(&models.MetricSnapshot{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"strings"
+
+	"search-engine-service/internal/query"
+)
+
+// likeEscaper escapes the LIKE wildcard characters
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// buildQueryCondition translates a parsed query into a SQL condition with its arguments
+func buildQueryCondition(node query.Node) (string, []interface{}) {
+	switch n := node.(type) {
+	case *query.TermNode:
+		return textCondition(n.Field, n.Text)
+
+	case *query.PhraseNode:
+		return textCondition(n.Field, n.Text)
+
+	case *query.AndNode:
+		return joinConditions(n.Children, " AND ")
+
+	case *query.OrNode:
+		return joinConditions(n.Children, " OR ")
+
+	case *query.NotNode:
+		condition, args := buildQueryCondition(n.Child)
+		return "NOT (" + condition + ")", args
+
+	default:
+		return "1 = 1", nil
+	}
+}
+
+// textCondition matches a term or phrase as a substring of the scoped fields
+func textCondition(field, text string) (string, []interface{}) {
+	if field == query.FieldType {
+		return "type = ?", []interface{}{strings.ToLower(text)}
+	}
+
+	pattern := "%" + likeEscaper.Replace(strings.ToLower(text)) + "%"
+	switch field {
+	case query.FieldTitle, query.FieldDescription, query.FieldTags:
+		return "LOWER(" + field + ") LIKE ?", []interface{}{pattern}
+	default:
+		return "(LOWER(title) LIKE ? OR LOWER(description) LIKE ? OR LOWER(tags) LIKE ?)",
+			[]interface{}{pattern, pattern, pattern}
+	}
+}
+
+// joinConditions combines the conditions of several nodes with an operator
+func joinConditions(nodes []query.Node, operator string) (string, []interface{}) {
+	parts := make([]string, len(nodes))
+	var args []interface{}
+	for i, node := range nodes {
+		condition, nodeArgs := buildQueryCondition(node)
+		parts[i] = "(" + condition + ")"
+		args = append(args, nodeArgs...)
+	}
+	return strings.Join(parts, operator), args
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"fmt"
+
+	"search-engine-service/internal/database/models"
+)
+
+// sortColumn returns the column ordering results for a sort field, relevance
+// is only known to the search index so the database falls back to the score
+func sortColumn(sort models.SortField) string {
+	switch sort {
+	case models.SortNewest:
+		return "published_at"
+	case models.SortViews:
+		return "views"
+	case models.SortReactions:
+		return "reactions"
+	default:
+		return "final_score"
+	}
+}
+
+// orderClause orders by the column with ties broken by ascending ID,
+// reversing both for backward seeks
+func orderClause(column string, order models.SortOrder, reverse bool) string {
+	desc := order == models.OrderDesc
+	if reverse {
+		desc = !desc
+	}
+	return fmt.Sprintf("%s %s, id %s", column, sqlDirection(desc), sqlDirection(reverse))
+}
+
+// sqlDirection returns the SQL keyword of a sort direction
+func sqlDirection(desc bool) string {
+	if desc {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// seekCondition returns the keyset condition selecting the rows after the
+// cursor in the scan direction, taking the sort value, the value again and
+// the cursor ID as arguments
+func seekCondition(column string, order models.SortOrder, cursor *models.Cursor) (string, interface{}) {
+	var value interface{} = cursor.Value
+	if column == "published_at" {
+		value = cursor.Time
+	}
+
+	// Rows after the cursor have a smaller value when sorting descending
+	less := order == models.OrderDesc
+	if cursor.Backward {
+		less = !less
+	}
+
+	idOp := ">"
+	if cursor.Backward {
+		idOp = "<"
+	}
+	valueOp := ">"
+	if less {
+		valueOp = "<"
+	}
+
+	return fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", column, valueOp, column, idOp), value
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"search-engine-service/internal/database/models"
+
+	"gorm.io/gorm"
+)
+
+type SynonymRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewSynonymRepository(db *gorm.DB) models.SynonymRepository {
+	return &SynonymRepositoryImpl{db: db}
+}
+
+func (r *SynonymRepositoryImpl) Create(rule *models.SynonymRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *SynonymRepositoryImpl) Update(rule *models.SynonymRule) error {
+	return r.db.Save(rule).Error
+}
+
+func (r *SynonymRepositoryImpl) Delete(id uint) error {
+	return r.db.Delete(&models.SynonymRule{}, id).Error
+}
+
+func (r *SynonymRepositoryImpl) FindByID(id uint) (*models.SynonymRule, error) {
+	var rule models.SynonymRule
+	err := r.db.First(&rule, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// FindAll returns every synonym rule ordered by ID
+func (r *SynonymRepositoryImpl) FindAll() ([]models.SynonymRule, error) {
+	var rules []models.SynonymRule
+	err := r.db.Order("id ASC").Find(&rules).Error
+	return rules, err
+}
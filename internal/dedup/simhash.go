@@ -0,0 +1,153 @@
+// Package dedup detects near-duplicate contents with SimHash fingerprints.
+//
+// A fingerprint sums the signed bits of the hashes of the words and word
+// pairs of a text, so texts differing in a few words differ in a few bits
+// and near-duplicates are found by their Hamming distance.
+package dedup
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"sort"
+
+	"search-engine-service/internal/analysis"
+)
+
+// Fingerprint returns the 64-bit SimHash of a text, zero for a text without words
+func Fingerprint(text string) uint64 {
+	tokens := analysis.Standard.Analyze(text)
+	if len(tokens) == 0 {
+		return 0
+	}
+
+	var counts [64]int
+	add := func(feature string) {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<bit) != 0 {
+				counts[bit]++
+			} else {
+				counts[bit]--
+			}
+		}
+	}
+
+	for i, token := range tokens {
+		add(token.Term)
+		if i > 0 {
+			add(tokens[i-1].Term + " " + token.Term)
+		}
+	}
+
+	var fingerprint uint64
+	for bit, count := range counts {
+		if count > 0 {
+			fingerprint |= 1 << bit
+		}
+	}
+	return fingerprint
+}
+
+// Distance returns the number of differing bits of two fingerprints
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Item is a fingerprinted content to cluster
+type Item struct {
+	ID          uint
+	Fingerprint uint64
+}
+
+// Cluster groups the items whose fingerprints are within maxDistance bits of
+// each other, directly or through other items, and maps every item to the
+// canonical item of its group: the one that comes first in the slice. Items
+// without a fingerprint are never grouped.
+func Cluster(items []Item, maxDistance int) map[uint]uint {
+	parent := make([]int, len(items))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(i, j int) {
+		ri, rj := find(i), find(j)
+		// The earlier item stays the root, so it becomes the canonical one
+		if ri < rj {
+			parent[rj] = ri
+		} else if rj < ri {
+			parent[ri] = rj
+		}
+	}
+
+	// Splitting the fingerprints into maxDistance+1 bands, two fingerprints
+	// within maxDistance bits agree on at least one band, so only items
+	// sharing a band value need to be compared
+	for _, band := range bands(maxDistance) {
+		buckets := make(map[uint64][]int)
+		for i, item := range items {
+			if item.Fingerprint == 0 {
+				continue
+			}
+			key := item.Fingerprint & band
+			buckets[key] = append(buckets[key], i)
+		}
+
+		keys := make([]uint64, 0, len(buckets))
+		for key := range buckets {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+		for _, key := range keys {
+			bucket := buckets[key]
+			for a := 0; a < len(bucket); a++ {
+				for b := a + 1; b < len(bucket); b++ {
+					i, j := bucket[a], bucket[b]
+					if Distance(items[i].Fingerprint, items[j].Fingerprint) <= maxDistance {
+						union(i, j)
+					}
+				}
+			}
+		}
+	}
+
+	canonical := make(map[uint]uint, len(items))
+	for i, item := range items {
+		canonical[item.ID] = items[find(i)].ID
+	}
+	return canonical
+}
+
+// bands returns the masks of maxDistance+1 disjoint bit ranges covering all
+// 64 bits
+func bands(maxDistance int) []uint64 {
+	count := maxDistance + 1
+	if count < 1 {
+		count = 1
+	}
+	if count > 64 {
+		count = 64
+	}
+
+	masks := make([]uint64, count)
+	start := 0
+	for i := range masks {
+		width := 64 / count
+		if i < 64%count {
+			width++
+		}
+		for bit := start; bit < start+width; bit++ {
+			masks[i] |= 1 << bit
+		}
+		start += width
+	}
+	return masks
+}
@@ -0,0 +1,115 @@
+// Package embedding turns text into dense vectors for semantic search.
+//
+// The built-in embedder hashes words and their character n-grams into a
+// fixed number of dimensions, so it needs no model files or network access
+// and texts sharing words or word parts end up close to each other.
+package embedding
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+
+	"search-engine-service/internal/analysis"
+)
+
+// Embedder maps text to a unit-length vector
+type Embedder interface {
+	// Name identifies the model, vectors of different models are not comparable
+	Name() string
+	Dimensions() int
+	Embed(text string) []float32
+}
+
+// DefaultDimensions is the vector size of the hashing embedder
+const DefaultDimensions = 256
+
+// Feature weights of the hashing embedder, whole words carry more meaning
+// than the n-grams shared by related word forms
+const (
+	wordWeight  = 1.0
+	ngramWeight = 0.5
+)
+
+// ngramSizes are the character n-gram lengths hashed for every word
+var ngramSizes = []int{3, 4}
+
+// HashEmbedder embeds text with signed feature hashing of words and their
+// character n-grams
+type HashEmbedder struct {
+	dimensions int
+}
+
+// NewHashEmbedder creates a hashing embedder of the given dimensions
+func NewHashEmbedder(dimensions int) *HashEmbedder {
+	if dimensions < 1 {
+		dimensions = DefaultDimensions
+	}
+	return &HashEmbedder{dimensions: dimensions}
+}
+
+// Name returns the model name, including the dimensions
+func (e *HashEmbedder) Name() string {
+	return fmt.Sprintf("hash-ngram-%d", e.dimensions)
+}
+
+// Dimensions returns the vector size
+func (e *HashEmbedder) Dimensions() int {
+	return e.dimensions
+}
+
+// Embed returns the normalized feature vector of the text, the zero vector
+// when it has no words
+func (e *HashEmbedder) Embed(text string) []float32 {
+	vector := make([]float32, e.dimensions)
+	for _, token := range analysis.Standard.Analyze(text) {
+		e.add(vector, "w:"+token.Term, wordWeight)
+
+		runes := []rune("<" + token.Term + ">")
+		for _, size := range ngramSizes {
+			for i := 0; i+size <= len(runes); i++ {
+				e.add(vector, "n:"+string(runes[i:i+size]), ngramWeight)
+			}
+		}
+	}
+	Normalize(vector)
+	return vector
+}
+
+// add hashes a feature into the vector, the sign hash keeps collisions from
+// adding up
+func (e *HashEmbedder) add(vector []float32, feature string, weight float32) {
+	h := fnv.New64a()
+	h.Write([]byte(feature))
+	sum := h.Sum64()
+
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	vector[sum%uint64(e.dimensions)] += weight
+}
+
+// Normalize scales a vector to unit length in place, leaving the zero vector
+func Normalize(vector []float32) {
+	var norm float64
+	for _, x := range vector {
+		norm += float64(x) * float64(x)
+	}
+	if norm == 0 {
+		return
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range vector {
+		vector[i] *= scale
+	}
+}
+
+// IsZero reports whether a vector has no non-zero component
+func IsZero(vector []float32) bool {
+	for _, x := range vector {
+		if x != 0 {
+			return false
+		}
+	}
+	return true
+}
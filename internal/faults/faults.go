@@ -0,0 +1,194 @@
+// Package faults injects failures into the responses of a gin server, so the
+// retries and circuit breakers of provider fetches can be exercised against
+// the mock provider server.
+package faults
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Fault describes how requests to a path fail
+type Fault struct {
+	// Path is the request path the fault applies to, e.g. /api/videos
+	Path string `json:"path"`
+	// Status is the status failing requests answer with, 503 by default. A
+	// fault with only a delay lets requests through once delayed.
+	Status int `json:"status,omitempty"`
+	// Count is how many requests fail before the fault clears, zero fails
+	// every request until the fault is removed
+	Count int `json:"count,omitempty"`
+	// Rate is the probability a request fails, every request by default
+	Rate float64 `json:"rate,omitempty"`
+	// Delay is how long requests wait before being answered, e.g. 2s
+	Delay string `json:"delay,omitempty"`
+	// RetryAfter is the Retry-After header of failing requests, in seconds
+	RetryAfter int `json:"retry_after,omitempty"`
+	// Drop closes the connection without answering, a network error
+	Drop bool `json:"drop,omitempty"`
+
+	delay time.Duration
+}
+
+// fails reports whether the fault answers with an error rather than only
+// delaying requests
+func (f *Fault) fails() bool {
+	return f.Status != 0 || f.Drop
+}
+
+// Injector holds the faults of a server, safe for concurrent use
+type Injector struct {
+	mu     sync.Mutex
+	faults map[string]*Fault
+}
+
+// New creates an injector without faults
+func New() *Injector {
+	return &Injector{faults: make(map[string]*Fault)}
+}
+
+// Set installs a fault, replacing the fault of its path
+func (i *Injector) Set(fault Fault) error {
+	if fault.Path == "" {
+		return fmt.Errorf("path is required")
+	}
+	if fault.Status != 0 && (fault.Status < 400 || fault.Status > 599) {
+		return fmt.Errorf("status must be between 400 and 599")
+	}
+	if fault.Count < 0 || fault.Rate < 0 || fault.Rate > 1 || fault.RetryAfter < 0 {
+		return fmt.Errorf("count, rate and retry_after must not be negative, rate at most 1")
+	}
+	if fault.Delay != "" {
+		delay, err := time.ParseDuration(fault.Delay)
+		if err != nil || delay < 0 {
+			return fmt.Errorf("invalid delay %q", fault.Delay)
+		}
+		fault.delay = delay
+	}
+	if fault.Status == 0 && !fault.Drop && fault.delay == 0 {
+		fault.Status = http.StatusServiceUnavailable
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults[fault.Path] = &fault
+	return nil
+}
+
+// Clear removes the fault of a path, every fault when the path is empty
+func (i *Injector) Clear(path string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if path == "" {
+		i.faults = make(map[string]*Fault)
+		return
+	}
+	delete(i.faults, path)
+}
+
+// List returns the installed faults
+func (i *Injector) List() []Fault {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	faults := make([]Fault, 0, len(i.faults))
+	for _, fault := range i.faults {
+		faults = append(faults, *fault)
+	}
+	return faults
+}
+
+// take returns the fault applying to a request to a path, consuming one of
+// its counted failures
+func (i *Injector) take(path string) (Fault, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	fault, ok := i.faults[path]
+	if !ok || (fault.Rate > 0 && rand.Float64() >= fault.Rate) {
+		return Fault{}, false
+	}
+	if fault.Count > 0 {
+		fault.Count--
+		if fault.Count == 0 {
+			delete(i.faults, path)
+		}
+	}
+	return *fault, true
+}
+
+// Middleware applies the installed faults to the requests of the router
+func (i *Injector) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fault, ok := i.take(c.Request.URL.Path)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		if fault.delay > 0 {
+			select {
+			case <-time.After(fault.delay):
+			case <-c.Request.Context().Done():
+				c.Abort()
+				return
+			}
+		}
+		if !fault.fails() {
+			c.Next()
+			return
+		}
+
+		if fault.Drop {
+			if conn, _, err := c.Writer.Hijack(); err == nil {
+				conn.Close()
+				c.Abort()
+				return
+			}
+		}
+		if fault.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(fault.RetryAfter))
+		}
+		status := fault.Status
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+		c.AbortWithStatusJSON(status, gin.H{
+			"error": "injected fault",
+		})
+	}
+}
+
+// RegisterRoutes adds the routes managing the faults: GET lists them, PUT
+// installs one and DELETE removes the fault of the path query parameter, or
+// every fault
+func (i *Injector) RegisterRoutes(router gin.IRoutes) {
+	router.GET("/faults", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"faults": i.List()})
+	})
+
+	router.PUT("/faults", func(c *gin.Context) {
+		var fault Fault
+		if err := c.ShouldBindJSON(&fault); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := i.Set(fault); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"faults": i.List()})
+	})
+
+	router.DELETE("/faults", func(c *gin.Context) {
+		i.Clear(c.Query("path"))
+		c.JSON(http.StatusOK, gin.H{"faults": i.List()})
+	})
+}
@@ -0,0 +1,109 @@
+package index
+
+// bkTree is a Burkhard-Keller tree over terms for edit distance lookups
+type bkTree struct {
+	root *bkNode
+}
+
+type bkNode struct {
+	term     string
+	children map[int]*bkNode
+}
+
+// fuzzyMatch is a term within the requested edit distance of a lookup
+type fuzzyMatch struct {
+	term     string
+	distance int
+}
+
+// insert adds a term to the tree
+func (t *bkTree) insert(term string) {
+	if t.root == nil {
+		t.root = &bkNode{term: term}
+		return
+	}
+
+	node := t.root
+	for {
+		distance := levenshtein(term, node.term)
+		if distance == 0 {
+			return
+		}
+		child, ok := node.children[distance]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[int]*bkNode)
+			}
+			node.children[distance] = &bkNode{term: term}
+			return
+		}
+		node = child
+	}
+}
+
+// search returns every term within maxDistance edits of the given term
+func (t *bkTree) search(term string, maxDistance int) []fuzzyMatch {
+	if t.root == nil {
+		return nil
+	}
+
+	var matches []fuzzyMatch
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		distance := levenshtein(term, node.term)
+		if distance <= maxDistance {
+			matches = append(matches, fuzzyMatch{term: node.term, distance: distance})
+		}
+
+		// By the triangle inequality only children in this band can match
+		for childDistance, child := range node.children {
+			if childDistance >= distance-maxDistance && childDistance <= distance+maxDistance {
+				stack = append(stack, child)
+			}
+		}
+	}
+	return matches
+}
+
+// levenshtein returns the edit distance between two strings in runes
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
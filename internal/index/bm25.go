@@ -0,0 +1,113 @@
+package index
+
+import (
+	"math"
+	"sort"
+
+	"search-engine-service/internal/analysis"
+)
+
+// BM25Params holds the BM25F ranking parameters
+type BM25Params struct {
+	K1                float64
+	B                 float64
+	TitleWeight       float64
+	DescriptionWeight float64
+	TagsWeight        float64
+}
+
+// DefaultBM25Params returns the standard BM25 parameters with title matches
+// weighted above tags and tags above descriptions
+func DefaultBM25Params() BM25Params {
+	return BM25Params{
+		K1:                1.2,
+		B:                 0.75,
+		TitleWeight:       3.0,
+		DescriptionWeight: 1.0,
+		TagsWeight:        2.0,
+	}
+}
+
+// weight returns the weight of a field
+func (p BM25Params) weight(field Field) float64 {
+	switch field {
+	case FieldTitle:
+		return p.TitleWeight
+	case FieldDescription:
+		return p.DescriptionWeight
+	case FieldTags:
+		return p.TagsWeight
+	default:
+		return 0
+	}
+}
+
+// Hit is a matching document with its text relevance score
+type Hit struct {
+	DocID uint
+	Score float64
+}
+
+// sortHits orders hits by descending score, breaking ties by document ID
+func sortHits(hits []Hit) {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].DocID < hits[j].DocID
+	})
+}
+
+// termScore computes the BM25F score of a term for a document over the given
+// fields, the caller must hold the read lock
+func (ix *Index) termScore(term string, id uint, params BM25Params, fields []Field) float64 {
+	posting, ok := ix.postings[term][id]
+	if !ok {
+		return 0
+	}
+	doc := ix.docs[id]
+
+	// Combine the length-normalized frequencies of every field
+	tf := 0.0
+	for _, field := range fields {
+		freq := posting.Freq(field)
+		if freq == 0 {
+			continue
+		}
+		norm := 1 - params.B
+		if avg := ix.avgFieldLength(field); avg > 0 {
+			norm += params.B * float64(doc.lengths[field]) / avg
+		}
+		tf += params.weight(field) * float64(freq) / norm
+	}
+
+	return ix.idf(term) * tf * (params.K1 + 1) / (tf + params.K1)
+}
+
+// idf returns the inverse document frequency of a term, the caller must hold the read lock
+func (ix *Index) idf(term string) float64 {
+	n := float64(len(ix.docs))
+	df := float64(len(ix.postings[term]))
+	return math.Log(1 + (n-df+0.5)/(df+0.5))
+}
+
+// avgFieldLength returns the average token count of a field, the caller must hold the read lock
+func (ix *Index) avgFieldLength(field Field) float64 {
+	if len(ix.docs) == 0 {
+		return 0
+	}
+	return float64(ix.totalLengths[field]) / float64(len(ix.docs))
+}
+
+// uniqueTerms returns the distinct terms of the tokens in order of appearance
+func uniqueTerms(tokens []analysis.Token) []string {
+	seen := make(map[string]bool, len(tokens))
+	var terms []string
+	for _, token := range tokens {
+		if !seen[token.Term] {
+			seen[token.Term] = true
+			terms = append(terms, token.Term)
+		}
+	}
+	return terms
+}
@@ -0,0 +1,102 @@
+package index
+
+import (
+	"fmt"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/query"
+)
+
+// Explain explains the BM25F score Execute gives a document for a query,
+// matched reports whether the document matches the query at all
+func (ix *Index) Explain(node query.Node, opts Options, id uint) (explanation models.Explanation, matched bool) {
+	if node == nil {
+		return models.Explain(0, "empty query"), false
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	doc, ok := ix.docs[id]
+	if !ok {
+		return models.Explain(0, "not indexed"), false
+	}
+
+	ex := &executor{ix: ix, opts: opts}
+	if _, ok := ex.eval(node).matched[id]; !ok {
+		return models.Explain(0, fmt.Sprintf("does not match %s", node)), false
+	}
+
+	total := 0.0
+	var details []models.Explanation
+	for _, clause := range ex.scoringClauses(node) {
+		if clause.analyzer != doc.analyzer {
+			continue
+		}
+
+		// A clause scores its best variant, like Execute
+		var best models.Explanation
+		for _, variant := range clause.variants {
+			term := ix.termExplanation(variant.term, id, opts.BM25, clause.fields)
+			if term.Value == 0 {
+				continue
+			}
+			weighted := models.Explain(variant.weight*term.Value,
+				fmt.Sprintf("term %q, weight %.2g", variant.term, variant.weight), term)
+			if weighted.Value > best.Value {
+				best = weighted
+			}
+		}
+		if best.Value > 0 {
+			total += best.Value
+			details = append(details, best)
+		}
+	}
+
+	return models.Explain(total, fmt.Sprintf("BM25F, sum of the matched terms analyzed by %q", doc.analyzer.Name()), details...), true
+}
+
+// termExplanation explains termScore, the caller must hold the read lock
+func (ix *Index) termExplanation(term string, id uint, params BM25Params, fields []Field) models.Explanation {
+	posting, ok := ix.postings[term][id]
+	if !ok {
+		return models.Explanation{}
+	}
+	doc := ix.docs[id]
+
+	tf := 0.0
+	var fieldDetails []models.Explanation
+	for _, field := range fields {
+		freq := posting.Freq(field)
+		if freq == 0 {
+			continue
+		}
+		norm := 1 - params.B
+		avg := ix.avgFieldLength(field)
+		if avg > 0 {
+			norm += params.B * float64(doc.lengths[field]) / avg
+		}
+		fieldTF := params.weight(field) * float64(freq) / norm
+		tf += fieldTF
+
+		fieldDetails = append(fieldDetails, models.Explain(fieldTF,
+			fmt.Sprintf("%s, weight * freq / (1 - b + b * length / avg length)", field),
+			models.Explain(params.weight(field), "weight"),
+			models.Explain(float64(freq), "freq"),
+			models.Explain(float64(doc.lengths[field]), "length"),
+			models.Explain(avg, "avg length"),
+			models.Explain(params.B, "b"),
+		))
+	}
+
+	idf := ix.idf(term)
+	saturation := tf * (params.K1 + 1) / (tf + params.K1)
+	return models.Explain(idf*saturation, "idf * tf * (k1 + 1) / (tf + k1)",
+		models.Explain(idf, "idf, ln(1 + (N - df + 0.5) / (df + 0.5))",
+			models.Explain(float64(len(ix.docs)), "N, documents"),
+			models.Explain(float64(len(ix.postings[term])), "df, documents containing the term"),
+		),
+		models.Explain(tf, "tf, sum over the fields", fieldDetails...),
+		models.Explain(params.K1, "k1"),
+	)
+}
@@ -0,0 +1,28 @@
+package index
+
+import "sort"
+
+// FuseRanks merges rankings with reciprocal-rank fusion, each document
+// scores the sum of 1 / (k + rank) over the rankings it appears in, ranks
+// starting at 1. Only the order of each ranking matters, so rankings with
+// incomparable scores can be combined.
+func FuseRanks(k float64, rankings ...[]Hit) []Hit {
+	scores := make(map[uint]float64)
+	for _, ranking := range rankings {
+		for rank, hit := range ranking {
+			scores[hit.DocID] += 1 / (k + float64(rank+1))
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{DocID: id, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].DocID < hits[j].DocID
+	})
+	return hits
+}
@@ -0,0 +1,123 @@
+package index
+
+import (
+	"unicode/utf8"
+)
+
+// FuzzyParams controls typo-tolerant term matching, the zero value disables it
+type FuzzyParams struct {
+	// OneEditMinLength is the shortest term length allowed one edit
+	OneEditMinLength int
+	// TwoEditsMinLength is the shortest term length allowed two edits
+	TwoEditsMinLength int
+}
+
+// DefaultFuzzyParams returns the default fuzzy matching thresholds
+func DefaultFuzzyParams() FuzzyParams {
+	return FuzzyParams{
+		OneEditMinLength:  4,
+		TwoEditsMinLength: 8,
+	}
+}
+
+// MaxDistance returns the number of edits allowed for a term
+func (p FuzzyParams) MaxDistance(term string) int {
+	length := utf8.RuneCountInString(term)
+	switch {
+	case p.TwoEditsMinLength > 0 && length >= p.TwoEditsMinLength:
+		return 2
+	case p.OneEditMinLength > 0 && length >= p.OneEditMinLength:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Options holds the parameters used to execute a query
+type Options struct {
+	BM25  BM25Params
+	Fuzzy FuzzyParams
+}
+
+// DefaultOptions returns the default query execution parameters
+func DefaultOptions() Options {
+	return Options{
+		BM25:  DefaultBM25Params(),
+		Fuzzy: DefaultFuzzyParams(),
+	}
+}
+
+// weightedTerm is a vocabulary term standing in for a query term
+type weightedTerm struct {
+	term   string
+	weight float64
+}
+
+// expand returns the vocabulary terms matching a query term, weighting fuzzy
+// variants down by their edit distance, the caller must hold the read lock
+func (ix *Index) expand(term string, fuzzy FuzzyParams) []weightedTerm {
+	variants := []weightedTerm{{term: term, weight: 1}}
+
+	maxDistance := fuzzy.MaxDistance(term)
+	if maxDistance == 0 {
+		return variants
+	}
+
+	for _, match := range ix.vocabulary().search(term, maxDistance) {
+		if match.distance > 0 {
+			variants = append(variants, weightedTerm{
+				term:   match.term,
+				weight: 1 / float64(1+match.distance),
+			})
+		}
+	}
+	return variants
+}
+
+// Correct returns the indexed word closest to a query word within the
+// allowed edit distance of its analyzed term, if that word occurs in more
+// documents than the query word itself
+func (ix *Index) Correct(word string, fuzzy FuzzyParams) (string, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	best, bestDistance, bestFreq := "", 0, 0
+	for _, analyzer := range ix.analyzers() {
+		term := analyzer.Term(word)
+		maxDistance := fuzzy.MaxDistance(term)
+		if maxDistance == 0 {
+			continue
+		}
+
+		for _, match := range ix.vocabulary().search(term, maxDistance) {
+			freq := len(ix.postings[match.term])
+			if match.distance == 0 || freq <= len(ix.postings[term]) {
+				continue
+			}
+			if best == "" || match.distance < bestDistance || (match.distance == bestDistance && freq > bestFreq) {
+				best, bestDistance, bestFreq = match.term, match.distance, freq
+			}
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+	return ix.surfaces[best], true
+}
+
+// vocabulary returns the BK-tree of indexed terms, rebuilding it if the index
+// changed since it was last built, the caller must hold the read lock
+func (ix *Index) vocabulary() *bkTree {
+	ix.vocabMu.Lock()
+	defer ix.vocabMu.Unlock()
+
+	if ix.vocab == nil || ix.vocabDirty {
+		tree := &bkTree{}
+		for term := range ix.postings {
+			tree.insert(term)
+		}
+		ix.vocab = tree
+		ix.vocabDirty = false
+	}
+	return ix.vocab
+}
@@ -0,0 +1,274 @@
+package index
+
+import (
+	"sort"
+	"strings"
+
+	"search-engine-service/internal/analysis"
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/query"
+)
+
+// Highlighter marks the terms of a query within content fields
+type Highlighter struct {
+	languages map[*analysis.Analyzer]*fieldTerms
+}
+
+// fieldTerms holds the query terms of one analyzer per field
+type fieldTerms struct {
+	// terms holds the single terms and their fuzzy variants
+	terms [numFields]map[string]bool
+	// phrases holds the term sequences of phrases
+	phrases [numFields][][]string
+}
+
+// span is a byte range of text to highlight
+type span struct {
+	start, end int
+}
+
+// Highlighter collects the positive terms and phrases of a query for each
+// indexed language, expanding single terms to the fuzzy variants they match
+func (ix *Index) Highlighter(node query.Node, opts Options) *Highlighter {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	h := &Highlighter{languages: make(map[*analysis.Analyzer]*fieldTerms)}
+	for _, analyzer := range ix.analyzers() {
+		ft := &fieldTerms{}
+		for i := range ft.terms {
+			ft.terms[i] = make(map[string]bool)
+		}
+		h.languages[analyzer] = ft
+
+		query.Walk(node, func(n query.Node, negated bool) {
+			if negated {
+				return
+			}
+
+			var field, text string
+			fuzzy := false
+			switch n := n.(type) {
+			case *query.TermNode:
+				field, text, fuzzy = n.Field, n.Text, true
+			case *query.PhraseNode:
+				field, text = n.Field, n.Text
+			default:
+				return
+			}
+			if field == query.FieldType {
+				return
+			}
+
+			tokens := analyzer.Analyze(text)
+			for _, f := range fieldsFor(field) {
+				switch {
+				case len(tokens) == 1 && fuzzy:
+					for _, variant := range ix.expand(tokens[0].Term, opts.Fuzzy) {
+						ft.terms[f][variant.term] = true
+					}
+				case len(tokens) == 1:
+					ft.terms[f][tokens[0].Term] = true
+				case len(tokens) > 1:
+					terms := make([]string, len(tokens))
+					for i, token := range tokens {
+						terms[i] = token.Term
+					}
+					ft.phrases[f] = append(ft.phrases[f], terms)
+				}
+			}
+		})
+	}
+	return h
+}
+
+// Highlight returns the highlighted fragments of the content fields that
+// contain query terms, keyed by field name
+func (h *Highlighter) Highlight(content *models.Content, opts models.HighlightOptions) map[string][]string {
+	analyzer := analysis.For(content.Language)
+	ft, ok := h.languages[analyzer]
+	if !ok {
+		return nil
+	}
+
+	highlights := make(map[string][]string)
+
+	titleTokens := analyzer.Analyze(content.Title)
+	if spans := ft.spans(FieldTitle, titleTokens); len(spans) > 0 {
+		highlights[FieldTitle.String()] = fragments(content.Title, titleTokens, spans, opts)
+	}
+	descriptionTokens := analyzer.Analyze(content.Description)
+	if spans := ft.spans(FieldDescription, descriptionTokens); len(spans) > 0 {
+		highlights[FieldDescription.String()] = fragments(content.Description, descriptionTokens, spans, opts)
+	}
+	if spans := ft.spans(FieldTags, analyzer.AnalyzeTags(content.Tags)); len(spans) > 0 {
+		highlights[FieldTags.String()] = tagFragments(content.Tags, spans, opts)
+	}
+
+	if len(highlights) == 0 {
+		return nil
+	}
+	return highlights
+}
+
+// spans returns the merged byte ranges of the tokens matching a query term or
+// phrase within a field
+func (ft *fieldTerms) spans(field Field, tokens []analysis.Token) []span {
+	var spans []span
+	for i, token := range tokens {
+		if ft.terms[field][token.Term] {
+			spans = append(spans, span{token.Start, token.End})
+		}
+		for _, phrase := range ft.phrases[field] {
+			if phraseAt(tokens, i, phrase) {
+				spans = append(spans, span{token.Start, tokens[i+len(phrase)-1].End})
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+
+	// Merge overlapping spans, e.g. a term inside a matched phrase
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	merged := spans[:1]
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s.start <= last.end {
+			if s.end > last.end {
+				last.end = s.end
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// phraseAt reports whether the phrase terms occur at consecutive positions
+// starting at token i
+func phraseAt(tokens []analysis.Token, i int, phrase []string) bool {
+	if i+len(phrase) > len(tokens) {
+		return false
+	}
+	for j, term := range phrase {
+		token := tokens[i+j]
+		if token.Term != term || token.Position != tokens[i].Position+j {
+			return false
+		}
+	}
+	return true
+}
+
+// fragments cuts snippets of about the fragment size around the spans,
+// keeping whole words
+func fragments(text string, tokens []analysis.Token, spans []span, opts models.HighlightOptions) []string {
+	var result []string
+	for i := 0; i < len(spans) && len(result) < opts.MaxFragments; {
+		first := spans[i]
+
+		// Center the fragment on the first span it holds
+		from := first.start - (opts.FragmentSize-(first.end-first.start))/2
+		if from < 0 {
+			from = 0
+		}
+		to := from + opts.FragmentSize
+		if to > len(text) {
+			to = len(text)
+			from = to - opts.FragmentSize
+			if from < 0 {
+				from = 0
+			}
+		}
+		if from > first.start {
+			from = first.start
+		}
+		if to < first.end {
+			to = first.end
+		}
+		from, to = snapToWords(tokens, from, to, len(text))
+
+		j := i
+		for j < len(spans) && spans[j].end <= to {
+			j++
+		}
+		result = append(result, mark(text, from, to, spans[i:j], opts))
+		i = j
+	}
+	return result
+}
+
+// snapToWords moves the fragment bounds inwards so no word is cut, leading
+// and trailing punctuation is kept at the text bounds
+func snapToWords(tokens []analysis.Token, from, to, length int) (int, int) {
+	if len(tokens) == 0 {
+		return from, to
+	}
+
+	if from <= tokens[0].Start {
+		from = 0
+	} else {
+		for _, token := range tokens {
+			if token.Start >= from {
+				from = token.Start
+				break
+			}
+		}
+	}
+
+	if to >= tokens[len(tokens)-1].End {
+		to = length
+	} else {
+		for i := len(tokens) - 1; i >= 0; i-- {
+			if tokens[i].End <= to {
+				to = tokens[i].End
+				break
+			}
+		}
+	}
+	return from, to
+}
+
+// tagFragments returns each tag containing a span as a fragment
+func tagFragments(tags string, spans []span, opts models.HighlightOptions) []string {
+	var result []string
+	offset := 0
+	for _, tag := range strings.Split(tags, ",") {
+		start, end := offset, offset+len(tag)
+		offset = end + 1
+
+		var tagSpans []span
+		for _, s := range spans {
+			if s.start >= start && s.end <= end {
+				tagSpans = append(tagSpans, s)
+			}
+		}
+		if len(tagSpans) == 0 {
+			continue
+		}
+
+		// Drop the whitespace around the tag
+		trimmedStart := start + len(tag) - len(strings.TrimLeft(tag, " "))
+		trimmedEnd := start + len(strings.TrimRight(tag, " "))
+		result = append(result, mark(tags, trimmedStart, trimmedEnd, tagSpans, opts))
+		if len(result) == opts.MaxFragments {
+			break
+		}
+	}
+	return result
+}
+
+// mark returns text[from:to] with the spans wrapped in the pre and post tags
+func mark(text string, from, to int, spans []span, opts models.HighlightOptions) string {
+	var b strings.Builder
+	pos := from
+	for _, s := range spans {
+		b.WriteString(text[pos:s.start])
+		b.WriteString(opts.PreTag)
+		b.WriteString(text[s.start:s.end])
+		b.WriteString(opts.PostTag)
+		pos = s.end
+	}
+	b.WriteString(text[pos:to])
+	return b.String()
+}
@@ -0,0 +1,235 @@
+// Package index implements an in-process inverted index over content so
+// searches no longer need to scan the contents table
+package index
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"search-engine-service/internal/analysis"
+	"search-engine-service/internal/database/models"
+)
+
+// Field identifies an indexed content field
+type Field int
+
+const (
+	FieldTitle Field = iota
+	FieldDescription
+	FieldTags
+
+	numFields = 3
+)
+
+// Fields lists all indexed fields
+var Fields = []Field{FieldTitle, FieldDescription, FieldTags}
+
+// String returns the field name
+func (f Field) String() string {
+	switch f {
+	case FieldTitle:
+		return "title"
+	case FieldDescription:
+		return "description"
+	case FieldTags:
+		return "tags"
+	default:
+		return "unknown"
+	}
+}
+
+// Posting records the occurrences of a term in a single document
+type Posting struct {
+	DocID     uint
+	Positions [numFields][]int
+}
+
+// Freq returns the term frequency within the given field
+func (p *Posting) Freq(field Field) int {
+	return len(p.Positions[field])
+}
+
+// TotalFreq returns the term frequency across all fields
+func (p *Posting) TotalFreq() int {
+	total := 0
+	for _, positions := range p.Positions {
+		total += len(positions)
+	}
+	return total
+}
+
+// document holds the per-document data needed to update and score the index
+type document struct {
+	terms       []string
+	lengths     [numFields]int
+	contentType models.ContentType
+	analyzer    *analysis.Analyzer
+}
+
+// Index is a thread-safe inverted index of content titles, descriptions and
+// tags, each document analyzed by the analyzer of its language
+type Index struct {
+	mu           sync.RWMutex
+	postings     map[string]map[uint]*Posting
+	docs         map[uint]*document
+	totalLengths [numFields]int
+
+	// languages counts the documents per analyzer, queries are analyzed once
+	// for each of them
+	languages map[*analysis.Analyzer]int
+	// surfaces maps each term to a word it was indexed from
+	surfaces map[string]string
+
+	vocabMu    sync.Mutex
+	vocab      *bkTree
+	vocabDirty bool
+}
+
+// New creates an empty index
+func New() *Index {
+	return &Index{
+		postings:  make(map[string]map[uint]*Posting),
+		docs:      make(map[uint]*document),
+		languages: make(map[*analysis.Analyzer]int),
+		surfaces:  make(map[string]string),
+	}
+}
+
+// Add indexes a content item, replacing any previous version of it
+func (ix *Index) Add(content *models.Content) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.add(content)
+}
+
+// AddBatch indexes multiple content items under a single lock
+func (ix *Index) AddBatch(contents []models.Content) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	for i := range contents {
+		ix.add(&contents[i])
+	}
+}
+
+// Remove deletes a content item from the index
+func (ix *Index) Remove(id uint) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(id)
+}
+
+// Len returns the number of indexed documents
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return len(ix.docs)
+}
+
+// DocFreq returns the number of documents containing the term
+func (ix *Index) DocFreq(term string) int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return len(ix.postings[term])
+}
+
+// Postings returns a copy of the postings of a term ordered by document ID
+func (ix *Index) Postings(term string) []Posting {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	list := ix.postings[term]
+	result := make([]Posting, 0, len(list))
+	for _, posting := range list {
+		result = append(result, *posting)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].DocID < result[j].DocID })
+	return result
+}
+
+// analyzers returns the analyzers of the indexed documents ordered by name,
+// the caller must hold the read lock
+func (ix *Index) analyzers() []*analysis.Analyzer {
+	analyzers := make([]*analysis.Analyzer, 0, len(ix.languages))
+	for analyzer := range ix.languages {
+		analyzers = append(analyzers, analyzer)
+	}
+	sort.Slice(analyzers, func(i, j int) bool { return analyzers[i].Name() < analyzers[j].Name() })
+	return analyzers
+}
+
+// add indexes a content item, the caller must hold the write lock
+func (ix *Index) add(content *models.Content) {
+	if content.ID == 0 {
+		return
+	}
+	ix.remove(content.ID)
+	ix.vocabDirty = true
+
+	analyzer := analysis.For(content.Language)
+	texts := [numFields]string{
+		FieldTitle:       content.Title,
+		FieldDescription: content.Description,
+		FieldTags:        content.Tags,
+	}
+	fieldTokens := [numFields][]analysis.Token{
+		FieldTitle:       analyzer.Analyze(content.Title),
+		FieldDescription: analyzer.Analyze(content.Description),
+		FieldTags:        analyzer.AnalyzeTags(content.Tags),
+	}
+
+	doc := &document{contentType: content.Type, analyzer: analyzer}
+	for field, tokens := range fieldTokens {
+		doc.lengths[field] = len(tokens)
+		ix.totalLengths[field] += len(tokens)
+
+		for _, token := range tokens {
+			list, ok := ix.postings[token.Term]
+			if !ok {
+				list = make(map[uint]*Posting)
+				ix.postings[token.Term] = list
+				ix.surfaces[token.Term] = strings.ToLower(texts[field][token.Start:token.End])
+			}
+			posting, ok := list[content.ID]
+			if !ok {
+				posting = &Posting{DocID: content.ID}
+				list[content.ID] = posting
+				doc.terms = append(doc.terms, token.Term)
+			}
+			posting.Positions[field] = append(posting.Positions[field], token.Position)
+		}
+	}
+
+	ix.docs[content.ID] = doc
+	ix.languages[analyzer]++
+}
+
+// remove deletes a document, the caller must hold the write lock
+func (ix *Index) remove(id uint) {
+	doc, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+
+	ix.vocabDirty = true
+	for _, term := range doc.terms {
+		delete(ix.postings[term], id)
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+			delete(ix.surfaces, term)
+		}
+	}
+	for field, length := range doc.lengths {
+		ix.totalLengths[field] -= length
+	}
+	if ix.languages[doc.analyzer]--; ix.languages[doc.analyzer] == 0 {
+		delete(ix.languages, doc.analyzer)
+	}
+
+	delete(ix.docs, id)
+}
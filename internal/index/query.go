@@ -0,0 +1,388 @@
+package index
+
+import (
+	"strings"
+
+	"search-engine-service/internal/analysis"
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/query"
+)
+
+// docSet is a set of document IDs
+type docSet map[uint]struct{}
+
+// evalResult holds the documents a node matches and the documents it does
+// not constrain, as its text analyzes to nothing in their language, e.g.
+// stop words only. Enclosing clauses skip the node for the latter.
+type evalResult struct {
+	matched docSet
+	void    docSet
+}
+
+// scoringClause is a positive query term contributing to the BM25F score of
+// the documents of its analyzer through the best scoring of its variants
+type scoringClause struct {
+	analyzer *analysis.Analyzer
+	variants []weightedTerm
+	fields   []Field
+}
+
+// executor evaluates a single query against the index
+type executor struct {
+	ix   *Index
+	opts Options
+}
+
+// Execute evaluates a parsed query against the index, returning the matching
+// documents scored with BM25F over their positive terms
+func (ix *Index) Execute(node query.Node, opts Options) []Hit {
+	if node == nil {
+		return nil
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	ex := &executor{ix: ix, opts: opts}
+	matched := ex.eval(node).matched
+	clauses := ex.scoringClauses(node)
+
+	hits := make([]Hit, 0, len(matched))
+	for id := range matched {
+		score := 0.0
+		for _, clause := range clauses {
+			if clause.analyzer != ix.docs[id].analyzer {
+				continue
+			}
+			best := 0.0
+			for _, variant := range clause.variants {
+				variantScore := variant.weight * ix.termScore(variant.term, id, opts.BM25, clause.fields)
+				if variantScore > best {
+					best = variantScore
+				}
+			}
+			score += best
+		}
+		hits = append(hits, Hit{DocID: id, Score: score})
+	}
+
+	sortHits(hits)
+	return hits
+}
+
+// eval returns the documents matching a node. Clauses that do not constrain
+// a document are skipped for it, and a document no clause constrains is not
+// matched.
+func (ex *executor) eval(node query.Node) evalResult {
+	ix := ex.ix
+
+	switch n := node.(type) {
+	case *query.TermNode:
+		return ex.evalText(n.Field, n.Text, true)
+
+	case *query.PhraseNode:
+		return ex.evalText(n.Field, n.Text, false)
+
+	case *query.AndNode:
+		var positive, negative []query.Node
+		for _, child := range n.Children {
+			if not, ok := child.(*query.NotNode); ok {
+				negative = append(negative, not.Child)
+			} else {
+				positive = append(positive, child)
+			}
+		}
+
+		var result, void docSet
+		if len(positive) == 0 {
+			result = ix.allDocs()
+		}
+		for i, child := range positive {
+			r := ex.eval(child)
+			if i == 0 {
+				result, void = union(r.matched, r.void), r.void
+				continue
+			}
+			result = intersect(result, union(r.matched, r.void))
+			void = intersect(void, r.void)
+		}
+		for i, child := range negative {
+			r := ex.eval(child)
+			result = subtract(result, r.matched)
+			if i == 0 && len(positive) == 0 {
+				void = r.void
+				continue
+			}
+			void = intersect(void, r.void)
+		}
+		return evalResult{matched: subtract(result, void), void: void}
+
+	case *query.OrNode:
+		var matched, void docSet
+		for i, child := range n.Children {
+			r := ex.eval(child)
+			if i == 0 {
+				matched, void = r.matched, r.void
+				continue
+			}
+			matched = union(matched, r.matched)
+			void = intersect(void, r.void)
+		}
+		return evalResult{matched: matched, void: subtract(void, matched)}
+
+	case *query.NotNode:
+		r := ex.eval(n.Child)
+		return evalResult{matched: subtract(subtract(ix.allDocs(), r.matched), r.void), void: r.void}
+
+	default:
+		return evalResult{matched: make(docSet)}
+	}
+}
+
+// evalText matches a term or phrase within a field, single terms may match
+// fuzzily when allowed. The text is analyzed once per indexed language and
+// matched against the documents of that language only, the documents of a
+// language it analyzes to nothing in are void.
+func (ex *executor) evalText(field, text string, fuzzy bool) evalResult {
+	ix := ex.ix
+	if field == query.FieldType {
+		return evalResult{matched: ix.typeDocs(models.ContentType(strings.ToLower(text)))}
+	}
+
+	result := evalResult{matched: make(docSet), void: make(docSet)}
+	for _, analyzer := range ix.analyzers() {
+		tokens := analyzer.Analyze(text)
+		if len(tokens) == 0 {
+			for id := range ix.analyzerDocs(analyzer) {
+				result.void[id] = struct{}{}
+			}
+			continue
+		}
+		for id := range ex.evalAnalyzed(tokens, field, fuzzy) {
+			if ix.docs[id].analyzer == analyzer {
+				result.matched[id] = struct{}{}
+			}
+		}
+	}
+	return result
+}
+
+// evalAnalyzed matches analyzed tokens within a field
+func (ex *executor) evalAnalyzed(tokens []analysis.Token, field string, fuzzy bool) docSet {
+	ix := ex.ix
+	fields := fieldsFor(field)
+
+	switch len(tokens) {
+	case 0:
+		// Nothing searchable, e.g. punctuation or stop words only
+		return make(docSet)
+	case 1:
+		if !fuzzy {
+			return ix.termDocs(tokens[0].Term, fields)
+		}
+		result := make(docSet)
+		for _, variant := range ix.expand(tokens[0].Term, ex.opts.Fuzzy) {
+			for id := range ix.termDocs(variant.term, fields) {
+				result[id] = struct{}{}
+			}
+		}
+		return result
+	default:
+		return ix.phraseDocs(tokens, fields)
+	}
+}
+
+// termDocs returns the documents containing a term in any of the fields
+func (ix *Index) termDocs(term string, fields []Field) docSet {
+	result := make(docSet)
+	for id, posting := range ix.postings[term] {
+		for _, field := range fields {
+			if posting.Freq(field) > 0 {
+				result[id] = struct{}{}
+				break
+			}
+		}
+	}
+	return result
+}
+
+// phraseDocs returns the documents containing the tokens at consecutive
+// positions within one of the fields
+func (ix *Index) phraseDocs(tokens []analysis.Token, fields []Field) docSet {
+	result := make(docSet)
+
+	for id, first := range ix.postings[tokens[0].Term] {
+		postings := make([]*Posting, len(tokens))
+		postings[0] = first
+		complete := true
+		for i, token := range tokens[1:] {
+			posting, ok := ix.postings[token.Term][id]
+			if !ok {
+				complete = false
+				break
+			}
+			postings[i+1] = posting
+		}
+		if !complete {
+			continue
+		}
+
+		for _, field := range fields {
+			if phraseInField(tokens, postings, field) {
+				result[id] = struct{}{}
+				break
+			}
+		}
+	}
+	return result
+}
+
+// phraseInField reports whether the postings contain the tokens in the same
+// relative positions within a field
+func phraseInField(tokens []analysis.Token, postings []*Posting, field Field) bool {
+	for _, start := range postings[0].Positions[field] {
+		matched := true
+		for i := 1; i < len(tokens); i++ {
+			want := start + tokens[i].Position - tokens[0].Position
+			if !containsInt(postings[i].Positions[field], want) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// typeDocs returns the documents of a content type
+func (ix *Index) typeDocs(contentType models.ContentType) docSet {
+	result := make(docSet)
+	for id, doc := range ix.docs {
+		if doc.contentType == contentType {
+			result[id] = struct{}{}
+		}
+	}
+	return result
+}
+
+// analyzerDocs returns the documents analyzed by an analyzer
+func (ix *Index) analyzerDocs(analyzer *analysis.Analyzer) docSet {
+	result := make(docSet)
+	for id, doc := range ix.docs {
+		if doc.analyzer == analyzer {
+			result[id] = struct{}{}
+		}
+	}
+	return result
+}
+
+// allDocs returns every indexed document
+func (ix *Index) allDocs() docSet {
+	result := make(docSet, len(ix.docs))
+	for id := range ix.docs {
+		result[id] = struct{}{}
+	}
+	return result
+}
+
+// scoringClauses collects the positive terms of a query, single terms are
+// expanded to their fuzzy variants
+func (ex *executor) scoringClauses(node query.Node) []scoringClause {
+	var clauses []scoringClause
+	query.Walk(node, func(n query.Node, negated bool) {
+		if negated {
+			return
+		}
+
+		var field, text string
+		fuzzy := false
+		switch n := n.(type) {
+		case *query.TermNode:
+			field, text, fuzzy = n.Field, n.Text, true
+		case *query.PhraseNode:
+			field, text = n.Field, n.Text
+		default:
+			return
+		}
+
+		if field == query.FieldType {
+			return
+		}
+
+		for _, analyzer := range ex.ix.analyzers() {
+			terms := uniqueTerms(analyzer.Analyze(text))
+			fuzzyTerm := fuzzy && len(terms) == 1
+			for _, term := range terms {
+				variants := []weightedTerm{{term: term, weight: 1}}
+				if fuzzyTerm {
+					variants = ex.ix.expand(term, ex.opts.Fuzzy)
+				}
+				clauses = append(clauses, scoringClause{
+					analyzer: analyzer,
+					variants: variants,
+					fields:   fieldsFor(field),
+				})
+			}
+		}
+	})
+	return clauses
+}
+
+// fieldsFor maps a query field name to the index fields it searches
+func fieldsFor(name string) []Field {
+	switch name {
+	case query.FieldTitle:
+		return []Field{FieldTitle}
+	case query.FieldDescription:
+		return []Field{FieldDescription}
+	case query.FieldTags:
+		return []Field{FieldTags}
+	default:
+		return Fields
+	}
+}
+
+func union(a, b docSet) docSet {
+	result := make(docSet, len(a)+len(b))
+	for id := range a {
+		result[id] = struct{}{}
+	}
+	for id := range b {
+		result[id] = struct{}{}
+	}
+	return result
+}
+
+func intersect(a, b docSet) docSet {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	result := make(docSet, len(a))
+	for id := range a {
+		if _, ok := b[id]; ok {
+			result[id] = struct{}{}
+		}
+	}
+	return result
+}
+
+func subtract(a, b docSet) docSet {
+	result := make(docSet, len(a))
+	for id := range a {
+		if _, ok := b[id]; !ok {
+			result[id] = struct{}{}
+		}
+	}
+	return result
+}
+
+func containsInt(values []int, want int) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package index
+
+import "math"
+
+// Weights of the related document signals, both of which lie in [0, 1]
+const (
+	relatedTextWeight = 0.5
+	relatedTagWeight  = 0.5
+)
+
+// Related returns up to limit documents similar to the given document,
+// scored by the TF-IDF cosine similarity of their titles and descriptions
+// and the overlap of their tags. Only documents of the same analyzer are
+// compared and, unless crossType is set, of the same content type. The
+// document itself is excluded.
+func (ix *Index) Related(id uint, crossType bool, limit int) []Hit {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	source, ok := ix.docs[id]
+	if !ok {
+		return nil
+	}
+	sourceText, sourceNorm := ix.textVector(id, source)
+	sourceTags := ix.tagSet(id, source)
+
+	// Candidates share at least one term with the source
+	candidates := make(map[uint]struct{})
+	for _, term := range source.terms {
+		for candidate := range ix.postings[term] {
+			doc := ix.docs[candidate]
+			if candidate == id || doc.analyzer != source.analyzer {
+				continue
+			}
+			if !crossType && doc.contentType != source.contentType {
+				continue
+			}
+			candidates[candidate] = struct{}{}
+		}
+	}
+
+	hits := make([]Hit, 0, len(candidates))
+	for candidate := range candidates {
+		doc := ix.docs[candidate]
+
+		text := 0.0
+		if candidateText, norm := ix.textVector(candidate, doc); sourceNorm > 0 && norm > 0 {
+			dot := 0.0
+			for term, weight := range sourceText {
+				dot += weight * candidateText[term]
+			}
+			text = dot / (sourceNorm * norm)
+		}
+		tags := jaccard(sourceTags, ix.tagSet(candidate, doc))
+
+		if score := relatedTextWeight*text + relatedTagWeight*tags; score > 0 {
+			hits = append(hits, Hit{DocID: candidate, Score: score})
+		}
+	}
+
+	sortHits(hits)
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// textVector returns the TF-IDF weights of the title and description terms
+// of a document and their norm, the caller must hold the read lock
+func (ix *Index) textVector(id uint, doc *document) (map[string]float64, float64) {
+	vector := make(map[string]float64)
+	norm := 0.0
+	for _, term := range doc.terms {
+		posting := ix.postings[term][id]
+		tf := posting.Freq(FieldTitle) + posting.Freq(FieldDescription)
+		if tf == 0 {
+			continue
+		}
+		weight := (1 + math.Log(float64(tf))) * ix.idf(term)
+		vector[term] = weight
+		norm += weight * weight
+	}
+	return vector, math.Sqrt(norm)
+}
+
+// tagSet returns the tag terms of a document, the caller must hold the read lock
+func (ix *Index) tagSet(id uint, doc *document) map[string]struct{} {
+	tags := make(map[string]struct{})
+	for _, term := range doc.terms {
+		if ix.postings[term][id].Freq(FieldTags) > 0 {
+			tags[term] = struct{}{}
+		}
+	}
+	return tags
+}
+
+// jaccard returns the size of the intersection of two sets over their union
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for term := range a {
+		if _, ok := b[term]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,153 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned instead of fetching a provider whose circuit
+// breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState is the state of a circuit breaker
+type BreakerState string
+
+const (
+	// BreakerClosed lets every fetch through
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen rejects every fetch until the open timeout elapses
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single trial fetch through, closing the
+	// breaker if it succeeds and opening it again if it fails
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+// BreakerPolicy configures a circuit breaker
+type BreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failed fetches that
+	// opens the breaker
+	FailureThreshold int `json:"failure_threshold,omitempty"`
+	// OpenTimeout is how long the breaker stays open before a trial fetch
+	OpenTimeout Duration `json:"open_timeout,omitempty"`
+}
+
+// DefaultBreakerPolicy opens after 5 consecutive failures for 30 seconds
+func DefaultBreakerPolicy() BreakerPolicy {
+	return BreakerPolicy{FailureThreshold: 5, OpenTimeout: Duration(30 * time.Second)}
+}
+
+// withDefaults fills the unset fields of the policy from another
+func (p BreakerPolicy) withDefaults(defaults BreakerPolicy) BreakerPolicy {
+	if p.FailureThreshold <= 0 {
+		p.FailureThreshold = defaults.FailureThreshold
+	}
+	if p.OpenTimeout <= 0 {
+		p.OpenTimeout = defaults.OpenTimeout
+	}
+	return p
+}
+
+// Breaker is a circuit breaker guarding the fetches of a provider, safe for
+// concurrent use
+type Breaker struct {
+	policy BreakerPolicy
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	// trial is set while the half-open trial fetch runs
+	trial   bool
+	lastErr string
+}
+
+// BreakerSnapshot is the observable state of a circuit breaker
+type BreakerSnapshot struct {
+	State               BreakerState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+	// RetryAt is when an open breaker lets a trial fetch through
+	RetryAt   *time.Time `json:"retry_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+// NewBreaker creates a closed circuit breaker
+func NewBreaker(policy BreakerPolicy) *Breaker {
+	return &Breaker{
+		policy: policy.withDefaults(DefaultBreakerPolicy()),
+		state:  BreakerClosed,
+	}
+}
+
+// Allow reports whether a fetch may run, ErrCircuitOpen when the breaker is
+// open or its half-open trial is already running. Every allowed fetch must
+// be followed by Record.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= time.Duration(b.policy.OpenTimeout) {
+		b.state = BreakerHalfOpen
+	}
+
+	switch b.state {
+	case BreakerOpen:
+		return ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.trial {
+			return ErrCircuitOpen
+		}
+		b.trial = true
+	}
+	return nil
+}
+
+// Record records the outcome of an allowed fetch. A cancelled fetch says
+// nothing about the provider and only ends a half-open trial.
+func (b *Breaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	if err == nil {
+		b.state = BreakerClosed
+		b.failures = 0
+		b.lastErr = ""
+		return
+	}
+
+	b.failures++
+	b.lastErr = err.Error()
+	if b.state == BreakerHalfOpen || b.failures >= b.policy.FailureThreshold {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// Snapshot returns the current state of the breaker. An open breaker whose
+// timeout elapsed reports half-open, the next fetch being its trial.
+func (b *Breaker) Snapshot() BreakerSnapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	snapshot := BreakerSnapshot{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		LastError:           b.lastErr,
+	}
+	if b.state != BreakerClosed {
+		openedAt := b.openedAt
+		retryAt := openedAt.Add(time.Duration(b.policy.OpenTimeout))
+		snapshot.OpenedAt, snapshot.RetryAt = &openedAt, &retryAt
+		if b.state == BreakerOpen && !time.Now().Before(retryAt) {
+			snapshot.State = BreakerHalfOpen
+		}
+	}
+	return snapshot
+}
@@ -0,0 +1,117 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"search-engine-service/internal/database/models"
+)
+
+// Duration is a time.Duration read from JSON as a string such as "30s"
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\"")
+	}
+	if s == "" {
+		*d = 0
+		return nil
+	}
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// MarshalJSON formats the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// InstanceConfig declares a provider instance
+type InstanceConfig struct {
+	// Name identifies the instance and is stored as the provider of its
+	// contents, so it must be unique and stable
+	Name string `json:"name"`
+	// Kind selects the registered factory building the instance
+	Kind string `json:"kind"`
+	URL  string `json:"url"`
+	// Timeout bounds a fetch, zero uses the manager's default timeout
+	Timeout Duration `json:"timeout,omitempty"`
+	// Headers are sent with every request of the instance
+	Headers map[string]string `json:"headers,omitempty"`
+	// ContentType is the type of the fetched contents, empty uses the
+	// default of the kind
+	ContentType models.ContentType `json:"content_type,omitempty"`
+	// Schedule is how often the instance is refreshed in the background,
+	// zero refreshes it only on request
+	Schedule Duration `json:"schedule,omitempty"`
+	// Mapping maps the feed items to contents, nil uses the default
+	// mapping of the kind
+	Mapping *Mapping `json:"mapping,omitempty"`
+	// Retry and Breaker override the manager's retry and circuit breaker
+	// policies field by field
+	Retry   RetryPolicy   `json:"retry"`
+	Breaker BreakerPolicy `json:"breaker"`
+}
+
+// Validate checks the fields every kind needs
+func (c *InstanceConfig) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("provider name is required")
+	}
+	if c.Kind == "" {
+		return fmt.Errorf("provider %s: kind is required", c.Name)
+	}
+	if c.URL == "" {
+		return fmt.Errorf("provider %s: url is required", c.Name)
+	}
+	if c.Timeout < 0 || c.Schedule < 0 {
+		return fmt.Errorf("provider %s: timeout and schedule must not be negative", c.Name)
+	}
+	return nil
+}
+
+// FileConfig is the structure of a providers configuration file
+type FileConfig struct {
+	Providers []InstanceConfig `json:"providers"`
+}
+
+// LoadConfig reads the provider instances from a JSON file
+func LoadConfig(path string) ([]InstanceConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config FileConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid providers %s: %w", path, err)
+	}
+	if len(config.Providers) == 0 {
+		return nil, fmt.Errorf("invalid providers %s: no provider declared", path)
+	}
+	return config.Providers, nil
+}
+
+// Names of the default JSON video and XML article providers
+const (
+	DefaultJSONName = "json_provider"
+	DefaultXMLName  = "xml_provider"
+)
+
+// DefaultInstances returns the JSON video and XML article providers used
+// without a providers file
+func DefaultInstances(jsonURL, xmlURL string) []InstanceConfig {
+	return []InstanceConfig{
+		{Name: DefaultJSONName, Kind: KindJSON, URL: jsonURL, ContentType: models.ContentTypeVideo},
+		{Name: DefaultXMLName, Kind: KindXML, URL: xmlURL, ContentType: models.ContentTypeText},
+	}
+}
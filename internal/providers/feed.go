@@ -0,0 +1,197 @@
+package providers
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// jsonRecord is an item of a JSON feed, read with dotted paths such as
+// stats.views, media[0].url or tags[*].name. Arrays without an index are
+// traversed element by element.
+type jsonRecord struct {
+	node interface{}
+}
+
+// parseJSONFeed returns the items of a JSON document at a path, the whole
+// document when the path is empty
+func parseJSONFeed(body []byte, items string) ([]record, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	var records []record
+	for _, node := range jsonNodes(document, jsonPath(items)) {
+		records = append(records, jsonRecord{node})
+	}
+	return records, nil
+}
+
+// jsonPath splits a JSON path into its segments
+func jsonPath(path string) []string {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = strings.ReplaceAll(path, "[*]", "")
+	path = strings.ReplaceAll(strings.ReplaceAll(path, "[", "."), "]", "")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+// jsonNodes returns the nodes at a path, with the arrays it ends on flattened
+func jsonNodes(node interface{}, segments []string) []interface{} {
+	if array, ok := node.([]interface{}); ok {
+		if len(segments) > 0 {
+			if index, err := strconv.Atoi(segments[0]); err == nil {
+				if index < 0 || index >= len(array) {
+					return nil
+				}
+				return jsonNodes(array[index], segments[1:])
+			}
+		}
+
+		var nodes []interface{}
+		for _, element := range array {
+			nodes = append(nodes, jsonNodes(element, segments)...)
+		}
+		return nodes
+	}
+
+	if len(segments) == 0 {
+		return []interface{}{node}
+	}
+	if object, ok := node.(map[string]interface{}); ok {
+		if child, ok := object[segments[0]]; ok {
+			return jsonNodes(child, segments[1:])
+		}
+	}
+	return nil
+}
+
+func (r jsonRecord) values(path string) []string {
+	var values []string
+	for _, node := range jsonNodes(r.node, jsonPath(path)) {
+		switch value := node.(type) {
+		case string:
+			values = append(values, value)
+		case json.Number:
+			values = append(values, value.String())
+		case bool:
+			values = append(values, strconv.FormatBool(value))
+		}
+	}
+	return values
+}
+
+// xmlNode is an element of an XML document
+type xmlNode struct {
+	name     string
+	attrs    map[string]string
+	text     strings.Builder
+	children []*xmlNode
+}
+
+// xmlRecord is an item of an XML feed, read with slash-separated element
+// paths such as stats/views, ending on @name for an attribute
+type xmlRecord struct {
+	node *xmlNode
+}
+
+// parseXMLFeed returns the items of an XML document at a path starting with
+// the root element, such as articles/article or rss/channel/item
+func parseXMLFeed(body []byte, items string) ([]record, error) {
+	document, err := parseXML(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal XML: %w", err)
+	}
+
+	var records []record
+	for _, node := range xmlNodes(document, strings.Trim(items, "/")) {
+		records = append(records, xmlRecord{node})
+	}
+	return records, nil
+}
+
+// parseXML reads an XML document into a tree under a nameless document node
+func parseXML(body []byte) (*xmlNode, error) {
+	document := &xmlNode{}
+	stack := []*xmlNode{document}
+
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name.Local, attrs: make(map[string]string, len(t.Attr))}
+			for _, attr := range t.Attr {
+				node.attrs[attr.Name.Local] = attr.Value
+			}
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, node)
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			stack[len(stack)-1].text.Write(t)
+		}
+	}
+
+	if len(document.children) == 0 {
+		return nil, fmt.Errorf("no root element")
+	}
+	return document, nil
+}
+
+// xmlNodes returns the elements at a slash-separated path
+func xmlNodes(node *xmlNode, path string) []*xmlNode {
+	nodes := []*xmlNode{node}
+	if path == "" {
+		return nodes
+	}
+
+	for _, name := range strings.Split(path, "/") {
+		var next []*xmlNode
+		for _, parent := range nodes {
+			for _, child := range parent.children {
+				if child.name == name {
+					next = append(next, child)
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+func (r xmlRecord) values(path string) []string {
+	path = strings.Trim(path, "/")
+	elements, attr := path, ""
+	if i := strings.LastIndex(path, "@"); i >= 0 {
+		elements, attr = strings.TrimSuffix(path[:i], "/"), path[i+1:]
+	}
+
+	var values []string
+	for _, node := range xmlNodes(r.node, elements) {
+		if attr == "" {
+			values = append(values, node.text.String())
+		} else if value, ok := node.attrs[attr]; ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,89 @@
+package providers
+
+import "time"
+
+// HealthStatus is the health of a provider or of all providers
+type HealthStatus string
+
+const (
+	// HealthHealthy is a provider whose last fetch succeeded, or one never
+	// fetched
+	HealthHealthy HealthStatus = "healthy"
+	// HealthDegraded is a provider whose last fetch failed without opening
+	// its circuit breaker, or whose breaker awaits a trial fetch
+	HealthDegraded HealthStatus = "degraded"
+	// HealthUnhealthy is a provider whose circuit breaker is open
+	HealthUnhealthy HealthStatus = "unhealthy"
+)
+
+// ProviderHealth is the health of a provider
+type ProviderHealth struct {
+	Name    string          `json:"name"`
+	URL     string          `json:"url"`
+	Status  HealthStatus    `json:"status"`
+	Circuit BreakerSnapshot `json:"circuit"`
+	// LastCheck, ResponseTime, LastStatus and LastError describe the last
+	// fetch, absent before the first one
+	LastCheck    *time.Time  `json:"last_check,omitempty"`
+	ResponseTime string      `json:"response_time,omitempty"`
+	LastStatus   FetchStatus `json:"last_status,omitempty"`
+	LastError    string      `json:"last_error,omitempty"`
+}
+
+// Health returns the health of every provider, from its circuit breaker and
+// its last fetch
+func (pm *ProviderManager) Health() []ProviderHealth {
+	health := make([]ProviderHealth, 0, len(pm.providers))
+	for _, provider := range pm.providers {
+		name := provider.GetName()
+		h := ProviderHealth{
+			Name:    name,
+			URL:     provider.GetURL(),
+			Status:  HealthHealthy,
+			Circuit: pm.breakers[name].Snapshot(),
+		}
+
+		pm.mu.Lock()
+		result, fetched := pm.lastResult[name]
+		pm.mu.Unlock()
+		if fetched {
+			checked := result.FetchedAt
+			h.LastCheck = &checked
+			h.ResponseTime = time.Duration(result.Duration).String()
+			h.LastStatus = result.Status
+			h.LastError = result.Error
+		}
+
+		switch {
+		case h.Circuit.State == BreakerOpen:
+			h.Status = HealthUnhealthy
+		case h.Circuit.State == BreakerHalfOpen, fetched && result.Err != nil:
+			h.Status = HealthDegraded
+		}
+		health = append(health, h)
+	}
+	return health
+}
+
+// OverallHealth summarizes the health of providers: healthy when all are,
+// unhealthy when none can be fetched and degraded otherwise
+func OverallHealth(health []ProviderHealth) HealthStatus {
+	healthy, unhealthy := 0, 0
+	for _, h := range health {
+		switch h.Status {
+		case HealthHealthy:
+			healthy++
+		case HealthUnhealthy:
+			unhealthy++
+		}
+	}
+
+	switch {
+	case healthy == len(health):
+		return HealthHealthy
+	case unhealthy == len(health):
+		return HealthUnhealthy
+	default:
+		return HealthDegraded
+	}
+}
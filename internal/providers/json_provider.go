@@ -2,51 +2,79 @@ package providers
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
 	"time"
 
 	"search-engine-service/internal/database/models"
 )
 
+// KindJSON is the kind of JSON providers
+const KindJSON = "json"
+
+func init() {
+	Register(KindJSON, func(config InstanceConfig) (Provider, error) {
+		return NewJSONProvider(config)
+	})
+}
+
 // JSONProvider implements Provider interface for JSON data sources
 type JSONProvider struct {
-	url     string
-	timeout time.Duration
+	name        string
+	url         string
+	timeout     time.Duration
+	contentType models.ContentType
+	mapping     *Mapping
+	client      *FeedClient
 }
 
-// JSONVideoResponse represents the structure of JSON video data
-type JSONVideoResponse struct {
-	Videos []JSONVideo `json:"videos"`
+// DefaultJSONMapping maps the items of a {"videos": [...]} document with
+// fields named after the content fields
+func DefaultJSONMapping() *Mapping {
+	return &Mapping{
+		Items: "videos",
+		Fields: map[string]FieldMapping{
+			"id":           {Path: "id"},
+			"title":        {Path: "title"},
+			"description":  {Path: "description"},
+			"url":          {Path: "url"},
+			"views":        {Path: "views"},
+			"likes":        {Path: "likes"},
+			"duration":     {Path: "duration"},
+			"tags":         {Path: "tags", Separator: ","},
+			"language":     {Path: "language"},
+			"published_at": {Path: "published_at"},
+		},
+	}
 }
 
-// JSONVideo represents a single video from JSON provider
-type JSONVideo struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	URL         string    `json:"url"`
-	Views       int       `json:"views"`
-	Likes       int       `json:"likes"`
-	Duration    int       `json:"duration"`
-	Tags        string    `json:"tags"`
-	Language    string    `json:"language"`
-	PublishedAt time.Time `json:"published_at"`
-}
+// NewJSONProvider creates a new JSON provider, fetching videos unless the
+// instance declares another content type, with the default mapping unless
+// the instance declares one
+func NewJSONProvider(config InstanceConfig) (*JSONProvider, error) {
+	contentType := config.ContentType
+	if contentType == "" {
+		contentType = models.ContentTypeVideo
+	}
+	mapping := config.Mapping
+	if mapping == nil {
+		mapping = DefaultJSONMapping()
+	}
+	if err := mapping.Validate(); err != nil {
+		return nil, err
+	}
 
-// NewJSONProvider creates a new JSON provider
-func NewJSONProvider(url string, timeout time.Duration) *JSONProvider {
 	return &JSONProvider{
-		url:     url,
-		timeout: timeout,
-	}
+		name:        config.Name,
+		url:         config.URL,
+		timeout:     time.Duration(config.Timeout),
+		contentType: contentType,
+		mapping:     mapping,
+		client:      NewFeedClient(config),
+	}, nil
 }
 
 // GetName returns the provider name
 func (jp *JSONProvider) GetName() string {
-	return "json_provider"
+	return jp.name
 }
 
 // GetURL returns the provider URL
@@ -61,66 +89,14 @@ func (jp *JSONProvider) GetTimeout() time.Duration {
 
 // FetchContent fetches content from the JSON provider
 func (jp *JSONProvider) FetchContent(ctx context.Context) ([]models.Content, error) {
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: jp.timeout,
-	}
-
-	// Create request with context
-	req, err := http.NewRequestWithContext(ctx, "GET", jp.url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "SearchEngineService/1.0")
-
-	// Make request
-	resp, err := client.Do(req)
+	body, err := jp.client.Fetch(ctx, "application/json")
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	// Check status code
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	records, err := parseJSONFeed(body, jp.mapping.Items)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Parse JSON response
-	var jsonResponse JSONVideoResponse
-	if err := json.Unmarshal(body, &jsonResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
-	}
-
-	// Convert to Content models
-	var contents []models.Content
-	for _, video := range jsonResponse.Videos {
-		content := models.Content{
-			ProviderID:  video.ID,
-			Title:       video.Title,
-			Description: video.Description,
-			URL:         video.URL,
-			Type:        models.ContentTypeVideo,
-			Views:       video.Views,
-			Likes:       video.Likes,
-			Duration:    video.Duration,
-			Tags:        video.Tags,
-			Language:    video.Language,
-			PublishedAt: video.PublishedAt,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		}
-
-		contents = append(contents, content)
+		return nil, err
 	}
-
-	return contents, nil
-} 
\ No newline at end of file
+	return jp.mapping.apply(jp.name, records, jp.contentType), nil
+}
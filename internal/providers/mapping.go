@@ -0,0 +1,291 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"search-engine-service/internal/database/models"
+)
+
+// Mapping maps the items of a feed to contents. Items is the path of the
+// repeated element holding the contents and Fields the source of every
+// content field, read relative to an item.
+type Mapping struct {
+	Items  string                  `json:"items"`
+	Fields map[string]FieldMapping `json:"fields"`
+}
+
+// FieldMapping is the source of a content field: a path into the item, or a
+// constant value. It can be written in JSON as the bare path.
+type FieldMapping struct {
+	Path string `json:"path,omitempty"`
+	// Value is a constant used instead of a path
+	Value string `json:"value,omitempty"`
+	// Default is used when the path is missing, empty or cannot be coerced
+	Default string `json:"default,omitempty"`
+	// Layouts are the time layouts a date is parsed with, tried in order:
+	// Go reference layouts, "unix" or "unix_ms". RFC 3339 by default.
+	Layouts []string `json:"layouts,omitempty"`
+	// Separator splits a tags string, tags are stored comma-separated
+	Separator string `json:"separator,omitempty"`
+}
+
+// UnmarshalJSON reads a field mapping from an object or a bare path
+func (f *FieldMapping) UnmarshalJSON(data []byte) error {
+	var path string
+	if err := json.Unmarshal(data, &path); err == nil {
+		*f = FieldMapping{Path: path}
+		return nil
+	}
+
+	type fieldMapping FieldMapping
+	return json.Unmarshal(data, (*fieldMapping)(f))
+}
+
+// fieldKind is how source values are coerced into a content field
+type fieldKind int
+
+const (
+	kindString fieldKind = iota
+	kindInt
+	kindTime
+	kindTags
+)
+
+// mappedFields are the content fields a mapping can fill and their kinds
+var mappedFields = map[string]fieldKind{
+	"id":           kindString,
+	"title":        kindString,
+	"description":  kindString,
+	"url":          kindString,
+	"type":         kindString,
+	"language":     kindString,
+	"views":        kindInt,
+	"likes":        kindInt,
+	"duration":     kindInt,
+	"reading_time": kindInt,
+	"reactions":    kindInt,
+	"tags":         kindTags,
+	"published_at": kindTime,
+}
+
+// MappedFields returns the content fields a mapping can fill, sorted
+func MappedFields() []string {
+	fields := make([]string, 0, len(mappedFields))
+	for field := range mappedFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Validate checks every field of the mapping names a content field and
+// has a source, and that constants and defaults coerce to the field
+func (m *Mapping) Validate() error {
+	if _, ok := m.Fields["id"]; !ok {
+		return fmt.Errorf("mapping: id is required")
+	}
+
+	for name, field := range m.Fields {
+		kind, ok := mappedFields[name]
+		if !ok {
+			return fmt.Errorf("mapping: unknown field %q, must be one of '%s'", name, strings.Join(MappedFields(), "', '"))
+		}
+		if (field.Path == "") == (field.Value == "") {
+			return fmt.Errorf("mapping: field %s needs either a path or a value", name)
+		}
+		if len(field.Layouts) > 0 && kind != kindTime {
+			return fmt.Errorf("mapping: field %s is not a date, it takes no layouts", name)
+		}
+		if field.Separator != "" && kind != kindTags {
+			return fmt.Errorf("mapping: field %s is not tags, it takes no separator", name)
+		}
+		for _, constant := range []string{field.Value, field.Default} {
+			if constant == "" {
+				continue
+			}
+			if _, err := field.coerce(kind, []string{constant}); err != nil {
+				return fmt.Errorf("mapping: field %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// record is a feed item the fields of a content are read from
+type record interface {
+	// values returns the scalar values at a path, arrays flattened
+	values(path string) []string
+}
+
+// apply maps the records of a feed to contents of the given default type.
+// Items without an id or with a field that cannot be coerced are skipped, so
+// one malformed item does not fail the whole feed.
+func (m *Mapping) apply(provider string, records []record, contentType models.ContentType) []models.Content {
+	var contents []models.Content
+	var skipped int
+	var firstErr error
+
+	for i, r := range records {
+		content, err := m.content(r, contentType)
+		if err != nil {
+			skipped++
+			if firstErr == nil {
+				firstErr = fmt.Errorf("item %d: %w", i, err)
+			}
+			continue
+		}
+		contents = append(contents, content)
+	}
+
+	if skipped > 0 {
+		log.Printf("Provider %s skipped %d of %d items, %v", provider, skipped, len(records), firstErr)
+	}
+	return contents
+}
+
+// content maps a record to a content
+func (m *Mapping) content(r record, contentType models.ContentType) (models.Content, error) {
+	content := models.Content{
+		Type:      contentType,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	for name, field := range m.Fields {
+		kind := mappedFields[name]
+		value, err := field.resolve(kind, r)
+		if err != nil {
+			return content, fmt.Errorf("field %s: %w", name, err)
+		}
+		if value == nil {
+			continue
+		}
+
+		switch name {
+		case "id":
+			content.ProviderID = value.(string)
+		case "title":
+			content.Title = value.(string)
+		case "description":
+			content.Description = value.(string)
+		case "url":
+			content.URL = value.(string)
+		case "type":
+			content.Type = models.ContentType(value.(string))
+		case "language":
+			content.Language = value.(string)
+		case "views":
+			content.Views = value.(int)
+		case "likes":
+			content.Likes = value.(int)
+		case "duration":
+			content.Duration = value.(int)
+		case "reading_time":
+			content.ReadingTime = value.(int)
+		case "reactions":
+			content.Reactions = value.(int)
+		case "tags":
+			content.Tags = value.(string)
+		case "published_at":
+			content.PublishedAt = value.(time.Time)
+		}
+	}
+
+	if content.ProviderID == "" {
+		return content, fmt.Errorf("field id is empty")
+	}
+	return content, nil
+}
+
+// resolve returns the coerced value of the field in a record, nil when the
+// record has none and the field no default
+func (f *FieldMapping) resolve(kind fieldKind, r record) (interface{}, error) {
+	if f.Value != "" {
+		return f.coerce(kind, []string{f.Value})
+	}
+
+	var values []string
+	for _, value := range r.values(f.Path) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) > 0 {
+		value, err := f.coerce(kind, values)
+		if err == nil || f.Default == "" {
+			return value, err
+		}
+	}
+	if f.Default != "" {
+		return f.coerce(kind, []string{f.Default})
+	}
+	return nil, nil
+}
+
+// coerce converts source values into the kind of a field. Tags join every
+// value, the other kinds read the first.
+func (f *FieldMapping) coerce(kind fieldKind, values []string) (interface{}, error) {
+	switch kind {
+	case kindInt:
+		number, err := strconv.ParseFloat(values[0], 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, fmt.Errorf("%q is not a number", values[0])
+		}
+		return int(math.Round(number)), nil
+
+	case kindTime:
+		return f.parseTime(values[0])
+
+	case kindTags:
+		var tags []string
+		for _, value := range values {
+			parts := []string{value}
+			if f.Separator != "" {
+				parts = strings.Split(value, f.Separator)
+			}
+			for _, tag := range parts {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					tags = append(tags, tag)
+				}
+			}
+		}
+		return strings.Join(tags, ","), nil
+
+	default:
+		return values[0], nil
+	}
+}
+
+// parseTime parses a date with the layouts of the field
+func (f *FieldMapping) parseTime(value string) (time.Time, error) {
+	layouts := f.Layouts
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339Nano}
+	}
+
+	for _, layout := range layouts {
+		switch layout {
+		case "unix", "unix_ms":
+			epoch, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			if layout == "unix_ms" {
+				return time.UnixMilli(int64(epoch)).UTC(), nil
+			}
+			return time.Unix(0, int64(epoch*float64(time.Second))).UTC(), nil
+		default:
+			if parsed, err := time.Parse(layout, value); err == nil {
+				return parsed, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q does not match the layouts '%s'", value, strings.Join(layouts, "', '"))
+}
@@ -2,6 +2,10 @@ package providers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
 	"time"
 
 	"search-engine-service/internal/database/models"
@@ -18,33 +22,78 @@ type Provider interface {
 // ProviderManager manages multiple providers
 type ProviderManager struct {
 	providers []Provider
+	instances map[string]InstanceConfig
 	config    ProviderConfig
+
+	// breakers guard the fetches of each provider
+	breakers map[string]*Breaker
+
+	// lastFetch is when each provider was last fetched, for its schedule,
+	// and lastResult the outcome of that fetch, for its health
+	mu         sync.Mutex
+	lastFetch  map[string]time.Time
+	lastResult map[string]FetchResult
 }
 
 // ProviderConfig holds provider configuration
 type ProviderConfig struct {
-	JSONURL   string
-	XMLURL    string
+	// Instances are the declared providers, built with the factory of
+	// their kind
+	Instances []InstanceConfig
+	// Timeout is the fetch timeout of instances declaring none
 	Timeout   time.Duration
 	RateLimit int
+	// Concurrency is how many providers are fetched at once
+	Concurrency int
+	// Retry and Breaker are the retry and circuit breaker policies of
+	// instances declaring none, the package defaults fill unset fields
+	Retry   RetryPolicy
+	Breaker BreakerPolicy
+	// Logger receives the outcome of every provider fetch, optional
+	Logger FetchLogger
+}
+
+// defaultConcurrency is the number of providers fetched at once when the
+// configuration sets none
+const defaultConcurrency = 4
+
+// FetchLogger receives the outcome of every provider fetch, as
+// logger.Logger does
+type FetchLogger interface {
+	LogProviderFetch(provider string, count int, duration time.Duration, err error)
 }
 
-// NewManager creates a new provider manager
-func NewManager(config ProviderConfig) *ProviderManager {
+// NewManager creates a provider manager with a provider for every declared
+// instance
+func NewManager(config ProviderConfig) (*ProviderManager, error) {
 	manager := &ProviderManager{
-		config: config,
+		instances:  make(map[string]InstanceConfig, len(config.Instances)),
+		config:     config,
+		breakers:   make(map[string]*Breaker, len(config.Instances)),
+		lastFetch:  make(map[string]time.Time),
+		lastResult: make(map[string]FetchResult),
 	}
 
-	// Initialize providers
-	jsonProvider := NewJSONProvider(config.JSONURL, config.Timeout)
-	xmlProvider := NewXMLProvider(config.XMLURL, config.Timeout)
+	for _, instance := range config.Instances {
+		if _, exists := manager.instances[instance.Name]; exists {
+			return nil, fmt.Errorf("provider %s is declared twice", instance.Name)
+		}
+		if instance.Timeout == 0 {
+			instance.Timeout = Duration(config.Timeout)
+		}
+		instance.Retry = instance.Retry.withDefaults(config.Retry).withDefaults(DefaultRetryPolicy())
+		instance.Breaker = instance.Breaker.withDefaults(config.Breaker).withDefaults(DefaultBreakerPolicy())
 
-	manager.providers = []Provider{
-		jsonProvider,
-		xmlProvider,
+		provider, err := New(instance)
+		if err != nil {
+			return nil, err
+		}
+		manager.providers = append(manager.providers, provider)
+		manager.instances[instance.Name] = instance
+		manager.breakers[instance.Name] = NewBreaker(instance.Breaker)
 	}
 
-	return manager
+	return manager, nil
 }
 
 // GetAllProviders returns all registered providers
@@ -52,30 +101,147 @@ func (pm *ProviderManager) GetAllProviders() []Provider {
 	return pm.providers
 }
 
-// FetchAllContent fetches content from all providers
-func (pm *ProviderManager) FetchAllContent(ctx context.Context) ([]models.Content, error) {
-	var allContent []models.Content
+// GetInstance returns the configuration a provider was built from
+func (pm *ProviderManager) GetInstance(name string) (InstanceConfig, bool) {
+	instance, ok := pm.instances[name]
+	return instance, ok
+}
 
+// FetchAllContent fetches content from all providers concurrently. The
+// report holds the outcome of every provider, the error is set only when
+// every provider failed.
+func (pm *ProviderManager) FetchAllContent(ctx context.Context) (*FetchReport, error) {
+	return pm.fetch(ctx, pm.providers, time.Now())
+}
+
+// FetchDueContent fetches content from the scheduled providers whose
+// schedule elapsed since they were last fetched
+func (pm *ProviderManager) FetchDueContent(ctx context.Context, now time.Time) (*FetchReport, error) {
+	var due []Provider
+
+	pm.mu.Lock()
 	for _, provider := range pm.providers {
-		content, err := provider.FetchContent(ctx)
-		if err != nil {
-			// Log error but continue with other providers
+		schedule := time.Duration(pm.instances[provider.GetName()].Schedule)
+		if schedule > 0 && !now.Before(pm.lastFetch[provider.GetName()].Add(schedule)) {
+			due = append(due, provider)
+		}
+	}
+	pm.mu.Unlock()
+
+	return pm.fetch(ctx, due, now)
+}
+
+// NextDue returns when the next scheduled provider is due, false when no
+// provider is scheduled. Providers never fetched are due right away.
+func (pm *ProviderManager) NextDue() (time.Time, bool) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	var next time.Time
+	found := false
+	for name, instance := range pm.instances {
+		if instance.Schedule <= 0 {
 			continue
 		}
+		due := pm.lastFetch[name].Add(time.Duration(instance.Schedule))
+		if !found || due.Before(next) {
+			next, found = due, true
+		}
+	}
+	return next, found
+}
+
+// fetch fetches content from the given providers, at most Concurrency at a
+// time, and reports the outcome of each in the order of the providers
+func (pm *ProviderManager) fetch(ctx context.Context, providers []Provider, now time.Time) (*FetchReport, error) {
+	report := &FetchReport{Results: make([]FetchResult, len(providers))}
+
+	concurrency := pm.config.Concurrency
+	if concurrency < 1 {
+		concurrency = defaultConcurrency
+	}
+	slots := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, provider := range providers {
+		pm.mu.Lock()
+		pm.lastFetch[provider.GetName()] = now
+		pm.mu.Unlock()
 
-		// Set provider name for each content
-		for i := range content {
-			content[i].Provider = provider.GetName()
+		wg.Add(1)
+		go func(i int, provider Provider) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			report.Results[i] = pm.fetchProvider(ctx, provider)
+		}(i, provider)
+	}
+	wg.Wait()
+
+	if len(providers) > 0 && report.Failed() == len(providers) {
+		errs := make([]error, len(report.Results))
+		for i := range report.Results {
+			errs[i] = fmt.Errorf("%s: %w", report.Results[i].Provider, report.Results[i].Err)
 		}
+		return report, fmt.Errorf("all providers failed: %w", errors.Join(errs...))
+	}
+	return report, nil
+}
 
-		allContent = append(allContent, content...)
+// fetchProvider fetches the content of a provider through its circuit
+// breaker and logs the outcome
+func (pm *ProviderManager) fetchProvider(ctx context.Context, provider Provider) FetchResult {
+	start := time.Now()
+	breaker := pm.breakers[provider.GetName()]
+	var content []models.Content
+	err := breaker.Allow()
+	if err == nil {
+		content, err = provider.FetchContent(ctx)
+		breaker.Record(err)
 	}
 
-	return allContent, nil
+	result := FetchResult{
+		Provider:  provider.GetName(),
+		FetchedAt: start,
+		Duration:  Duration(time.Since(start)),
+		Err:       err,
+	}
+
+	if err != nil {
+		content = nil
+		result.Status = FetchFailed
+		result.Error = err.Error()
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			result.StatusCode = statusErr.StatusCode
+		}
+	} else {
+		result.Status = FetchOK
+		result.StatusCode = http.StatusOK
+	}
+
+	// Set provider name for each content
+	for i := range content {
+		content[i].Provider = provider.GetName()
+	}
+	result.Contents = content
+	result.Items = len(content)
+
+	if pm.config.Logger != nil {
+		pm.config.Logger.LogProviderFetch(result.Provider, result.Items, time.Duration(result.Duration), err)
+	}
+
+	recorded := result
+	recorded.Contents = nil
+	pm.mu.Lock()
+	pm.lastResult[result.Provider] = recorded
+	pm.mu.Unlock()
+	return result
 }
 
 // RefreshContent fetches fresh content from all providers
-func (pm *ProviderManager) RefreshContent(ctx context.Context) ([]models.Content, error) {
+func (pm *ProviderManager) RefreshContent(ctx context.Context) (*FetchReport, error) {
 	return pm.FetchAllContent(ctx)
 }
 
@@ -87,4 +253,4 @@ func (pm *ProviderManager) GetProviderByName(name string) Provider {
 		}
 	}
 	return nil
-} 
\ No newline at end of file
+}
@@ -0,0 +1,66 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Factory builds a provider from its instance configuration
+type Factory func(config InstanceConfig) (Provider, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes a provider kind available to instance configurations.
+// Provider packages call it from an init function; it panics if the kind is
+// registered twice or the factory is nil.
+func Register(kind string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if factory == nil {
+		panic("providers: Register factory is nil for kind " + kind)
+	}
+	if _, exists := factories[kind]; exists {
+		panic("providers: Register called twice for kind " + kind)
+	}
+	factories[kind] = factory
+}
+
+// Kinds returns the registered provider kinds, sorted
+func Kinds() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	kinds := make([]string, 0, len(factories))
+	for kind := range factories {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// New builds a provider with the factory of its kind
+func New(config InstanceConfig) (Provider, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	factoriesMu.RLock()
+	factory, ok := factories[config.Kind]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("provider %s: unknown kind %q, must be one of '%s'",
+			config.Name, config.Kind, strings.Join(Kinds(), "', '"))
+	}
+
+	provider, err := factory(config)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s: %w", config.Name, err)
+	}
+	return provider, nil
+}
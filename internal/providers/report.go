@@ -0,0 +1,87 @@
+package providers
+
+import (
+	"fmt"
+	"time"
+
+	"search-engine-service/internal/database/models"
+)
+
+// StatusError is returned when a feed answers with a status other than 200
+type StatusError struct {
+	StatusCode int
+	// RetryAfter is the delay the feed asked for with Retry-After
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+// FetchStatus is the outcome of a fetch
+type FetchStatus string
+
+const (
+	// FetchOK is a provider fetched successfully, or every provider of a report
+	FetchOK FetchStatus = "ok"
+	// FetchPartial is a report where some providers failed
+	FetchPartial FetchStatus = "partial"
+	// FetchFailed is a provider that failed, or every provider of a report
+	FetchFailed FetchStatus = "failed"
+)
+
+// FetchResult is the outcome of fetching a provider
+type FetchResult struct {
+	Provider string      `json:"provider"`
+	Status   FetchStatus `json:"status"`
+	Items    int         `json:"items"`
+	// FetchedAt is when the fetch started, Duration how long it took
+	// including retries
+	FetchedAt time.Time `json:"fetched_at"`
+	Duration  Duration  `json:"duration"`
+	// StatusCode is the HTTP status of the feed, zero when no response
+	// was received
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+
+	Err      error            `json:"-"`
+	Contents []models.Content `json:"-"`
+}
+
+// FetchReport is the outcome of fetching a set of providers
+type FetchReport struct {
+	Results []FetchResult
+}
+
+// Contents returns the contents of the providers fetched successfully
+func (r *FetchReport) Contents() []models.Content {
+	var contents []models.Content
+	for i := range r.Results {
+		contents = append(contents, r.Results[i].Contents...)
+	}
+	return contents
+}
+
+// Failed returns the number of providers that failed
+func (r *FetchReport) Failed() int {
+	failed := 0
+	for i := range r.Results {
+		if r.Results[i].Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Status summarizes the report: ok when every provider succeeded, failed
+// when every provider failed and partial otherwise
+func (r *FetchReport) Status() FetchStatus {
+	switch failed := r.Failed(); {
+	case failed == 0:
+		return FetchOK
+	case failed == len(r.Results):
+		return FetchFailed
+	default:
+		return FetchPartial
+	}
+}
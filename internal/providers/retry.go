@@ -0,0 +1,194 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy configures the retries of a feed request
+type RetryPolicy struct {
+	// MaxAttempts is the number of requests made before giving up, 1
+	// disables retries
+	MaxAttempts int `json:"max_attempts,omitempty"`
+	// BaseDelay is the backoff ceiling of the first retry, doubled on every
+	// retry up to MaxDelay
+	BaseDelay Duration `json:"base_delay,omitempty"`
+	MaxDelay  Duration `json:"max_delay,omitempty"`
+}
+
+// DefaultRetryPolicy makes 3 attempts, backing off from 200ms up to 5s
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   Duration(200 * time.Millisecond),
+		MaxDelay:    Duration(5 * time.Second),
+	}
+}
+
+// withDefaults fills the unset fields of the policy from another
+func (p RetryPolicy) withDefaults(defaults RetryPolicy) RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaults.BaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaults.MaxDelay
+	}
+	return p
+}
+
+// Backoff returns the delay before the given retry, counting from 1. The
+// ceiling doubles from BaseDelay up to MaxDelay and the delay is drawn
+// between half the ceiling and the ceiling, so failing clients spread out.
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	ceiling := time.Duration(p.MaxDelay)
+	if shift := retry - 1; shift < 32 {
+		ceiling = min(time.Duration(p.BaseDelay)<<shift, ceiling)
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	half := ceiling / 2
+	return half + time.Duration(rand.Int63n(int64(ceiling-half)+1))
+}
+
+// retryable reports whether a failed request may succeed when retried:
+// network errors, including attempt timeouts and dropped connections, and
+// 5xx and 429 responses. Requests that cannot be made, e.g. to an invalid
+// URL, fail right away.
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	// A url.Error is a net.Error itself, so judge the error it wraps
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// parseRetryAfter reads a Retry-After header in seconds or as an HTTP date
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// FeedClient downloads a provider's feed over HTTP, retrying network errors
+// and 5xx and 429 responses with jittered exponential backoff. It is the
+// fetch layer of the built-in providers and can be used by registered
+// kinds.
+type FeedClient struct {
+	name    string
+	url     string
+	headers map[string]string
+	retry   RetryPolicy
+	client  *http.Client
+}
+
+// NewFeedClient creates the feed client of a provider instance, each
+// attempt bounded by the instance timeout
+func NewFeedClient(config InstanceConfig) *FeedClient {
+	return &FeedClient{
+		name:    config.Name,
+		url:     config.URL,
+		headers: config.Headers,
+		retry:   config.Retry.withDefaults(DefaultRetryPolicy()),
+		client: &http.Client{
+			Timeout: time.Duration(config.Timeout),
+		},
+	}
+}
+
+// Fetch downloads the feed. A Retry-After header sets the delay before the
+// next attempt, and one longer than the maximum backoff ends the retries.
+func (fc *FeedClient) Fetch(ctx context.Context, mediaType string) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		body, err := fc.fetchOnce(ctx, mediaType)
+		if err == nil {
+			return body, nil
+		}
+		if attempt >= fc.retry.MaxAttempts || !retryable(err) || ctx.Err() != nil {
+			return nil, err
+		}
+
+		delay := fc.retry.Backoff(attempt)
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			if statusErr.RetryAfter > time.Duration(fc.retry.MaxDelay) {
+				return nil, err
+			}
+			delay = statusErr.RetryAfter
+		}
+		log.Printf("Provider %s attempt %d of %d failed: %v, retrying in %s", fc.name, attempt, fc.retry.MaxAttempts, err, delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// fetchOnce makes a single request for the feed
+func (fc *FeedClient) fetchOnce(ctx context.Context, mediaType string) ([]byte, error) {
+	// Create request with context
+	req, err := http.NewRequestWithContext(ctx, "GET", fc.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers
+	req.Header.Set("Content-Type", mediaType)
+	req.Header.Set("User-Agent", "SearchEngineService/1.0")
+	for key, value := range fc.headers {
+		req.Header.Set(key, value)
+	}
+
+	// Make request
+	resp, err := fc.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check status code
+	if resp.StatusCode != http.StatusOK {
+		statusErr := &StatusError{StatusCode: resp.StatusCode}
+		statusErr.RetryAfter, _ = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		return nil, statusErr
+	}
+
+	// Read response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return body, nil
+}
@@ -2,51 +2,78 @@ package providers
 
 import (
 	"context"
-	"encoding/xml"
-	"fmt"
-	"io"
-	"net/http"
 	"time"
 
 	"search-engine-service/internal/database/models"
 )
 
+// KindXML is the kind of XML providers
+const KindXML = "xml"
+
+func init() {
+	Register(KindXML, func(config InstanceConfig) (Provider, error) {
+		return NewXMLProvider(config)
+	})
+}
+
 // XMLProvider implements Provider interface for XML data sources
 type XMLProvider struct {
-	url     string
-	timeout time.Duration
+	name        string
+	url         string
+	timeout     time.Duration
+	contentType models.ContentType
+	mapping     *Mapping
+	client      *FeedClient
 }
 
-// XMLArticleResponse represents the structure of XML article data
-type XMLArticleResponse struct {
-	XMLName xml.Name     `xml:"articles"`
-	Articles []XMLArticle `xml:"article"`
+// DefaultXMLMapping maps the article elements of an <articles> document
+// with child elements named after the content fields
+func DefaultXMLMapping() *Mapping {
+	return &Mapping{
+		Items: "articles/article",
+		Fields: map[string]FieldMapping{
+			"id":           {Path: "id"},
+			"title":        {Path: "title"},
+			"description":  {Path: "description"},
+			"url":          {Path: "url"},
+			"reading_time": {Path: "reading_time"},
+			"reactions":    {Path: "reactions"},
+			"tags":         {Path: "tags", Separator: ","},
+			"language":     {Path: "language"},
+			"published_at": {Path: "published_at"},
+		},
+	}
 }
 
-// XMLArticle represents a single article from XML provider
-type XMLArticle struct {
-	ID          string    `xml:"id"`
-	Title       string    `xml:"title"`
-	Description string    `xml:"description"`
-	URL         string    `xml:"url"`
-	ReadingTime int       `xml:"reading_time"`
-	Reactions   int       `xml:"reactions"`
-	Tags        string    `xml:"tags"`
-	Language    string    `xml:"language"`
-	PublishedAt time.Time `xml:"published_at"`
-}
+// NewXMLProvider creates a new XML provider, fetching articles unless the
+// instance declares another content type, with the default mapping unless
+// the instance declares one
+func NewXMLProvider(config InstanceConfig) (*XMLProvider, error) {
+	contentType := config.ContentType
+	if contentType == "" {
+		contentType = models.ContentTypeText
+	}
+	mapping := config.Mapping
+	if mapping == nil {
+		mapping = DefaultXMLMapping()
+	}
+	if err := mapping.Validate(); err != nil {
+		return nil, err
+	}
 
-// NewXMLProvider creates a new XML provider
-func NewXMLProvider(url string, timeout time.Duration) *XMLProvider {
 	return &XMLProvider{
-		url:     url,
-		timeout: timeout,
-	}
+		name:        config.Name,
+		url:         config.URL,
+		timeout:     time.Duration(config.Timeout),
+		contentType: contentType,
+		mapping:     mapping,
+		client:      NewFeedClient(config),
+	}, nil
 }
 
 // GetName returns the provider name
 func (xp *XMLProvider) GetName() string {
-	return "xml_provider"
+	return xp.name
 }
 
 // GetURL returns the provider URL
@@ -61,65 +88,14 @@ func (xp *XMLProvider) GetTimeout() time.Duration {
 
 // FetchContent fetches content from the XML provider
 func (xp *XMLProvider) FetchContent(ctx context.Context) ([]models.Content, error) {
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: xp.timeout,
-	}
-
-	// Create request with context
-	req, err := http.NewRequestWithContext(ctx, "GET", xp.url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Content-Type", "application/xml")
-	req.Header.Set("User-Agent", "SearchEngineService/1.0")
-
-	// Make request
-	resp, err := client.Do(req)
+	body, err := xp.client.Fetch(ctx, "application/xml")
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	// Check status code
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	records, err := parseXMLFeed(body, xp.mapping.Items)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Parse XML response
-	var xmlResponse XMLArticleResponse
-	if err := xml.Unmarshal(body, &xmlResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal XML: %w", err)
-	}
-
-	// Convert to Content models
-	var contents []models.Content
-	for _, article := range xmlResponse.Articles {
-		content := models.Content{
-			ProviderID:  article.ID,
-			Title:       article.Title,
-			Description: article.Description,
-			URL:         article.URL,
-			Type:        models.ContentTypeText,
-			ReadingTime: article.ReadingTime,
-			Reactions:   article.Reactions,
-			Tags:        article.Tags,
-			Language:    article.Language,
-			PublishedAt: article.PublishedAt,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		}
-
-		contents = append(contents, content)
+		return nil, err
 	}
-
-	return contents, nil
-} 
\ No newline at end of file
+	return xp.mapping.apply(xp.name, records, xp.contentType), nil
+}
@@ -0,0 +1,128 @@
+// Package query parses the search query language into an abstract syntax tree.
+//
+// The language supports bare terms, "exact phrases", the AND, OR and NOT
+// operators (AND is implied between adjacent clauses), -exclusions, field
+// scoping such as title:go, tags:kubernetes or type:video and grouping
+// with parentheses.
+package query
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Field names accepted before a colon
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldTags        = "tags"
+	FieldType        = "type"
+)
+
+// Node is a node of the query syntax tree
+type Node interface {
+	// Pos returns the byte offset of the node in the query string
+	Pos() int
+	String() string
+}
+
+// TermNode matches a single term, Field is empty for all text fields
+type TermNode struct {
+	Field    string
+	Text     string
+	Position int
+}
+
+// PhraseNode matches consecutive terms, Field is empty for all text fields
+type PhraseNode struct {
+	Field    string
+	Text     string
+	Position int
+}
+
+// AndNode matches documents matching all of its children
+type AndNode struct {
+	Children []Node
+	Position int
+}
+
+// OrNode matches documents matching any of its children
+type OrNode struct {
+	Children []Node
+	Position int
+}
+
+// NotNode matches documents not matching its child
+type NotNode struct {
+	Child    Node
+	Position int
+}
+
+func (n *TermNode) Pos() int   { return n.Position }
+func (n *PhraseNode) Pos() int { return n.Position }
+func (n *AndNode) Pos() int    { return n.Position }
+func (n *OrNode) Pos() int     { return n.Position }
+func (n *NotNode) Pos() int    { return n.Position }
+
+func (n *TermNode) String() string {
+	return fieldPrefix(n.Field) + n.Text
+}
+
+func (n *PhraseNode) String() string {
+	return fieldPrefix(n.Field) + strconv.Quote(n.Text)
+}
+
+func (n *AndNode) String() string {
+	return joinNodes(n.Children, " AND ")
+}
+
+func (n *OrNode) String() string {
+	return joinNodes(n.Children, " OR ")
+}
+
+func (n *NotNode) String() string {
+	return "NOT " + n.Child.String()
+}
+
+// fieldPrefix returns the field scope prefix of a node
+func fieldPrefix(field string) string {
+	if field == "" {
+		return ""
+	}
+	return field + ":"
+}
+
+// joinNodes renders a parenthesized list of nodes
+func joinNodes(nodes []Node, separator string) string {
+	parts := make([]string, len(nodes))
+	for i, node := range nodes {
+		parts[i] = node.String()
+	}
+	return "(" + strings.Join(parts, separator) + ")"
+}
+
+// Walk calls fn for every node of the tree in depth-first order, negated
+// reports whether the node is under an odd number of NOT operators
+func Walk(node Node, fn func(node Node, negated bool)) {
+	walk(node, false, fn)
+}
+
+func walk(node Node, negated bool, fn func(node Node, negated bool)) {
+	if node == nil {
+		return
+	}
+	fn(node, negated)
+
+	switch n := node.(type) {
+	case *AndNode:
+		for _, child := range n.Children {
+			walk(child, negated, fn)
+		}
+	case *OrNode:
+		for _, child := range n.Children {
+			walk(child, negated, fn)
+		}
+	case *NotNode:
+		walk(n.Child, !negated, fn)
+	}
+}
@@ -0,0 +1,340 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SyntaxError reports an invalid query and the byte offset it was found at
+type SyntaxError struct {
+	Position int
+	Message  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Position, e.Message)
+}
+
+// Errorf creates a syntax error for the given node position
+func Errorf(position int, format string, args ...interface{}) *SyntaxError {
+	return &SyntaxError{Position: position, Message: fmt.Sprintf(format, args...)}
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokPhrase
+	tokField
+	tokMinus
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// fields lists the field names accepted before a colon
+var fields = map[string]bool{
+	FieldTitle:       true,
+	FieldDescription: true,
+	FieldTags:        true,
+	FieldType:        true,
+}
+
+// Parse parses a query string, an empty query yields a nil node
+func Parse(input string) (Node, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, nil
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, Errorf(tok.pos, "unexpected %s", describe(tok))
+	}
+	return node, nil
+}
+
+// lex splits the input into tokens
+func lex(input string) ([]token, error) {
+	var tokens []token
+	boundary := true // whether the previous character separates tokens
+
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+
+		switch {
+		case unicode.IsSpace(r):
+			boundary = true
+			i += size
+			continue
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			boundary = true
+			i += size
+			continue
+
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			boundary = true
+			i += size
+			continue
+
+		case r == '"':
+			end := strings.IndexByte(input[i+1:], '"')
+			if end < 0 {
+				return nil, Errorf(i, "unterminated phrase")
+			}
+			text := input[i+1 : i+1+end]
+			if strings.TrimSpace(text) == "" {
+				return nil, Errorf(i, "empty phrase")
+			}
+			tokens = append(tokens, token{kind: tokPhrase, text: text, pos: i})
+			boundary = false
+			i += end + 2
+			continue
+
+		case r == '-' && boundary && i+1 < len(input) && input[i+1] != ')' && !isSpace(input[i+1]):
+			tokens = append(tokens, token{kind: tokMinus, text: "-", pos: i})
+			boundary = false
+			i += size
+			continue
+		}
+
+		// Read a word up to the next separator
+		start := i
+		for i < len(input) && !isSeparator(input[i]) && input[i] != '"' {
+			i++
+		}
+		word := input[start:i]
+
+		if colon := strings.IndexByte(word, ':'); colon > 0 && fields[strings.ToLower(word[:colon])] {
+			tokens = append(tokens, token{kind: tokField, text: strings.ToLower(word[:colon]), pos: start})
+			if value := word[colon+1:]; value != "" {
+				tokens = append(tokens, token{kind: tokWord, text: value, pos: start + colon + 1})
+			}
+			boundary = false
+			continue
+		}
+
+		switch word {
+		case "AND":
+			tokens = append(tokens, token{kind: tokAnd, text: word, pos: start})
+		case "OR":
+			tokens = append(tokens, token{kind: tokOr, text: word, pos: start})
+		case "NOT":
+			tokens = append(tokens, token{kind: tokNot, text: word, pos: start})
+		default:
+			tokens = append(tokens, token{kind: tokWord, text: word, pos: start})
+		}
+		boundary = false
+	}
+
+	tokens = append(tokens, token{kind: tokEOF, pos: len(input)})
+	return tokens, nil
+}
+
+// isSeparator reports whether a byte ends a word
+func isSeparator(b byte) bool {
+	return isSpace(b) || b == '(' || b == ')'
+}
+
+// isSpace reports whether a byte is ASCII white space
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// parseOr parses clauses separated by OR
+func (p *parser) parseOr() (Node, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []Node{first}
+	for p.peek().kind == tokOr {
+		p.next()
+		child, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &OrNode{Children: children, Position: first.Pos()}, nil
+}
+
+// parseAnd parses clauses joined by AND or by juxtaposition
+func (p *parser) parseAnd() (Node, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []Node{first}
+	for {
+		tok := p.peek()
+		if tok.kind == tokAnd {
+			p.next()
+		} else if !startsClause(tok) {
+			break
+		}
+
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &AndNode{Children: children, Position: first.Pos()}, nil
+}
+
+// parseUnary parses an optionally negated clause
+func (p *parser) parseUnary() (Node, error) {
+	tok := p.peek()
+	if tok.kind == tokNot || tok.kind == tokMinus {
+		p.next()
+		var child Node
+		var err error
+		if tok.kind == tokNot {
+			child, err = p.parseUnary()
+		} else {
+			child, err = p.parsePrimary()
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &NotNode{Child: child, Position: tok.pos}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a term, phrase, field scoped clause or group
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokWord:
+		return &TermNode{Text: tok.text, Position: tok.pos}, nil
+
+	case tokPhrase:
+		return &PhraseNode{Text: tok.text, Position: tok.pos}, nil
+
+	case tokLParen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, Errorf(tok.pos, "missing closing parenthesis")
+		}
+		p.next()
+		return node, nil
+
+	case tokField:
+		value := p.peek()
+		if value.kind != tokWord && value.kind != tokPhrase && value.kind != tokLParen {
+			return nil, Errorf(value.pos, "expected a value for field %q", tok.text)
+		}
+		node, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		if err := scope(node, tok.text); err != nil {
+			return nil, err
+		}
+		return node, nil
+
+	case tokEOF:
+		return nil, Errorf(tok.pos, "unexpected end of query")
+
+	default:
+		return nil, Errorf(tok.pos, "unexpected %s", describe(tok))
+	}
+}
+
+// scope applies a field to every term and phrase of a clause
+func scope(node Node, field string) error {
+	var err error
+	Walk(node, func(n Node, negated bool) {
+		switch n := n.(type) {
+		case *TermNode:
+			if n.Field != "" && n.Field != field && err == nil {
+				err = Errorf(n.Position, "conflicting fields %q and %q", field, n.Field)
+			}
+			n.Field = field
+		case *PhraseNode:
+			if n.Field != "" && n.Field != field && err == nil {
+				err = Errorf(n.Position, "conflicting fields %q and %q", field, n.Field)
+			}
+			n.Field = field
+		}
+	})
+	return err
+}
+
+// startsClause reports whether a token can begin a new clause
+func startsClause(tok token) bool {
+	switch tok.kind {
+	case tokWord, tokPhrase, tokField, tokMinus, tokNot, tokLParen:
+		return true
+	default:
+		return false
+	}
+}
+
+// describe returns a human readable description of a token
+func describe(tok token) string {
+	switch tok.kind {
+	case tokEOF:
+		return "end of query"
+	case tokAnd, tokOr, tokNot:
+		return fmt.Sprintf("operator %s", tok.text)
+	default:
+		return fmt.Sprintf("%q", tok.text)
+	}
+}
@@ -0,0 +1,69 @@
+package scoring
+
+import "math"
+
+// DecayFunction is the shape of a decay curve
+type DecayFunction string
+
+const (
+	// DecayExp decays exponentially, halving every scale at the default decay
+	DecayExp DecayFunction = "exp"
+	// DecayGauss decays slowly near the origin, then fast past the scale
+	DecayGauss DecayFunction = "gauss"
+	// DecayLinear decays linearly, reaching 0 at scale / (1 - decay)
+	DecayLinear DecayFunction = "linear"
+)
+
+// DefaultDecay is the value of a curve at scale past the offset
+const DefaultDecay = 0.5
+
+// Decay is a decay curve over the distance of a value from an origin. Values
+// within Offset of the origin score 1, and the curve reaches Decay at Scale
+// past the offset, so with the default decay Scale is the half-life.
+type Decay struct {
+	Function DecayFunction
+	Origin   float64
+	Scale    float64
+	Offset   float64
+	Decay    float64
+}
+
+// Valid reports whether the curve parameters are in range
+func (d Decay) Valid() bool {
+	return d.Scale > 0 && d.Offset >= 0 && d.Decay > 0 && d.Decay < 1
+}
+
+// Value returns the value of the curve at x, between 0 and 1. Invalid curves
+// evaluate to 0.
+func (d Decay) Value(x float64) float64 {
+	if !d.Valid() {
+		return 0
+	}
+
+	distance := math.Max(math.Abs(x-d.Origin)-d.Offset, 0) / d.Scale
+	switch d.Function {
+	case DecayExp:
+		return math.Pow(d.Decay, distance)
+	case DecayGauss:
+		return math.Pow(d.Decay, distance*distance)
+	case DecayLinear:
+		return math.Max(1-(1-d.Decay)*distance, 0)
+	default:
+		return 0
+	}
+}
+
+// decayFunction returns the expression function of a curve shape, called as
+// decay_<shape>(x, origin, scale[, offset[, decay]])
+func decayFunction(shape DecayFunction) *function {
+	return &function{3, 5, func(a []float64) float64 {
+		d := Decay{Function: shape, Origin: a[1], Scale: a[2], Decay: DefaultDecay}
+		if len(a) > 3 {
+			d.Offset = a[3]
+		}
+		if len(a) > 4 {
+			d.Decay = a[4]
+		}
+		return d.Value(a[0])
+	}}
+}
@@ -0,0 +1,100 @@
+package scoring
+
+import (
+	"time"
+
+	"search-engine-service/internal/database/models"
+)
+
+// Variable identifies a value expressions can read
+type Variable int
+
+const (
+	VarViews Variable = iota
+	VarLikes
+	VarDuration
+	VarReadingTime
+	VarReactions
+	VarAgeDays
+	VarAgeHours
+	VarIsVideo
+	VarIsText
+	// The engagement prior of the content's provider and type
+	VarPriorRate
+	VarPriorWeight
+
+	// The component scores, only readable by the final expression
+	VarBase
+	VarTypeMultiplier
+	VarFreshness
+	VarEngagement
+
+	numVariables
+)
+
+// Env holds the values of the variables
+type Env [numVariables]float64
+
+// variableNames maps the names used in expressions to the variables
+var variableNames = map[string]Variable{
+	"views":           VarViews,
+	"likes":           VarLikes,
+	"duration":        VarDuration,
+	"reading_time":    VarReadingTime,
+	"reactions":       VarReactions,
+	"age_days":        VarAgeDays,
+	"age_hours":       VarAgeHours,
+	"is_video":        VarIsVideo,
+	"is_text":         VarIsText,
+	"prior_rate":      VarPriorRate,
+	"prior_weight":    VarPriorWeight,
+	"base":            VarBase,
+	"type_multiplier": VarTypeMultiplier,
+	"freshness":       VarFreshness,
+	"engagement":      VarEngagement,
+}
+
+// String returns the name of the variable in expressions
+func (v Variable) String() string {
+	for name, variable := range variableNames {
+		if variable == v {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// ContentVariables are the variables of the component expressions
+var ContentVariables = []Variable{
+	VarViews, VarLikes, VarDuration, VarReadingTime, VarReactions,
+	VarAgeDays, VarAgeHours, VarIsVideo, VarIsText, VarPriorRate, VarPriorWeight,
+}
+
+// FinalVariables are the variables of the final expression
+var FinalVariables = append(append([]Variable(nil), ContentVariables...),
+	VarBase, VarTypeMultiplier, VarFreshness, VarEngagement)
+
+// NewEnv returns the content variables of a content, its age measured at the
+// given time and its engagement prior taken from priors, which may be nil
+func NewEnv(content *models.Content, now time.Time, priors *Priors) *Env {
+	age := now.Sub(content.PublishedAt)
+
+	env := &Env{}
+	env[VarViews] = float64(content.Views)
+	env[VarLikes] = float64(content.Likes)
+	env[VarDuration] = float64(content.Duration)
+	env[VarReadingTime] = float64(content.ReadingTime)
+	env[VarReactions] = float64(content.Reactions)
+	env[VarAgeDays] = age.Hours() / 24
+	env[VarAgeHours] = age.Hours()
+	if content.Type == models.ContentTypeVideo {
+		env[VarIsVideo] = 1
+	}
+	if content.Type == models.ContentTypeText {
+		env[VarIsText] = 1
+	}
+	prior := priors.Lookup(content.Provider, content.Type)
+	env[VarPriorRate] = prior.Rate
+	env[VarPriorWeight] = prior.Weight
+	return env
+}
@@ -0,0 +1,488 @@
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SyntaxError reports an invalid expression and the byte offset of the problem
+type SyntaxError struct {
+	Position int
+	Message  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Position, e.Message)
+}
+
+// errorf creates a syntax error at the given position
+func errorf(position int, format string, args ...interface{}) *SyntaxError {
+	return &SyntaxError{Position: position, Message: fmt.Sprintf(format, args...)}
+}
+
+// Expr is a compiled expression, safe for concurrent use. Evaluation never
+// fails: a division by zero, the logarithm or square root of a value out of
+// range and any other non-finite result evaluate to 0.
+type Expr struct {
+	source    string
+	root      node
+	variables []Variable
+}
+
+// String returns the source of the expression
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Variables returns the variables the expression reads, in order of first use
+func (e *Expr) Variables() []Variable {
+	return e.variables
+}
+
+// Eval evaluates the expression against the variable values
+func (e *Expr) Eval(env *Env) float64 {
+	return finite(e.root.eval(env))
+}
+
+// Compile parses an expression that may only read the allowed variables
+func Compile(source string, allowed ...Variable) (*Expr, error) {
+	tokens, err := lexExpr(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens, allowed: make(map[Variable]bool, len(allowed)), seen: make(map[Variable]bool)}
+	for _, v := range allowed {
+		p.allowed[v] = true
+	}
+	if p.peek().kind == exprEOF {
+		return nil, errorf(0, "empty expression")
+	}
+
+	root, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != exprEOF {
+		return nil, errorf(tok.pos, "unexpected %q", tok.text)
+	}
+	return &Expr{source: source, root: root, variables: p.variables}, nil
+}
+
+// node is a node of a compiled expression
+type node interface {
+	eval(env *Env) float64
+}
+
+type numberNode float64
+
+func (n numberNode) eval(*Env) float64 { return float64(n) }
+
+type variableNode Variable
+
+func (n variableNode) eval(env *Env) float64 { return env[n] }
+
+type negateNode struct{ operand node }
+
+func (n negateNode) eval(env *Env) float64 { return -n.operand.eval(env) }
+
+type binaryNode struct {
+	op          byte
+	left, right node
+}
+
+func (n binaryNode) eval(env *Env) float64 {
+	a, b := n.left.eval(env), n.right.eval(env)
+	switch n.op {
+	case '+':
+		return a + b
+	case '-':
+		return a - b
+	case '*':
+		return a * b
+	default:
+		if b == 0 {
+			return 0
+		}
+		return a / b
+	}
+}
+
+type comparisonNode struct {
+	op          string
+	left, right node
+}
+
+func (n comparisonNode) eval(env *Env) float64 {
+	a, b := n.left.eval(env), n.right.eval(env)
+	var result bool
+	switch n.op {
+	case "<":
+		result = a < b
+	case "<=":
+		result = a <= b
+	case ">":
+		result = a > b
+	case ">=":
+		result = a >= b
+	case "==":
+		result = a == b
+	default:
+		result = a != b
+	}
+	if result {
+		return 1
+	}
+	return 0
+}
+
+// ifNode only evaluates the selected branch
+type ifNode struct{ condition, then, otherwise node }
+
+func (n ifNode) eval(env *Env) float64 {
+	if n.condition.eval(env) != 0 {
+		return n.then.eval(env)
+	}
+	return n.otherwise.eval(env)
+}
+
+type callNode struct {
+	fn   *function
+	args []node
+}
+
+func (n callNode) eval(env *Env) float64 {
+	args := make([]float64, len(n.args))
+	for i, arg := range n.args {
+		args[i] = arg.eval(env)
+	}
+	return finite(n.fn.call(args))
+}
+
+// function is a built-in function, maxArgs < 0 accepts any number of
+// arguments from minArgs on
+type function struct {
+	minArgs, maxArgs int
+	call             func(args []float64) float64
+}
+
+var functions = map[string]*function{
+	"abs":   {1, 1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"sqrt":  {1, 1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"log":   {1, 1, func(a []float64) float64 { return math.Log(a[0]) }},
+	"log1p": {1, 1, func(a []float64) float64 { return math.Log1p(a[0]) }},
+	"pow":   {2, 2, func(a []float64) float64 { return math.Pow(a[0], a[1]) }},
+	"min": {1, -1, func(a []float64) float64 {
+		result := a[0]
+		for _, x := range a[1:] {
+			result = math.Min(result, x)
+		}
+		return result
+	}},
+	"max": {1, -1, func(a []float64) float64 {
+		result := a[0]
+		for _, x := range a[1:] {
+			result = math.Max(result, x)
+		}
+		return result
+	}},
+	"clamp": {3, 3, func(a []float64) float64 { return math.Max(a[1], math.Min(a[2], a[0])) }},
+
+	// bayes(successes, trials, prior_rate, prior_weight) and
+	// wilson(successes, trials[, z]), see priors.go
+	"bayes": {4, 4, func(a []float64) float64 { return bayes(a[0], a[1], a[2], a[3]) }},
+	"wilson": {2, 3, func(a []float64) float64 {
+		z := 1.96
+		if len(a) > 2 {
+			z = a[2]
+		}
+		return wilson(a[0], a[1], z)
+	}},
+
+	// decay_<shape>(x, origin, scale[, offset[, decay]]), see Decay
+	"decay_exp":    decayFunction(DecayExp),
+	"decay_gauss":  decayFunction(DecayGauss),
+	"decay_linear": decayFunction(DecayLinear),
+}
+
+// finite replaces NaN and infinite values with 0
+func finite(x float64) float64 {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return 0
+	}
+	return x
+}
+
+type exprTokenKind int
+
+const (
+	exprEOF exprTokenKind = iota
+	exprNumber
+	exprIdent
+	exprOperator
+	exprLParen
+	exprRParen
+	exprComma
+)
+
+type exprToken struct {
+	kind exprTokenKind
+	text string
+	pos  int
+}
+
+// lexExpr splits an expression into tokens
+func lexExpr(input string) ([]exprToken, error) {
+	var tokens []exprToken
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, exprToken{kind: exprLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, exprToken{kind: exprRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, exprToken{kind: exprComma, text: ",", pos: i})
+			i++
+		case isDigit(c) || c == '.':
+			start := i
+			for i < len(input) && (isDigit(input[i]) || input[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: exprNumber, text: input[start:i], pos: start})
+		case isLetter(c):
+			start := i
+			for i < len(input) && (isLetter(input[i]) || isDigit(input[i])) {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: exprIdent, text: input[start:i], pos: start})
+		case strings.IndexByte("+-*/", c) >= 0:
+			tokens = append(tokens, exprToken{kind: exprOperator, text: input[i : i+1], pos: i})
+			i++
+		case strings.IndexByte("<>=!", c) >= 0:
+			op := input[i : i+1]
+			if i+1 < len(input) && input[i+1] == '=' {
+				op = input[i : i+2]
+			}
+			if op == "=" || op == "!" {
+				return nil, errorf(i, "unexpected %q", op)
+			}
+			tokens = append(tokens, exprToken{kind: exprOperator, text: op, pos: i})
+			i += len(op)
+		default:
+			return nil, errorf(i, "unexpected %q", string(c))
+		}
+	}
+	return append(tokens, exprToken{kind: exprEOF, text: "end of expression", pos: len(input)}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+
+	allowed   map[Variable]bool
+	seen      map[Variable]bool
+	variables []Variable
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != exprEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// peekOperator reports whether the next token is one of the operators
+func (p *exprParser) peekOperator(ops ...string) bool {
+	tok := p.peek()
+	if tok.kind != exprOperator {
+		return false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+// parseComparison parses a sum optionally compared with another sum
+func (p *exprParser) parseComparison() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if !p.peekOperator("<", "<=", ">", ">=", "==", "!=") {
+		return left, nil
+	}
+
+	op := p.next().text
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.peekOperator("<", "<=", ">", ">=", "==", "!=") {
+		return nil, errorf(p.peek().pos, "comparisons cannot be chained")
+	}
+	return comparisonNode{op: op, left: left, right: right}, nil
+}
+
+// parseSum parses products separated by + or -
+func (p *exprParser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOperator("+", "-") {
+		op := p.next().text[0]
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseProduct parses unary expressions separated by * or /
+func (p *exprParser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOperator("*", "/") {
+		op := p.next().text[0]
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseUnary parses an optionally negated primary expression
+func (p *exprParser) parseUnary() (node, error) {
+	if p.peekOperator("-") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negateNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a number, variable, function call or parenthesized
+// expression
+func (p *exprParser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case exprNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, errorf(tok.pos, "invalid number %q", tok.text)
+		}
+		return numberNode(value), nil
+
+	case exprIdent:
+		if p.peek().kind == exprLParen {
+			return p.parseCall(tok)
+		}
+		v, ok := variableNames[tok.text]
+		if !ok {
+			return nil, errorf(tok.pos, "unknown variable %q", tok.text)
+		}
+		if !p.allowed[v] {
+			return nil, errorf(tok.pos, "variable %q is not available here", tok.text)
+		}
+		if !p.seen[v] {
+			p.seen[v] = true
+			p.variables = append(p.variables, v)
+		}
+		return variableNode(v), nil
+
+	case exprLParen:
+		inner, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != exprRParen {
+			return nil, errorf(closing.pos, "missing closing parenthesis")
+		}
+		return inner, nil
+
+	default:
+		return nil, errorf(tok.pos, "unexpected %q", tok.text)
+	}
+}
+
+// parseCall parses the arguments of a function call
+func (p *exprParser) parseCall(name exprToken) (node, error) {
+	fn, ok := functions[name.text]
+	if !ok && name.text != "if" {
+		return nil, errorf(name.pos, "unknown function %q", name.text)
+	}
+	p.next() // (
+
+	var args []node
+	if p.peek().kind != exprRParen {
+		for {
+			arg, err := p.parseComparison()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek().kind != exprComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if closing := p.next(); closing.kind != exprRParen {
+		return nil, errorf(closing.pos, "missing closing parenthesis")
+	}
+
+	if name.text == "if" {
+		if len(args) != 3 {
+			return nil, errorf(name.pos, "if takes 3 arguments, got %d", len(args))
+		}
+		return ifNode{condition: args[0], then: args[1], otherwise: args[2]}, nil
+	}
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, errorf(name.pos, "%s takes %s, got %d", name.text, arity(fn), len(args))
+	}
+	return callNode{fn: fn, args: args}, nil
+}
+
+// arity describes the number of arguments a function takes
+func arity(fn *function) string {
+	switch {
+	case fn.maxArgs < 0:
+		return fmt.Sprintf("at least %d arguments", fn.minArgs)
+	case fn.minArgs == 1 && fn.maxArgs == 1:
+		return "1 argument"
+	default:
+		return fmt.Sprintf("%d arguments", fn.minArgs)
+	}
+}
@@ -0,0 +1,115 @@
+package scoring
+
+import (
+	"math"
+
+	"search-engine-service/internal/database/models"
+)
+
+// Prior is the engagement rate expected of a content before its own
+// engagement is known, and the number of trials the expectation weighs: views
+// for videos, minutes of reading time for text
+type Prior struct {
+	Rate   float64 `json:"rate"`
+	Weight float64 `json:"weight"`
+}
+
+// priorKey identifies the contents of a provider and type
+type priorKey struct {
+	provider    string
+	contentType models.ContentType
+}
+
+// Priors holds the engagement priors per provider and content type, an
+// immutable set safe for concurrent use
+type Priors struct {
+	byProvider map[priorKey]Prior
+	byType     map[models.ContentType]Prior
+}
+
+// Lookup returns the prior of a provider's contents of a type, falling back to
+// the prior of the type across providers and to no prior at all. A nil set
+// has no priors.
+func (p *Priors) Lookup(provider string, contentType models.ContentType) Prior {
+	if p == nil {
+		return Prior{}
+	}
+	if prior, ok := p.byProvider[priorKey{provider, contentType}]; ok {
+		return prior
+	}
+	return p.byType[contentType]
+}
+
+// engagement returns the engagements and trials of the stats of a content
+// type, false for types without an engagement rate
+func engagement(stats *models.EngagementStats) (successes, trials float64, ok bool) {
+	switch stats.Type {
+	case models.ContentTypeVideo:
+		return float64(stats.Likes), float64(stats.Views), true
+	case models.ContentTypeText:
+		return float64(stats.Reactions), float64(stats.ReadingTime), true
+	default:
+		return 0, 0, false
+	}
+}
+
+// ComputePriors derives the priors from corpus-wide engagement statistics.
+// The rate of a group is its total engagement over its total trials, and its
+// prior weighs strength times the trials of its average content.
+func ComputePriors(stats []models.EngagementStats, strength float64) *Priors {
+	p := &Priors{byProvider: make(map[priorKey]Prior), byType: make(map[models.ContentType]Prior)}
+
+	type totals struct{ successes, trials, contents float64 }
+	byType := make(map[models.ContentType]*totals)
+	priorOf := func(t *totals) (Prior, bool) {
+		if t.trials <= 0 || t.contents <= 0 {
+			return Prior{}, false
+		}
+		return Prior{Rate: t.successes / t.trials, Weight: math.Max(strength, 0) * t.trials / t.contents}, true
+	}
+
+	for i := range stats {
+		successes, trials, ok := engagement(&stats[i])
+		if !ok {
+			continue
+		}
+		group := &totals{successes, trials, float64(stats[i].Contents)}
+		if prior, ok := priorOf(group); ok {
+			p.byProvider[priorKey{stats[i].Provider, stats[i].Type}] = prior
+		}
+
+		all := byType[stats[i].Type]
+		if all == nil {
+			all = &totals{}
+			byType[stats[i].Type] = all
+		}
+		all.successes += group.successes
+		all.trials += group.trials
+		all.contents += group.contents
+	}
+	for contentType, all := range byType {
+		if prior, ok := priorOf(all); ok {
+			p.byType[contentType] = prior
+		}
+	}
+	return p
+}
+
+// bayes averages the observed rate of successes over trials with a prior
+// rate weighing prior_weight trials
+func bayes(successes, trials, priorRate, priorWeight float64) float64 {
+	return (successes + priorRate*priorWeight) / (trials + priorWeight)
+}
+
+// wilson returns the lower bound of the Wilson score interval of the success
+// proportion at z standard deviations
+func wilson(successes, trials, z float64) float64 {
+	if trials <= 0 {
+		return 0
+	}
+	p := math.Max(0, math.Min(successes/trials, 1))
+	z2 := z * z
+	center := p + z2/(2*trials)
+	spread := z * math.Sqrt(p*(1-p)/trials+z2/(4*trials*trials))
+	return math.Max((center-spread)/(1+z2/trials), 0)
+}
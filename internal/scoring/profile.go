@@ -0,0 +1,333 @@
+// Package scoring computes content popularity scores from scoring profiles.
+//
+// A profile is a set of expressions over content fields: the base score,
+// type multiplier, freshness and engagement components, each optionally
+// written per content type, and the final expression combining them.
+// Profiles are declared in configuration and compiled once at load time.
+package scoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"search-engine-service/internal/database/models"
+)
+
+// AnyType is the key of the expression used for content types without
+// their own expression
+const AnyType = "*"
+
+// Expressions maps content types to the expression of a component, a plain
+// JSON string is the expression of every type
+type Expressions map[string]string
+
+// UnmarshalJSON accepts an object of expressions or a single expression
+func (e *Expressions) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*e = Expressions{AnyType: single}
+		return nil
+	}
+
+	var byType map[string]string
+	if err := json.Unmarshal(data, &byType); err != nil {
+		return fmt.Errorf("expressions must be a string or an object of strings")
+	}
+	*e = byType
+	return nil
+}
+
+// ProfileConfig declares a scoring profile
+type ProfileConfig struct {
+	Name string `json:"name"`
+	// Version is recorded on the contents scored with the profile, bump it
+	// whenever an expression changes
+	Version        int         `json:"version"`
+	Base           Expressions `json:"base"`
+	TypeMultiplier Expressions `json:"type_multiplier"`
+	Freshness      Expressions `json:"freshness"`
+	Engagement     Expressions `json:"engagement"`
+	Final          string      `json:"final"`
+}
+
+// Config declares the scoring profiles, contents are stored with the scores
+// of the default profile
+type Config struct {
+	Default  string          `json:"default"`
+	Profiles []ProfileConfig `json:"profiles"`
+}
+
+// DefaultConfig returns the built-in "standard" profile
+func DefaultConfig() Config {
+	return Config{
+		Default: "standard",
+		Profiles: []ProfileConfig{{
+			Name:    "standard",
+			Version: 3,
+			Base: Expressions{
+				"video": "views / 1000 + likes / 100",
+				"text":  "reading_time + reactions / 50",
+			},
+			TypeMultiplier: Expressions{
+				"video": "1.5",
+				"text":  "1",
+			},
+			// Tutorials stay relevant for months, articles are mostly news
+			Freshness: Expressions{
+				"video": "5 * decay_exp(age_days, 0, 30)",
+				"text":  "5 * decay_exp(age_days, 0, 7)",
+				AnyType: "5 * decay_exp(age_days, 0, 14)",
+			},
+			// Engagement rates are averaged with the prior of the provider and
+			// type, so a handful of views cannot earn the top engagement
+			Engagement: Expressions{
+				"video": "bayes(likes, views, prior_rate, prior_weight) * 10",
+				"text":  "bayes(reactions, reading_time, prior_rate, prior_weight) * 5",
+			},
+			Final: "base * type_multiplier + freshness + engagement",
+		}},
+	}
+}
+
+// LoadConfig reads a profiles configuration from a JSON file
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return Config{}, fmt.Errorf("invalid scoring profiles %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// component is a compiled component of a profile
+type component struct {
+	name     string
+	byType   map[models.ContentType]*Expr
+	fallback *Expr
+	// missing is the value of types without an expression
+	missing float64
+}
+
+// expr returns the expression of a content type
+func (c *component) expr(contentType models.ContentType) *Expr {
+	if expr, ok := c.byType[contentType]; ok {
+		return expr
+	}
+	return c.fallback
+}
+
+// eval evaluates the component for a content
+func (c *component) eval(contentType models.ContentType, env *Env) float64 {
+	expr := c.expr(contentType)
+	if expr == nil {
+		return c.missing
+	}
+	return expr.Eval(env)
+}
+
+// Profile is a compiled scoring profile, safe for concurrent use
+type Profile struct {
+	Name    string
+	Version int
+
+	base           component
+	typeMultiplier component
+	freshness      component
+	engagement     component
+	final          *Expr
+}
+
+// Scores holds the component and final scores of a content
+type Scores struct {
+	Base           float64
+	TypeMultiplier float64
+	Freshness      float64
+	Engagement     float64
+	Final          float64
+}
+
+// Score computes the scores of a content with its age measured at the given
+// time and its engagement prior taken from priors, which may be nil
+func (p *Profile) Score(content *models.Content, now time.Time, priors *Priors) Scores {
+	scores, _ := p.evaluate(content, now, priors)
+	return scores
+}
+
+// evaluate computes the scores of a content and returns them with the
+// variables the final expression read
+func (p *Profile) evaluate(content *models.Content, now time.Time, priors *Priors) (Scores, *Env) {
+	env := NewEnv(content, now, priors)
+	scores := Scores{
+		Base:           p.base.eval(content.Type, env),
+		TypeMultiplier: p.typeMultiplier.eval(content.Type, env),
+		Freshness:      p.freshness.eval(content.Type, env),
+		Engagement:     p.engagement.eval(content.Type, env),
+	}
+
+	env[VarBase] = scores.Base
+	env[VarTypeMultiplier] = scores.TypeMultiplier
+	env[VarFreshness] = scores.Freshness
+	env[VarEngagement] = scores.Engagement
+	scores.Final = p.final.Eval(env)
+	return scores, env
+}
+
+// Explain explains the final score of a content, down to the variables
+// every expression read
+func (p *Profile) Explain(content *models.Content, now time.Time, priors *Priors) models.Explanation {
+	scores, env := p.evaluate(content, now, priors)
+
+	details := []models.Explanation{
+		p.base.explain(content.Type, scores.Base, env),
+		p.typeMultiplier.explain(content.Type, scores.TypeMultiplier, env),
+		p.freshness.explain(content.Type, scores.Freshness, env),
+		p.engagement.explain(content.Type, scores.Engagement, env),
+	}
+	for _, v := range p.final.Variables() {
+		if v < VarBase {
+			details = append(details, explainVariable(v, env))
+		}
+	}
+
+	return models.Explain(scores.Final,
+		fmt.Sprintf("final score of profile %s v%d, %s", p.Name, p.Version, p.final), details...)
+}
+
+// explain explains the value of the component for a content type
+func (c *component) explain(contentType models.ContentType, value float64, env *Env) models.Explanation {
+	expr := c.expr(contentType)
+	if expr == nil {
+		return models.Explain(value, fmt.Sprintf("%s, no expression for type %q", c.name, contentType))
+	}
+
+	details := make([]models.Explanation, len(expr.Variables()))
+	for i, v := range expr.Variables() {
+		details[i] = explainVariable(v, env)
+	}
+	return models.Explain(value, fmt.Sprintf("%s of %s, %s", c.name, contentType, expr), details...)
+}
+
+// explainVariable explains the value of a variable
+func explainVariable(v Variable, env *Env) models.Explanation {
+	return models.Explain(env[v], v.String())
+}
+
+// Profiles is an immutable set of compiled profiles
+type Profiles struct {
+	byName      map[string]*Profile
+	defaultName string
+}
+
+// Default returns the default profile
+func (ps *Profiles) Default() *Profile {
+	return ps.byName[ps.defaultName]
+}
+
+// Get returns a profile by name, the default profile for an empty name
+func (ps *Profiles) Get(name string) (*Profile, bool) {
+	if name == "" {
+		return ps.Default(), true
+	}
+	profile, ok := ps.byName[name]
+	return profile, ok
+}
+
+// Names returns the profile names in alphabetical order
+func (ps *Profiles) Names() []string {
+	names := make([]string, 0, len(ps.byName))
+	for name := range ps.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Builtin compiles the built-in profiles
+func Builtin() *Profiles {
+	profiles, err := CompileConfig(DefaultConfig())
+	if err != nil {
+		panic(err)
+	}
+	return profiles
+}
+
+// CompileConfig validates and compiles the profiles of a configuration
+func CompileConfig(config Config) (*Profiles, error) {
+	if len(config.Profiles) == 0 {
+		return nil, fmt.Errorf("no scoring profiles declared")
+	}
+
+	ps := &Profiles{byName: make(map[string]*Profile, len(config.Profiles)), defaultName: config.Default}
+	for i := range config.Profiles {
+		profile, err := compileProfile(&config.Profiles[i])
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := ps.byName[profile.Name]; ok {
+			return nil, fmt.Errorf("duplicate scoring profile %q", profile.Name)
+		}
+		ps.byName[profile.Name] = profile
+	}
+
+	if ps.defaultName == "" && len(config.Profiles) == 1 {
+		ps.defaultName = config.Profiles[0].Name
+	}
+	if _, ok := ps.byName[ps.defaultName]; !ok {
+		return nil, fmt.Errorf("default scoring profile %q is not declared", ps.defaultName)
+	}
+	return ps, nil
+}
+
+// compileProfile validates and compiles a profile
+func compileProfile(config *ProfileConfig) (*Profile, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("scoring profile without a name")
+	}
+	if config.Version < 1 {
+		return nil, fmt.Errorf("scoring profile %q: version must be at least 1", config.Name)
+	}
+
+	p := &Profile{Name: config.Name, Version: config.Version}
+	components := []struct {
+		target      *component
+		key, name   string
+		expressions Expressions
+		missing     float64
+	}{
+		{&p.base, "base", "base score", config.Base, 0},
+		{&p.typeMultiplier, "type_multiplier", "type multiplier", config.TypeMultiplier, 1},
+		{&p.freshness, "freshness", "freshness score", config.Freshness, 0},
+		{&p.engagement, "engagement", "engagement score", config.Engagement, 0},
+	}
+	for _, c := range components {
+		*c.target = component{name: c.name, byType: make(map[models.ContentType]*Expr), missing: c.missing}
+		for contentType, source := range c.expressions {
+			expr, err := Compile(source, ContentVariables...)
+			if err != nil {
+				return nil, fmt.Errorf("scoring profile %q: %s[%s]: %w", config.Name, c.key, contentType, err)
+			}
+			switch models.ContentType(contentType) {
+			case AnyType:
+				c.target.fallback = expr
+			case models.ContentTypeVideo, models.ContentTypeText:
+				c.target.byType[models.ContentType(contentType)] = expr
+			default:
+				return nil, fmt.Errorf("scoring profile %q: %s: unknown content type %q", config.Name, c.key, contentType)
+			}
+		}
+	}
+
+	final, err := Compile(config.Final, FinalVariables...)
+	if err != nil {
+		return nil, fmt.Errorf("scoring profile %q: final: %w", config.Name, err)
+	}
+	p.final = final
+	return p, nil
+}
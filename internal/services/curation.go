@@ -0,0 +1,69 @@
+package services
+
+import (
+	"search-engine-service/internal/curation"
+	"search-engine-service/internal/database/models"
+)
+
+// curateHits removes the hidden hits and, when the hits are ranked by
+// relevance, multiplies the scores of the boosted and buried hits and ranks
+// them again
+func curateHits(plan *curation.Plan, hits []models.SearchHit, relevanceRanked bool) []models.SearchHit {
+	if plan.Empty() {
+		return hits
+	}
+
+	curated := make([]models.SearchHit, 0, len(hits))
+	for _, hit := range hits {
+		if _, hidden := plan.Hidden(hit.ID); hidden {
+			continue
+		}
+		if relevanceRanked {
+			hit.Score *= curation.Factor(plan.Boosts(&hit.Content))
+		}
+		curated = append(curated, hit)
+	}
+	if relevanceRanked {
+		sortByScore(curated)
+	}
+	return curated
+}
+
+// placePins moves the pinned contents to their positions. A pinned content
+// collapsed into a duplicate is taken from the ranked hits, one that did not
+// match the query is loaded and scored on popularity alone, as long as it
+// passes the filters.
+func (ss *SearchService) placePins(plan *curation.Plan, hits, ranked []models.SearchHit, p *searchParams, maxRelevance, maxPopularity float64) ([]models.SearchHit, error) {
+	listed := make(map[uint]bool, len(ranked))
+	for _, hit := range ranked {
+		listed[hit.ID] = true
+	}
+
+	var extra []models.SearchHit
+	for _, hit := range ranked {
+		if _, ok := plan.Pinned(hit.ID); ok {
+			extra = append(extra, hit)
+		}
+	}
+
+	var missing []uint
+	for _, pin := range plan.Pins {
+		if _, hidden := plan.Hidden(pin.ID); !hidden && !listed[pin.ID] {
+			missing = append(missing, pin.ID)
+		}
+	}
+	if len(missing) > 0 {
+		contents, err := ss.contentRepo.FindByIDs(missing, p.filters)
+		if err != nil {
+			return nil, err
+		}
+		for i := range contents {
+			ss.scoringService.CalculateScoreWith(p.profile, &contents[i], p.now)
+			hit := models.SearchHit{Content: contents[i], PopularityScore: contents[i].FinalScore}
+			hit.Score = ss.blendScore(&hit, maxRelevance, maxPopularity) * curation.Factor(plan.Boosts(&hit.Content))
+			extra = append(extra, hit)
+		}
+	}
+
+	return plan.Place(hits, extra), nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"time"
+
+	"search-engine-service/internal/curation"
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/database/repository"
+
+	"gorm.io/gorm"
+)
+
+// CurationService manages the curation rules and keeps a compiled copy of
+// them for the search pipeline, reloaded when a rule changes or the copy
+// expires
+type CurationService struct {
+	repo  models.CurationRepository
+	rules *reloader[*curation.Set]
+}
+
+// NewCurationService creates a new curation service
+func NewCurationService(db *gorm.DB, reloadInterval time.Duration) *CurationService {
+	s := &CurationService{repo: repository.NewCurationRepository(db)}
+	s.rules = newReloader("curation rules", reloadInterval, func() (*curation.Set, error) {
+		rules, err := s.repo.FindAll()
+		if err != nil {
+			return nil, err
+		}
+		return curation.Compile(rules), nil
+	})
+	return s
+}
+
+// Rules returns the compiled rules, reloading them from the database once
+// the reload interval has passed so changes made by other instances apply
+// without a restart. A failed reload keeps serving the previous rules.
+func (s *CurationService) Rules() *curation.Set {
+	return s.rules.Get()
+}
+
+// Reload compiles the rules currently stored in the database
+func (s *CurationService) Reload() error {
+	return s.rules.Reload()
+}
+
+// List returns every curation rule
+func (s *CurationService) List() ([]models.CurationRule, error) {
+	return s.repo.FindAll()
+}
+
+// Get returns a curation rule by ID
+func (s *CurationService) Get(id uint) (*models.CurationRule, error) {
+	return s.repo.FindByID(id)
+}
+
+// Create validates and stores a new rule
+func (s *CurationService) Create(rule *models.CurationRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	rule.ID = 0
+	if err := s.repo.Create(rule); err != nil {
+		return err
+	}
+	s.rules.reloadAfterWrite()
+	return nil
+}
+
+// Update validates and replaces an existing rule
+func (s *CurationService) Update(id uint, rule *models.CurationRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	existing, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	rule.ID = existing.ID
+	rule.CreatedAt = existing.CreatedAt
+	if err := s.repo.Update(rule); err != nil {
+		return err
+	}
+	s.rules.reloadAfterWrite()
+	return nil
+}
+
+// Delete removes a rule
+func (s *CurationService) Delete(id uint) error {
+	if _, err := s.repo.FindByID(id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	s.rules.reloadAfterWrite()
+	return nil
+}
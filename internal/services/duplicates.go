@@ -0,0 +1,138 @@
+package services
+
+import (
+	"sort"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/dedup"
+	"search-engine-service/internal/query"
+)
+
+// duplicateText returns the text a content is fingerprinted from, tags are
+// left out as providers tag the same story differently
+func duplicateText(content *models.Content) string {
+	return content.Title + "\n" + content.Description
+}
+
+// fingerprintContents computes the near-duplicate fingerprints of contents
+func fingerprintContents(contents []models.Content) {
+	for i := range contents {
+		contents[i].Fingerprint = dedup.Fingerprint(duplicateText(&contents[i]))
+	}
+}
+
+// clusterDuplicates regroups every stored content into clusters of near
+// duplicates, the earliest published content of a cluster being canonical,
+// and stores the clusters that changed
+func (ss *SearchService) clusterDuplicates() (int, error) {
+	contents, err := ss.contentRepo.FindFingerprints()
+	if err != nil {
+		return 0, err
+	}
+
+	// Contents stored before fingerprinting was introduced get one now
+	missing := make(map[uint]bool)
+	for i := range contents {
+		if contents[i].Fingerprint == 0 {
+			contents[i].Fingerprint = dedup.Fingerprint(duplicateText(&contents[i]))
+			missing[contents[i].ID] = contents[i].Fingerprint != 0
+		}
+	}
+
+	sort.SliceStable(contents, func(i, j int) bool {
+		if !contents[i].PublishedAt.Equal(contents[j].PublishedAt) {
+			return contents[i].PublishedAt.Before(contents[j].PublishedAt)
+		}
+		return contents[i].ID < contents[j].ID
+	})
+	items := make([]dedup.Item, len(contents))
+	for i := range contents {
+		items[i] = dedup.Item{ID: contents[i].ID, Fingerprint: contents[i].Fingerprint}
+	}
+	canonical := dedup.Cluster(items, ss.searchConfig.DuplicateMaxDistance)
+
+	var changed []models.Content
+	clustered := 0
+	for i := range contents {
+		content := &contents[i]
+		clusterID := canonical[content.ID]
+		if clusterID != content.ID {
+			clustered++
+		}
+		if clusterID != content.ClusterID || missing[content.ID] {
+			content.ClusterID = clusterID
+			changed = append(changed, *content)
+		}
+	}
+
+	if len(changed) == 0 {
+		return clustered, nil
+	}
+	return clustered, ss.contentRepo.UpdateClusters(changed)
+}
+
+// clusterKey returns the cluster of a content, contents not clustered yet
+// form their own
+func clusterKey(content *models.Content) uint {
+	if content.ClusterID != 0 {
+		return content.ClusterID
+	}
+	return content.ID
+}
+
+// collapseHits keeps the first hit of every cluster, counting the others as
+// its duplicates
+func collapseHits(hits []models.SearchHit) []models.SearchHit {
+	first := make(map[uint]int)
+	collapsed := hits[:0:0]
+	for _, hit := range hits {
+		key := clusterKey(&hit.Content)
+		if i, ok := first[key]; ok {
+			collapsed[i].Duplicates++
+			collapsed[i].AlternateURLs = append(collapsed[i].AlternateURLs, hit.URL)
+			continue
+		}
+		first[key] = len(collapsed)
+		collapsed = append(collapsed, hit)
+	}
+	return collapsed
+}
+
+// hitContents returns the contents of the hits
+func hitContents(hits []models.SearchHit) []models.Content {
+	contents := make([]models.Content, len(hits))
+	for i := range hits {
+		contents[i] = hits[i].Content
+	}
+	return contents
+}
+
+// attachDuplicates adds the matching duplicates of the canonical hits of a
+// collapsed database search
+func (ss *SearchService) attachDuplicates(node query.Node, filters *models.SearchFilters, hits []models.SearchHit) error {
+	var clusterIDs []uint
+	for i := range hits {
+		if hits[i].ClusterID != 0 {
+			clusterIDs = append(clusterIDs, hits[i].ClusterID)
+		}
+	}
+
+	duplicates, err := ss.contentRepo.FindDuplicates(node, filters, clusterIDs)
+	if err != nil {
+		return err
+	}
+
+	byCluster := make(map[uint]*models.SearchHit, len(hits))
+	for i := range hits {
+		if hits[i].ClusterID != 0 {
+			byCluster[hits[i].ClusterID] = &hits[i]
+		}
+	}
+	for _, duplicate := range duplicates {
+		if hit, ok := byCluster[duplicate.ClusterID]; ok {
+			hit.Duplicates++
+			hit.AlternateURLs = append(hit.AlternateURLs, duplicate.URL)
+		}
+	}
+	return nil
+}
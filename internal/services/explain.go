@@ -0,0 +1,250 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"search-engine-service/internal/curation"
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/index"
+)
+
+// explainer explains the scores of the hits of a search
+type explainer struct {
+	ss *SearchService
+	// idx is nil for database searches, which have no text relevance
+	idx    *index.Index
+	params *searchParams
+	// plan holds the curation rules triggered by the query, nil for
+	// database searches
+	plan *curation.Plan
+
+	maxRelevance  float64
+	maxPopularity float64
+
+	// 1-based ranks of the keyword and vector rankings, and the similarities
+	// of the vector ranking
+	keywordRanks map[uint]int
+	vectorRanks  map[uint]int
+	similarities map[uint]float64
+}
+
+// newExplainer creates an explainer for hits ranked together with the
+// keyword and vector rankings they came from
+func (ss *SearchService) newExplainer(idx *index.Index, p *searchParams, hits []models.SearchHit, keyword, semantic []index.Hit) *explainer {
+	e := &explainer{
+		ss:           ss,
+		idx:          idx,
+		params:       p,
+		keywordRanks: make(map[uint]int, len(keyword)),
+		vectorRanks:  make(map[uint]int, len(semantic)),
+		similarities: make(map[uint]float64, len(semantic)),
+	}
+	for _, hit := range hits {
+		if hit.RelevanceScore > e.maxRelevance {
+			e.maxRelevance = hit.RelevanceScore
+		}
+		if hit.PopularityScore > e.maxPopularity {
+			e.maxPopularity = hit.PopularityScore
+		}
+	}
+	for rank, hit := range keyword {
+		e.keywordRanks[hit.DocID] = rank + 1
+	}
+	for rank, hit := range semantic {
+		e.vectorRanks[hit.DocID] = rank + 1
+		e.similarities[hit.DocID] = hit.Score
+	}
+	return e
+}
+
+// explain explains the score of a hit, the blend of its relevance and
+// popularity adjusted by the curation rules
+func (e *explainer) explain(hit *models.SearchHit) models.Explanation {
+	explanation := e.blend(hit)
+	if e.plan == nil || !e.params.relevanceRanked() {
+		return explanation
+	}
+
+	if boosts := e.plan.Boosts(&hit.Content); len(boosts) > 0 {
+		details := []models.Explanation{explanation}
+		for _, boost := range boosts {
+			details = append(details, models.Explain(boost.Factor,
+				fmt.Sprintf("curation rule %d boost of %s %q", boost.Rule, boost.Target, boost.Value)))
+		}
+		explanation = models.Explain(explanation.Value*curation.Factor(boosts), "curated score, score * boost factors", details...)
+	}
+	if pin, ok := e.plan.Pinned(hit.ID); ok && hit.Pinned {
+		explanation = models.Explain(explanation.Value,
+			fmt.Sprintf("pinned at position %d by curation rule %d", pin.Position, pin.Rule), explanation)
+	}
+	return explanation
+}
+
+// blend explains the blended score of a hit
+func (e *explainer) blend(hit *models.SearchHit) models.Explanation {
+	weight := e.ss.searchConfig.RelevanceWeight
+
+	relevance := 0.0
+	if e.maxRelevance > 0 {
+		relevance = weight * hit.RelevanceScore / e.maxRelevance
+	}
+	popularity := 0.0
+	if e.maxPopularity > 0 {
+		popularity = (1 - weight) * hit.PopularityScore / e.maxPopularity
+	}
+
+	return models.Explain(relevance+popularity, "score, the sum of the weighted relevance and popularity",
+		models.Explain(relevance, "relevance weight * relevance / best relevance",
+			models.Explain(weight, "relevance weight"),
+			e.relevance(hit),
+			models.Explain(e.maxRelevance, "best relevance of the results"),
+		),
+		models.Explain(popularity, "(1 - relevance weight) * popularity / best popularity",
+			models.Explain(1-weight, "popularity weight"),
+			e.ss.scoringService.ExplainScoreWith(e.params.profile, &hit.Content, e.params.now),
+			models.Explain(e.maxPopularity, "best popularity of the results"),
+		),
+	)
+}
+
+// relevance explains the relevance score of a hit for the search mode
+func (e *explainer) relevance(hit *models.SearchHit) models.Explanation {
+	if e.idx == nil {
+		return models.Explain(hit.RelevanceScore, "relevance, not scored by database searches")
+	}
+
+	switch e.params.mode {
+	case models.SearchModeVector:
+		return e.similarity(hit.ID)
+	case models.SearchModeHybrid:
+		k := e.ss.searchConfig.RRFK
+		var details []models.Explanation
+		if rank, ok := e.keywordRanks[hit.ID]; ok {
+			details = append(details, models.Explain(1/(k+float64(rank)), fmt.Sprintf("keyword rank %d, 1 / (k + rank)", rank),
+				e.keyword(hit.ID)))
+		}
+		if rank, ok := e.vectorRanks[hit.ID]; ok {
+			details = append(details, models.Explain(1/(k+float64(rank)), fmt.Sprintf("vector rank %d, 1 / (k + rank)", rank),
+				e.similarity(hit.ID)))
+		}
+		details = append(details, models.Explain(k, "k"))
+		return models.Explain(hit.RelevanceScore, "relevance, reciprocal rank fusion of the keyword and vector rankings", details...)
+	default:
+		return e.keyword(hit.ID)
+	}
+}
+
+// keyword explains the BM25F score of a content
+func (e *explainer) keyword(id uint) models.Explanation {
+	explanation, _ := e.idx.Explain(e.params.node, e.ss.indexOptions(), id)
+	return explanation
+}
+
+// similarity explains the vector similarity of a content
+func (e *explainer) similarity(id uint) models.Explanation {
+	similarity, ok := e.similarities[id]
+	if !ok {
+		return models.Explain(0, fmt.Sprintf("not among the %d nearest contents with a similarity of at least %g",
+			e.ss.searchConfig.VectorCandidates, e.ss.searchConfig.VectorMinSimilarity))
+	}
+	return models.Explain(similarity, fmt.Sprintf("cosine similarity of the embeddings of %q and the content", queryText(e.params.node)))
+}
+
+// explainMiss explains why a content is not among the results
+func (e *explainer) explainMiss(content *models.Content, filters []models.FilterCheck, hits []models.SearchHit) models.Explanation {
+	var details []models.Explanation
+	if e.plan != nil {
+		if rule, hidden := e.plan.Hidden(content.ID); hidden {
+			details = append(details, models.Explain(0, fmt.Sprintf("hidden by curation rule %d", rule)))
+		}
+	}
+
+	switch e.params.mode {
+	case models.SearchModeVector:
+		details = append(details, e.similarity(content.ID))
+	case models.SearchModeHybrid:
+		details = append(details, e.keyword(content.ID), e.similarity(content.ID))
+	default:
+		details = append(details, e.keyword(content.ID))
+	}
+
+	for _, check := range filters {
+		if !check.Passed {
+			details = append(details, models.Explain(0, "failed filter "+check.String()))
+		}
+	}
+
+	if e.params.collapse {
+		for i := range hits {
+			if clusterKey(&hits[i].Content) == clusterKey(content) {
+				details = append(details, models.Explain(0, fmt.Sprintf("collapsed into the duplicate %d ranked %d", hits[i].ID, i+1)))
+				break
+			}
+		}
+	}
+
+	return models.Explain(0, "not among the results", details...)
+}
+
+// Explain explains the rank of a content among the results of a search
+func (ss *SearchService) Explain(req *models.SearchRequest, id uint) (*models.SearchExplanation, error) {
+	q, err := ss.prepareQuery(req)
+	if err != nil {
+		return nil, err
+	}
+	if q.expanded == nil {
+		return nil, &models.ParameterError{Key: "q", Message: "is required"}
+	}
+
+	content, err := ss.contentRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rank under the requested sort, the page itself does not matter
+	page, err := pageRequest(&models.SearchRequest{Sort: req.Sort, Order: req.Order}, true, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := ss.searchIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &searchParams{
+		query:    req.Query,
+		node:     q.expanded,
+		mode:     q.mode,
+		profile:  q.profile,
+		filters:  &req.Filters,
+		page:     page,
+		collapse: req.Collapse,
+		explain:  true,
+		now:      time.Now(),
+	}
+	hits, _, e, err := ss.rankIndexMatches(idx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.SearchExplanation{
+		ID:       id,
+		Total:    int64(len(hits)),
+		Rewrites: q.rewrites,
+		Filters:  req.Filters.Check(content, p.now),
+		Curation: e.plan.Rules,
+	}
+	for i := range hits {
+		if hits[i].ID == id {
+			result.Matched = true
+			result.Rank = i + 1
+			result.Explanation = e.explain(&hits[i])
+			return result, nil
+		}
+	}
+
+	result.Explanation = e.explainMiss(content, result.Filters, hits)
+	return result, nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"search-engine-service/internal/database/models"
+)
+
+// maxTagFacets is the number of tags returned in the tag facet
+const maxTagFacets = 10
+
+// Search parameters that filter on a facet value
+const (
+	facetParamType      = "type"
+	facetParamProvider  = "provider"
+	facetParamLanguage  = "language"
+	facetParamTag       = "tag"
+	facetParamPublished = "published"
+)
+
+// ComputeFacets counts the contents per type, provider, language, tag and
+// publication date bucket
+func ComputeFacets(contents []models.Content, now time.Time) *models.Facets {
+	types := make(map[string]int)
+	providers := make(map[string]int)
+	languages := make(map[string]int)
+	tags := make(map[string]int)
+	published := make(map[string]int)
+
+	for i := range contents {
+		content := &contents[i]
+		types[string(content.Type)]++
+		providers[content.Provider]++
+		if content.Language != "" {
+			languages[content.Language]++
+		}
+
+		// Count each tag once per content
+		seen := make(map[string]bool)
+		for _, tag := range strings.Split(content.Tags, ",") {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			tags[tag]++
+		}
+
+		for _, bucket := range models.PublishedBuckets(content.PublishedAt, now) {
+			published[bucket]++
+		}
+	}
+
+	// Publication buckets keep their natural order, including empty ones
+	publishedFacet := make([]models.FacetValue, 0, 4)
+	for _, bucket := range []string{models.PublishedLastWeek, models.PublishedLastMonth, models.PublishedLastQuarter, models.PublishedOlder} {
+		publishedFacet = append(publishedFacet, models.FacetValue{Value: bucket, Count: published[bucket], Param: facetParamPublished})
+	}
+
+	return &models.Facets{
+		Type:      facetValues(types, facetParamType, 0),
+		Provider:  facetValues(providers, facetParamProvider, 0),
+		Language:  facetValues(languages, facetParamLanguage, 0),
+		Tags:      facetValues(tags, facetParamTag, maxTagFacets),
+		Published: publishedFacet,
+	}
+}
+
+// facetValues orders counted values by count then value, keeping at most
+// limit values when limit is positive
+func facetValues(counts map[string]int, param string, limit int) []models.FacetValue {
+	values := make([]models.FacetValue, 0, len(counts))
+	for value, count := range counts {
+		values = append(values, models.FacetValue{Value: value, Count: count, Param: param})
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+
+	if limit > 0 && len(values) > limit {
+		values = values[:limit]
+	}
+	return values
+}
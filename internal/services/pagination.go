@@ -0,0 +1,172 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"search-engine-service/internal/database/models"
+)
+
+// sortKey is the value a hit is sorted by, only time is set when sorting by
+// publication date
+type sortKey struct {
+	value float64
+	time  time.Time
+}
+
+// hitSortKey returns the sort key of a hit
+func hitSortKey(hit *models.SearchHit, field models.SortField) sortKey {
+	switch field {
+	case models.SortRelevance:
+		return sortKey{value: hit.Score}
+	case models.SortNewest:
+		return sortKey{time: hit.PublishedAt}
+	case models.SortViews:
+		return sortKey{value: float64(hit.Views)}
+	case models.SortReactions:
+		return sortKey{value: float64(hit.Reactions)}
+	default:
+		return sortKey{value: hit.FinalScore}
+	}
+}
+
+// compareKeys orders two positions in a sorted result list, breaking ties
+// by ascending ID
+func compareKeys(a sortKey, aID uint, b sortKey, bID uint, order models.SortOrder) int {
+	c := 0
+	switch {
+	case a.value < b.value || a.time.Before(b.time):
+		c = -1
+	case a.value > b.value || a.time.After(b.time):
+		c = 1
+	}
+	if order == models.OrderDesc {
+		c = -c
+	}
+	if c != 0 {
+		return c
+	}
+
+	switch {
+	case aID < bID:
+		return -1
+	case aID > bID:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// sortHits orders hits by the requested sort
+func sortHits(hits []models.SearchHit, page models.PageRequest) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		return compareKeys(hitSortKey(&hits[i], page.Sort), hits[i].ID, hitSortKey(&hits[j], page.Sort), hits[j].ID, page.Order) < 0
+	})
+}
+
+// Paginate slices a page out of the sorted hits, by page number or by
+// seeking from the cursor. The hits must be scored at now, which relevance
+// cursors carry to the next page.
+func Paginate(hits []models.SearchHit, page models.PageRequest, now time.Time) *models.SearchResult {
+	total := len(hits)
+	totalPages := int(math.Ceil(float64(total) / float64(page.Limit)))
+
+	var start, end int
+	if cursor := page.After; cursor != nil {
+		cursorKey := sortKey{value: cursor.Value, time: cursor.Time}
+		after := func(inclusive bool) int {
+			return sort.Search(total, func(i int) bool {
+				c := compareKeys(hitSortKey(&hits[i], page.Sort), hits[i].ID, cursorKey, cursor.ID, page.Order)
+				return c > 0 || (inclusive && c == 0)
+			})
+		}
+		// Pinned hits break the sort order, so seek from the position of
+		// the cursor's hit when it is still listed
+		if i, ok := pinnedPosition(hits, cursor.ID); ok {
+			after = func(inclusive bool) int {
+				if inclusive {
+					return i
+				}
+				return i + 1
+			}
+		}
+
+		if cursor.Backward {
+			end = after(true)
+			start = end - page.Limit
+			if start < 0 {
+				start = 0
+			}
+		} else {
+			start = after(false)
+			end = start + page.Limit
+		}
+	} else {
+		start = (page.Page - 1) * page.Limit
+		end = start + page.Limit
+	}
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+
+	result := &models.SearchResult{
+		Contents:    hits[start:end],
+		Total:       int64(total),
+		Page:        page.Page,
+		Limit:       page.Limit,
+		TotalPages:  totalPages,
+		HasNext:     end < total,
+		HasPrevious: start > 0,
+	}
+	setCursors(result, page, page.Sort, now)
+	return result
+}
+
+// pinnedPosition returns the position of a hit in a list holding pinned hits
+func pinnedPosition(hits []models.SearchHit, id uint) (int, bool) {
+	pinned, position := false, -1
+	for i := range hits {
+		pinned = pinned || hits[i].Pinned
+		if hits[i].ID == id {
+			position = i
+		}
+	}
+	return position, pinned && position >= 0
+}
+
+// setCursors adds the cursors continuing from either end of the result page,
+// keyed by the given sort. Relevance cursors carry the time the page was
+// scored at, as blended scores change with the time they are computed at.
+func setCursors(result *models.SearchResult, page models.PageRequest, field models.SortField, now time.Time) {
+	if len(result.Contents) == 0 {
+		return
+	}
+
+	var scoredAt *time.Time
+	if page.Sort == models.SortRelevance {
+		scoredAt = &now
+	}
+	cursor := func(hit *models.SearchHit, backward bool) string {
+		key := hitSortKey(hit, field)
+		return (&models.Cursor{
+			Sort:     page.Sort,
+			Order:    page.Order,
+			Value:    key.value,
+			Time:     key.time,
+			ID:       hit.ID,
+			Backward: backward,
+			ScoredAt: scoredAt,
+		}).Encode()
+	}
+
+	if result.HasNext {
+		result.NextCursor = cursor(&result.Contents[len(result.Contents)-1], false)
+	}
+	if result.HasPrevious {
+		result.PrevCursor = cursor(&result.Contents[0], true)
+	}
+}
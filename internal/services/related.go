@@ -0,0 +1,79 @@
+package services
+
+import (
+	"time"
+
+	"search-engine-service/internal/database/models"
+)
+
+// relatedCandidates is the number of similar contents ranked before the
+// popularity blend picks the returned ones
+const relatedCandidates = 100
+
+// relatedKey identifies a cached related content list
+type relatedKey struct {
+	id        uint
+	limit     int
+	crossType bool
+}
+
+// GetRelatedContent returns contents similar to the given content in the same
+// language, by shared tags and title and description terms, ranked with the
+// same relevance and popularity blend as searches. Other content types are
+// only included with crossType. Results are cached until the next refresh.
+func (ss *SearchService) GetRelatedContent(id uint, limit int, crossType bool) ([]models.SearchHit, error) {
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+	key := relatedKey{id: id, limit: limit, crossType: crossType}
+
+	ss.relatedMu.RLock()
+	cached, ok := ss.related[key]
+	generation := ss.relatedGeneration
+	ss.relatedMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	source, err := ss.contentRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := ss.searchIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	matches := idx.Related(id, crossType, relatedCandidates)
+	ids := make([]uint, len(matches))
+	for i, match := range matches {
+		ids[i] = match.DocID
+	}
+
+	contents, err := ss.contentRepo.FindByIDs(ids, &models.SearchFilters{Language: source.Language})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := ss.rankHits(matches, contents, ss.scoringService.DefaultProfile(), time.Now())
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	// Contents refreshed meanwhile may have made the list stale
+	ss.relatedMu.Lock()
+	if ss.relatedGeneration == generation {
+		ss.related[key] = hits
+	}
+	ss.relatedMu.Unlock()
+	return hits, nil
+}
+
+// clearRelated drops the cached related contents
+func (ss *SearchService) clearRelated() {
+	ss.relatedMu.Lock()
+	ss.related = make(map[relatedKey][]models.SearchHit)
+	ss.relatedGeneration++
+	ss.relatedMu.Unlock()
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// reloader holds a value loaded from the database, reloaded once the reload
+// interval has passed so changes made by other instances apply without a
+// restart
+type reloader[T any] struct {
+	name     string
+	interval time.Duration
+	load     func() (T, error)
+
+	mu       sync.RWMutex
+	value    T
+	loaded   bool
+	loadedAt time.Time
+}
+
+// newReloader creates a reloader of the value returned by load, named in the
+// logs of failed reloads
+func newReloader[T any](name string, interval time.Duration, load func() (T, error)) *reloader[T] {
+	return &reloader[T]{name: name, interval: interval, load: load}
+}
+
+// Get returns the value, reloading it once the reload interval has passed. A
+// failed reload keeps serving the previous value, the zero value when none
+// was loaded yet.
+func (r *reloader[T]) Get() T {
+	r.mu.RLock()
+	value, loaded, loadedAt := r.value, r.loaded, r.loadedAt
+	r.mu.RUnlock()
+
+	if loaded && time.Since(loadedAt) < r.interval {
+		return value
+	}
+
+	if err := r.Reload(); err != nil {
+		log.Printf("Failed to reload %s: %v", r.name, err)
+		// Retry on the next interval rather than on every call
+		r.mu.Lock()
+		r.loadedAt = time.Now()
+		r.mu.Unlock()
+		return value
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.value
+}
+
+// Reload loads the value
+func (r *reloader[T]) Reload() error {
+	value, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.value = value
+	r.loaded = true
+	r.loadedAt = time.Now()
+	r.mu.Unlock()
+	return nil
+}
+
+// reloadAfterWrite applies a change to the stored value immediately, the
+// write itself succeeded so a failed reload is only logged
+func (r *reloader[T]) reloadAfterWrite() {
+	if err := r.Reload(); err != nil {
+		log.Printf("Failed to reload %s: %v", r.name, err)
+	}
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/scoring"
+)
+
+// ErrRescoreRunning is returned when a rescore is requested while one runs
+var ErrRescoreRunning = errors.New("a rescore is already running")
+
+// RescoreReport summarizes a rescoring run
+type RescoreReport struct {
+	// Profile and Version identify the profile the contents were scored with
+	Profile string `json:"profile"`
+	Version int    `json:"version"`
+	// Scanned counts the contents read, Updated the ones written back
+	Scanned   int       `json:"scanned"`
+	Updated   int       `json:"updated"`
+	Batches   int       `json:"batches"`
+	StartedAt time.Time `json:"started_at"`
+	Duration  string    `json:"duration"`
+}
+
+// Rescore recomputes the stored scores of every content with the default
+// profile, walking the table in batches and writing back only the contents
+// whose scores changed, so ordering by the stored final_score agrees with the
+// scores searches display. Only one rescore runs at a time.
+func (ss *SearchService) Rescore(ctx context.Context) (*RescoreReport, error) {
+	if !ss.rescoreMu.TryLock() {
+		return nil, ErrRescoreRunning
+	}
+	defer ss.rescoreMu.Unlock()
+
+	// Score with the engagement priors of the current corpus
+	if err := ss.RefreshPriors(); err != nil {
+		return nil, err
+	}
+
+	profile := ss.scoringService.DefaultProfile()
+	report := &RescoreReport{Profile: profile.Name, Version: profile.Version, StartedAt: time.Now()}
+	batchSize := ss.searchConfig.RescoreBatchSize
+	if batchSize < 1 {
+		batchSize = indexBatchSize
+	}
+
+	// Score every content at the same instant so their freshness compares
+	now := report.StartedAt
+	var lastID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		batch, err := ss.contentRepo.FindAfterID(lastID, batchSize)
+		if err != nil {
+			return report, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		var changed []models.Content
+		for i := range batch {
+			if ss.scoringService.Rescore(&batch[i], now) {
+				changed = append(changed, batch[i])
+			}
+		}
+		if len(changed) > 0 {
+			if err := ss.contentRepo.UpdateScores(changed); err != nil {
+				return report, err
+			}
+			ss.updateSuggestionScores(changed)
+		}
+
+		report.Scanned += len(batch)
+		report.Updated += len(changed)
+		report.Batches++
+		lastID = batch[len(batch)-1].ID
+		log.Printf("Rescored %d contents, %d updated, in %s", report.Scanned, report.Updated, time.Since(report.StartedAt))
+	}
+
+	if report.Updated > 0 {
+		ss.indexMu.Lock()
+		if ss.indexReady {
+			ss.rebuildSuggestionsLocked()
+		}
+		ss.indexMu.Unlock()
+		ss.clearRelated()
+	}
+
+	report.Duration = time.Since(report.StartedAt).String()
+	log.Printf("Rescore with profile %s v%d finished: %d of %d contents updated in %s",
+		report.Profile, report.Version, report.Updated, report.Scanned, report.Duration)
+	return report, nil
+}
+
+// RefreshPriors recomputes the engagement priors from the stored contents
+func (ss *SearchService) RefreshPriors() error {
+	stats, err := ss.contentRepo.FindEngagementStats()
+	if err != nil {
+		return err
+	}
+	ss.scoringService.SetPriors(scoring.ComputePriors(stats, ss.searchConfig.EngagementPriorStrength))
+	return nil
+}
+
+// updateSuggestionScores applies the new scores of rescored contents to
+// their completions, the trie is rebuilt once the rescore finishes
+func (ss *SearchService) updateSuggestionScores(contents []models.Content) {
+	ss.indexMu.Lock()
+	defer ss.indexMu.Unlock()
+
+	if !ss.indexReady {
+		return
+	}
+	for i := range contents {
+		if doc, ok := ss.suggestDocs[contents[i].ID]; ok {
+			doc.Score = contents[i].FinalScore
+			ss.suggestDocs[contents[i].ID] = doc
+		}
+	}
+}
+
+// AutoRescore rescores the contents right away, so a changed profile
+// applies on startup, then periodically until the context is done. A
+// non-positive interval disables it.
+func (ss *SearchService) AutoRescore(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := ss.Rescore(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("Auto rescore failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Auto rescore stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
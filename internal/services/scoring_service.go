@@ -1,118 +1,112 @@
 package services
 
 import (
+	"fmt"
+	"math"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/scoring"
 )
 
-// ScoringService handles content scoring calculations
-type ScoringService struct{}
+// ScoringService handles content scoring calculations with the configured
+// scoring profiles
+type ScoringService struct {
+	profiles *scoring.Profiles
+	// priors holds the engagement priors, replaced after every ingestion
+	priors atomic.Pointer[scoring.Priors]
+}
 
-// NewScoringService creates a new scoring service
+// NewScoringService creates a new scoring service with the built-in profile
 func NewScoringService() *ScoringService {
-	return &ScoringService{}
+	return NewScoringServiceWithProfiles(scoring.Builtin())
+}
+
+// NewScoringServiceWithProfiles creates a new scoring service with the given
+// profiles
+func NewScoringServiceWithProfiles(profiles *scoring.Profiles) *ScoringService {
+	return &ScoringService{profiles: profiles}
+}
+
+// Profile returns a scoring profile by name, the default profile for an
+// empty name
+func (ss *ScoringService) Profile(name string) (*scoring.Profile, error) {
+	profile, ok := ss.profiles.Get(name)
+	if !ok {
+		return nil, &models.ParameterError{
+			Key:     "profile",
+			Message: fmt.Sprintf("must be one of '%s'", strings.Join(ss.profiles.Names(), "', '")),
+		}
+	}
+	return profile, nil
+}
+
+// DefaultProfile returns the profile contents are stored with
+func (ss *ScoringService) DefaultProfile() *scoring.Profile {
+	return ss.profiles.Default()
+}
+
+// Priors returns the engagement priors, nil until they are first computed
+func (ss *ScoringService) Priors() *scoring.Priors {
+	return ss.priors.Load()
+}
+
+// SetPriors replaces the engagement priors
+func (ss *ScoringService) SetPriors(priors *scoring.Priors) {
+	ss.priors.Store(priors)
 }
 
 // CalculateScore calculates the final score for a content item
 func (ss *ScoringService) CalculateScore(content *models.Content) {
-	// Calculate base score
-	baseScore := ss.calculateBaseScore(content)
-	
-	// Calculate type multiplier
-	typeMultiplier := ss.calculateTypeMultiplier(content.Type)
-	
-	// Calculate freshness score
-	freshnessScore := ss.calculateFreshnessScore(content.PublishedAt)
-	
-	// Calculate engagement score
-	engagementScore := ss.calculateEngagementScore(content)
-	
-	// Calculate final score
-	finalScore := (baseScore * typeMultiplier) + freshnessScore + engagementScore
-	
-	// Update content scores
-	content.BaseScore = baseScore
-	content.TypeMultiplier = typeMultiplier
-	content.FreshnessScore = freshnessScore
-	content.EngagementScore = engagementScore
-	content.FinalScore = finalScore
-}
-
-// calculateBaseScore calculates the base score based on content type
-func (ss *ScoringService) calculateBaseScore(content *models.Content) float64 {
-	switch content.Type {
-	case models.ContentTypeVideo:
-		// Video: views / 1000 + (likes / 100)
-		viewsScore := float64(content.Views) / 1000.0
-		likesScore := float64(content.Likes) / 100.0
-		return viewsScore + likesScore
-		
-	case models.ContentTypeText:
-		// Text: reading_time + (reactions / 50)
-		readingScore := float64(content.ReadingTime)
-		reactionsScore := float64(content.Reactions) / 50.0
-		return readingScore + reactionsScore
-		
-	default:
-		return 0
-	}
+	ss.CalculateScoreAt(content, time.Now())
 }
 
-// calculateTypeMultiplier returns the type multiplier
-func (ss *ScoringService) calculateTypeMultiplier(contentType models.ContentType) float64 {
-	switch contentType {
-	case models.ContentTypeVideo:
-		return 1.5
-	case models.ContentTypeText:
-		return 1.0
-	default:
-		return 1.0
-	}
+// CalculateScoreAt calculates the final score for a content item with the
+// freshness measured at the given time
+func (ss *ScoringService) CalculateScoreAt(content *models.Content, now time.Time) {
+	ss.CalculateScoreWith(ss.profiles.Default(), content, now)
 }
 
-// calculateFreshnessScore calculates the freshness score based on publication date
-func (ss *ScoringService) calculateFreshnessScore(publishedAt time.Time) float64 {
-	now := time.Now()
-	age := now.Sub(publishedAt)
-	
-	// Convert to days
-	days := age.Hours() / 24
-	
-	switch {
-	case days <= 7: // 1 week
-		return 5.0
-	case days <= 30: // 1 month
-		return 3.0
-	case days <= 90: // 3 months
-		return 1.0
-	default:
-		return 0.0
-	}
+// CalculateScoreWith calculates the scores of a content item with a profile,
+// recording the profile and its version on the content
+func (ss *ScoringService) CalculateScoreWith(profile *scoring.Profile, content *models.Content, now time.Time) {
+	scores := profile.Score(content, now, ss.Priors())
+
+	// Update content scores
+	content.BaseScore = scores.Base
+	content.TypeMultiplier = scores.TypeMultiplier
+	content.FreshnessScore = scores.Freshness
+	content.EngagementScore = scores.Engagement
+	content.FinalScore = scores.Final
+	content.ScoringProfile = profile.Name
+	content.ScoringVersion = profile.Version
 }
 
-// calculateEngagementScore calculates the engagement score
-func (ss *ScoringService) calculateEngagementScore(content *models.Content) float64 {
-	switch content.Type {
-	case models.ContentTypeVideo:
-		// Video: (likes / views) * 10
-		if content.Views > 0 {
-			engagement := float64(content.Likes) / float64(content.Views)
-			return engagement * 10.0
-		}
-		return 0
-		
-	case models.ContentTypeText:
-		// Text: (reactions / reading_time) * 5
-		if content.ReadingTime > 0 {
-			engagement := float64(content.Reactions) / float64(content.ReadingTime)
-			return engagement * 5.0
-		}
-		return 0
-		
-	default:
-		return 0
-	}
+// storedScoreScale is 10^n for the n decimals the score columns store
+const storedScoreScale = 1e4
+
+// Rescore recalculates the scores of a stored content item with the default
+// profile, reporting whether it needs to be written back: a score changed at
+// the precision it is stored with, or the item was scored by another profile
+// or profile version
+func (ss *ScoringService) Rescore(content *models.Content, now time.Time) bool {
+	before := *content
+	ss.CalculateScoreAt(content, now)
+
+	return content.ScoringProfile != before.ScoringProfile ||
+		content.ScoringVersion != before.ScoringVersion ||
+		!sameStoredScore(content.BaseScore, before.BaseScore) ||
+		!sameStoredScore(content.TypeMultiplier, before.TypeMultiplier) ||
+		!sameStoredScore(content.FreshnessScore, before.FreshnessScore) ||
+		!sameStoredScore(content.EngagementScore, before.EngagementScore) ||
+		!sameStoredScore(content.FinalScore, before.FinalScore)
+}
+
+// sameStoredScore reports whether two scores are stored as the same value
+func sameStoredScore(a, b float64) bool {
+	return math.Round(a*storedScoreScale) == math.Round(b*storedScoreScale)
 }
 
 // CalculateScoresForBatch calculates scores for multiple content items
@@ -123,19 +117,34 @@ func (ss *ScoringService) CalculateScoresForBatch(contents []models.Content) []m
 	return contents
 }
 
-// GetScoreBreakdown returns a detailed breakdown of the scoring
+// GetScoreBreakdown returns a detailed breakdown of the scoring, with the
+// engagement prior of the content's provider and type
 func (ss *ScoringService) GetScoreBreakdown(content *models.Content) map[string]float64 {
 	ss.CalculateScore(content)
-	
+	prior := ss.Priors().Lookup(content.Provider, content.Type)
+
 	return map[string]float64{
 		"base_score":       content.BaseScore,
 		"type_multiplier":  content.TypeMultiplier,
 		"freshness_score":  content.FreshnessScore,
 		"engagement_score": content.EngagementScore,
+		"prior_rate":       prior.Rate,
+		"prior_weight":     prior.Weight,
 		"final_score":      content.FinalScore,
 	}
 }
 
+// ExplainScore explains the final score of a content item, with the freshness
+// measured at the given time
+func (ss *ScoringService) ExplainScore(content *models.Content, now time.Time) models.Explanation {
+	return ss.ExplainScoreWith(ss.profiles.Default(), content, now)
+}
+
+// ExplainScoreWith explains the final score of a content item with a profile
+func (ss *ScoringService) ExplainScoreWith(profile *scoring.Profile, content *models.Content, now time.Time) models.Explanation {
+	return profile.Explain(content, now, ss.Priors())
+}
+
 // NormalizeScore normalizes a score to a 0-100 range
 func (ss *ScoringService) NormalizeScore(score float64, maxScore float64) float64 {
 	if maxScore == 0 {
@@ -145,14 +154,18 @@ func (ss *ScoringService) NormalizeScore(score float64, maxScore float64) float6
 }
 
 // GetMaxPossibleScore calculates the maximum possible score for a content type
+// with the default profile
 func (ss *ScoringService) GetMaxPossibleScore(contentType models.ContentType) float64 {
 	// This is a simplified calculation - in a real system, you might want to
 	// use historical data to determine realistic maximums
 	
+	// Freshness decays with age, so it peaks for content published now
+	now := time.Now()
+	scores := ss.profiles.Default().Score(&models.Content{Type: contentType, PublishedAt: now}, now, nil)
+
 	baseScore := 100.0 // Assuming max base score
-	typeMultiplier := ss.calculateTypeMultiplier(contentType)
-	freshnessScore := 5.0 // Max freshness score
+	freshnessScore := scores.Freshness
 	engagementScore := 10.0 // Max engagement score
 	
-	return (baseScore * typeMultiplier) + freshnessScore + engagementScore
+	return (baseScore * scores.TypeMultiplier) + freshnessScore + engagementScore
 } 
\ No newline at end of file
@@ -3,11 +3,23 @@ package services
 import (
 	"context"
 	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"search-engine-service/internal/analysis"
+	"search-engine-service/internal/config"
 	"search-engine-service/internal/database/models"
 	"search-engine-service/internal/database/repository"
+	"search-engine-service/internal/embedding"
+	"search-engine-service/internal/index"
 	"search-engine-service/internal/providers"
+	"search-engine-service/internal/query"
+	"search-engine-service/internal/scoring"
+	"search-engine-service/internal/suggest"
+	"search-engine-service/internal/vector"
 
 	"gorm.io/gorm"
 )
@@ -15,25 +27,211 @@ import (
 // SearchService handles search operations and content management
 type SearchService struct {
 	contentRepo     models.ContentRepository
+	metricsRepo     models.MetricsRepository
 	providerManager *providers.ProviderManager
 	scoringService  *ScoringService
+	synonymService  *SynonymService
+	curationService *CurationService
+	searchConfig    config.SearchConfig
+
+	index      *index.Index
+	indexMu    sync.Mutex
+	indexReady bool
+
+	// vectors holds the content embeddings, guarded by indexMu and rebuilt
+	// together with the index
+	embedder embedding.Embedder
+	vectors  *vector.HNSW
+
+	// suggestDocs holds the completion sources of every content, guarded by indexMu
+	suggestDocs map[uint]suggest.Document
+	suggestions *suggest.Trie
+	suggestMu   sync.RWMutex
+
+	// related caches related content lists until the contents change
+	related           map[relatedKey][]models.SearchHit
+	relatedGeneration uint64
+	relatedMu         sync.RWMutex
+
+	// rescoreMu is held while the stored scores are recomputed
+	rescoreMu sync.Mutex
 }
 
+// indexBatchSize is the number of rows loaded per query when building the index
+const indexBatchSize = 500
+
 // NewSearchService creates a new search service
-func NewSearchService(db *gorm.DB, providerManager *providers.ProviderManager) *SearchService {
+func NewSearchService(db *gorm.DB, providerManager *providers.ProviderManager, searchConfig config.SearchConfig) *SearchService {
 	contentRepo := repository.NewContentRepository(db)
-	scoringService := NewScoringService()
+	scoringService := NewScoringServiceWithProfiles(searchConfig.ScoringProfiles)
 	
 	return &SearchService{
 		contentRepo:     contentRepo,
+		metricsRepo:     repository.NewMetricsRepository(db),
 		providerManager: providerManager,
 		scoringService:  scoringService,
+		synonymService:  NewSynonymService(db, searchConfig.SynonymReloadInterval),
+		curationService: NewCurationService(db, searchConfig.CurationReloadInterval),
+		searchConfig:    searchConfig,
+		index:           index.New(),
+		embedder:        embedding.NewHashEmbedder(searchConfig.EmbeddingDimensions),
+		vectors:         vector.New(vector.DefaultParams()),
+		suggestions:     suggest.Build(nil),
+		related:         make(map[relatedKey][]models.SearchHit),
+	}
+}
+
+// Synonyms returns the service managing the synonym rules
+func (ss *SearchService) Synonyms() *SynonymService {
+	return ss.synonymService
+}
+
+// Scoring returns the service scoring contents with the configured profiles
+func (ss *SearchService) Scoring() *ScoringService {
+	return ss.scoringService
+}
+
+// Curation returns the service managing the curation rules
+func (ss *SearchService) Curation() *CurationService {
+	return ss.curationService
+}
+
+// BuildIndex (re)builds the in-memory search index from the database
+func (ss *SearchService) BuildIndex() error {
+	ss.indexMu.Lock()
+	defer ss.indexMu.Unlock()
+
+	return ss.buildIndexLocked()
+}
+
+// searchIndex returns the search index, building it on first use
+func (ss *SearchService) searchIndex() (*index.Index, error) {
+	ss.indexMu.Lock()
+	defer ss.indexMu.Unlock()
+
+	if !ss.indexReady {
+		if err := ss.buildIndexLocked(); err != nil {
+			return nil, err
+		}
+	}
+	return ss.index, nil
+}
+
+// buildIndexLocked walks the contents table in batches and indexes every row,
+// embedding the rows stored without an up-to-date embedding, the caller must
+// hold indexMu
+func (ss *SearchService) buildIndexLocked() error {
+	idx := index.New()
+	vectors := vector.New(vector.DefaultParams())
+	suggestDocs := make(map[uint]suggest.Document)
+
+	var lastID uint
+	for {
+		batch, err := ss.contentRepo.FindAfterID(lastID, indexBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		idx.AddBatch(batch)
+		var stale []models.Content
+		for i := range batch {
+			suggestDocs[batch[i].ID] = suggestDocument(&batch[i])
+			if ss.addVector(vectors, &batch[i]) {
+				stale = append(stale, batch[i])
+			}
+		}
+		if len(stale) > 0 {
+			if err := ss.contentRepo.UpdateEmbeddings(stale); err != nil {
+				log.Printf("Failed to store %d content embeddings: %v", len(stale), err)
+			}
+		}
+		lastID = batch[len(batch)-1].ID
+	}
+
+	ss.index = idx
+	ss.vectors = vectors
+	ss.suggestDocs = suggestDocs
+	ss.indexReady = true
+	ss.rebuildSuggestionsLocked()
+	ss.clearRelated()
+	log.Printf("Search index built with %d documents", idx.Len())
+	return nil
+}
+
+// syncContents applies upserted contents to the index and the completions
+func (ss *SearchService) syncContents(contents []models.Content) {
+	ss.indexMu.Lock()
+	defer ss.indexMu.Unlock()
+
+	// An index that was never built will load the rows on first use
+	if !ss.indexReady {
+		return
 	}
+
+	ss.index.AddBatch(contents)
+	for i := range contents {
+		ss.suggestDocs[contents[i].ID] = suggestDocument(&contents[i])
+		ss.addVector(ss.vectors, &contents[i])
+	}
+	ss.rebuildSuggestionsLocked()
+	ss.clearRelated()
+}
+
+// addVector adds the embedding of a content to the vector index, reporting
+// whether it had to be computed. Contents without any words are left out.
+func (ss *SearchService) addVector(vectors *vector.HNSW, content *models.Content) bool {
+	embedded, stale := ss.contentVector(content)
+	if embedding.IsZero(embedded) {
+		vectors.Remove(content.ID)
+	} else {
+		vectors.Add(content.ID, embedded)
+	}
+	return stale
+}
+
+// rebuildSuggestionsLocked rebuilds the completion trie, the caller must hold indexMu
+func (ss *SearchService) rebuildSuggestionsLocked() {
+	docs := make([]suggest.Document, 0, len(ss.suggestDocs))
+	for _, doc := range ss.suggestDocs {
+		docs = append(docs, doc)
+	}
+	trie := suggest.Build(docs)
+
+	ss.suggestMu.Lock()
+	ss.suggestions = trie
+	ss.suggestMu.Unlock()
+}
+
+// suggestDocument returns the completion sources of a content
+func suggestDocument(content *models.Content) suggest.Document {
+	return suggest.Document{
+		ID:    content.ID,
+		Title: content.Title,
+		Tags:  content.Tags,
+		Score: content.FinalScore,
+	}
+}
+
+// GetSuggestions returns the best title and tag completions for a prefix
+func (ss *SearchService) GetSuggestions(prefix string, limit int) ([]suggest.Suggestion, error) {
+	if _, err := ss.searchIndex(); err != nil {
+		return nil, err
+	}
+
+	ss.suggestMu.RLock()
+	trie := ss.suggestions
+	ss.suggestMu.RUnlock()
+
+	return trie.Suggest(prefix, limit), nil
 }
 
 // Search performs a search operation with the given parameters
-func (ss *SearchService) Search(query string, contentType models.ContentType, page, limit int) (*models.SearchResult, error) {
+func (ss *SearchService) Search(req *models.SearchRequest) (*models.SearchResult, error) {
 	// Validate parameters
+	page, limit := req.Page, req.Limit
 	if page < 1 {
 		page = 1
 	}
@@ -41,20 +239,535 @@ func (ss *SearchService) Search(query string, contentType models.ContentType, pa
 		limit = 10
 	}
 
+	q, err := ss.prepareQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	pageReq, err := pageRequest(req, q.expanded != nil, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var highlight *models.HighlightOptions
+	if req.Highlight != nil {
+		options := req.Highlight.WithDefaults()
+		if err := options.Validate(); err != nil {
+			return nil, err
+		}
+		highlight = &options
+	}
+
 	// Perform search
-	result, err := ss.contentRepo.Search(query, contentType, page, limit)
+	result, err := ss.searchContents(&searchParams{
+		query:    req.Query,
+		node:     q.expanded,
+		mode:     q.mode,
+		profile:  q.profile,
+		filters:  &req.Filters,
+		page:     pageReq,
+		collapse: req.Collapse,
+		explain:  req.Explain,
+		now:      pageReq.ScoreTime(time.Now()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Rewrites = q.rewrites
+
+	// Highlight the returned page only
+	if q.expanded != nil && highlight != nil {
+		ss.highlightHits(q.expanded, result.Contents, *highlight)
+	}
+
+	// Offer a spelling correction when the query found little
+	if q.node != nil && result.Total < int64(ss.searchConfig.SuggestionThreshold) {
+		result.DidYouMean = ss.didYouMean(q.text, q.node)
+	}
+
+	return result, nil
+}
+
+// searchQuery is a validated search query with the synonym rules applied
+type searchQuery struct {
+	// text and node are the query string and tree after the rewrite rules,
+	// expanded adds the synonyms to node and is what gets searched
+	text     string
+	node     query.Node
+	expanded query.Node
+	rewrites []models.QueryRewrite
+	mode     models.SearchMode
+	profile  *scoring.Profile
+}
+
+// prepareQuery validates the filters, mode and scoring profile of a search
+// and parses its query, applying the synonym rules
+func (ss *SearchService) prepareQuery(req *models.SearchRequest) (*searchQuery, error) {
+	if err := req.Filters.Validate(); err != nil {
+		return nil, err
+	}
+	mode, err := models.ParseSearchMode(string(req.Mode))
+	if err != nil {
+		return nil, err
+	}
+	profile, err := ss.scoringService.Profile(req.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the query language
+	node, err := parseQuery(req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rewrite and expand the query with the synonym rules
+	text, node, expanded, rewrites := ss.applySynonyms(req.Query, node)
+	return &searchQuery{text: text, node: node, expanded: expanded, rewrites: rewrites, mode: mode, profile: profile}, nil
+}
+
+// applySynonyms applies the rewrite rules to the query string and expands
+// the resulting query with the synonyms. It returns the rewritten query
+// string and its tree, the expanded tree to search with and the rules that
+// applied. The original query was already parsed so syntax errors point into
+// it, a rewrite that no longer parses or leaves no query is dropped.
+func (ss *SearchService) applySynonyms(queryString string, node query.Node) (string, query.Node, query.Node, []models.QueryRewrite) {
+	if node == nil {
+		return queryString, nil, nil, nil
+	}
+	rules := ss.synonymService.Rules()
+
+	rewritten, rewrites := rules.Rewrite(queryString)
+	if len(rewrites) > 0 {
+		rewrittenNode, err := parseQuery(rewritten)
+		switch {
+		case err != nil:
+			log.Printf("Dropping synonym rewrite of %q: %v", queryString, err)
+			rewritten, rewrites = queryString, nil
+		case rewrittenNode == nil:
+			// Rewriting the whole query away would match everything
+			rewritten, rewrites = queryString, nil
+		default:
+			node = rewrittenNode
+		}
+	}
+
+	expanded, expansions := rules.Expand(node)
+	return rewritten, node, expanded, append(rewrites, expansions...)
+}
+
+// searchParams holds the resolved parameters of a search
+type searchParams struct {
+	// query is the query string as entered, curation rules trigger on it
+	query    string
+	node     query.Node
+	mode     models.SearchMode
+	profile  *scoring.Profile
+	filters  *models.SearchFilters
+	page     models.PageRequest
+	collapse bool
+	explain  bool
+	// now is the time popularity scores are computed at, so every hit and
+	// its explanation see the same freshness
+	now time.Time
+}
+
+// relevanceRanked reports whether the results are ordered by descending
+// relevance, the only order curation pins and boosts apply to
+func (p *searchParams) relevanceRanked() bool {
+	return p.page.Sort == models.SortRelevance && p.page.Order == models.OrderDesc
+}
+
+// pageRequest resolves the sort and the page or cursor of a search
+func pageRequest(req *models.SearchRequest, hasQuery bool, page, limit int) (models.PageRequest, error) {
+	sortField, sortOrder, err := models.ParseSort(req.Sort, req.Order, hasQuery)
+	if err != nil {
+		return models.PageRequest{}, err
+	}
+
+	pageReq := models.PageRequest{Sort: sortField, Order: sortOrder, Page: page, Limit: limit}
+	if req.Cursor != "" {
+		cursor, err := models.DecodeCursor(req.Cursor, sortField, sortOrder)
+		if err != nil {
+			return models.PageRequest{}, err
+		}
+		pageReq.After = cursor
+		pageReq.Page = 0
+	}
+	return pageReq, nil
+}
+
+// parseQuery parses a query string and validates its field values
+func parseQuery(queryString string) (query.Node, error) {
+	node, err := query.Parse(queryString)
 	if err != nil {
 		return nil, err
 	}
 
+	query.Walk(node, func(n query.Node, negated bool) {
+		term, ok := n.(*query.TermNode)
+		if !ok || term.Field != query.FieldType || err != nil {
+			return
+		}
+		contentType := models.ContentType(strings.ToLower(term.Text))
+		if contentType != models.ContentTypeVideo && contentType != models.ContentTypeText {
+			err = query.Errorf(term.Position, "invalid content type %q, must be 'video' or 'text'", term.Text)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// searchContents ranks the index matches of the query by blended relevance
+// and popularity, falling back to a database scan if the index is unavailable
+func (ss *SearchService) searchContents(p *searchParams) (*models.SearchResult, error) {
+	if p.node == nil {
+		return ss.searchDatabase(p)
+	}
+
+	idx, err := ss.searchIndex()
+	if err != nil {
+		log.Printf("Search index unavailable, falling back to database search: %v", err)
+		return ss.searchDatabase(p)
+	}
+
+	hits, contents, explainer, err := ss.rankIndexMatches(idx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	// The contents are the full match set, so facet them before paginating
+	facets := ComputeFacets(contents, time.Now())
+	result := Paginate(hits, p.page, p.now)
+	result.Facets = facets
+	if err := ss.hydrateHits(result.Contents); err != nil {
+		return nil, err
+	}
+	if explainer != nil {
+		for i := range result.Contents {
+			explanation := explainer.explain(&result.Contents[i])
+			result.Contents[i].Explanation = &explanation
+		}
+	}
+	return result, nil
+}
+
+// rankIndexMatches returns every match of the query in rank order with their
+// contents, holding only the fields ranking reads until hydrateHits loads a
+// page. The mode selects keyword matches, nearest neighbours of the query
+// embedding or both fused by reciprocal rank. The curation rules triggered by
+// the query hide, boost and pin hits. Collapsing keeps the best hit of every
+// cluster of near duplicates. The explainer is only set when the search
+// explains its hits.
+func (ss *SearchService) rankIndexMatches(idx *index.Index, p *searchParams) ([]models.SearchHit, []models.Content, *explainer, error) {
+	var matches, keyword, semantic []index.Hit
+	switch p.mode {
+	case models.SearchModeVector:
+		semantic = ss.vectorMatches(p.node)
+		matches = semantic
+	case models.SearchModeHybrid:
+		keyword = idx.Execute(p.node, ss.indexOptions())
+		semantic = ss.vectorMatches(p.node)
+		matches = index.FuseRanks(ss.searchConfig.RRFK, keyword, semantic)
+	default:
+		keyword = idx.Execute(p.node, ss.indexOptions())
+		matches = keyword
+	}
+	ids := make([]uint, len(matches))
+	for i, match := range matches {
+		ids[i] = match.DocID
+	}
+
+	// Load the ranking fields of the matches passing the filters
+	contents, err := ss.contentRepo.FindRankFields(ids, p.filters)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	hits := ss.rankHits(matches, contents, p.profile, p.now)
+	// rankHits already orders by descending relevance
+	if p.page.Sort != models.SortRelevance || p.page.Order != models.OrderDesc {
+		sortHits(hits, p.page)
+	}
+
+	// Normalization saw every hit, so the explainer must too
+	plan := ss.curationService.Rules().Match(p.query, p.now)
+	var e *explainer
+	if p.explain {
+		e = ss.newExplainer(idx, p, hits, keyword, semantic)
+		e.plan = plan
+	}
+	maxRelevance, maxPopularity := maxScores(hits)
+
+	hits = curateHits(plan, hits, p.relevanceRanked())
+	ranked := hits
+	if p.collapse {
+		hits = collapseHits(hits)
+	}
+	if p.relevanceRanked() && len(plan.Pins) > 0 {
+		hits, err = ss.placePins(plan, hits, ranked, p, maxRelevance, maxPopularity)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if p.collapse || !plan.Empty() {
+		contents = hitContents(hits)
+	}
+	return hits, contents, e, nil
+}
+
+// searchDatabase runs the search against the database, ordered by stored
+// score. A collapsed search returns the canonical content of every cluster.
+// The contents hidden by the curation rules are left out, boosts and pins
+// need the relevance of index searches and do not apply.
+func (ss *SearchService) searchDatabase(p *searchParams) (*models.SearchResult, error) {
+	matching := p.filters
+	if hidden := ss.curationService.Rules().Match(p.query, p.now).HiddenIDs(); len(hidden) > 0 {
+		curated := *matching
+		curated.ExcludeIDs = hidden
+		matching = &curated
+	}
+	filters := matching
+	if p.collapse {
+		canonical := *filters
+		canonical.CanonicalOnly = true
+		filters = &canonical
+	}
+
+	result, err := ss.contentRepo.Search(p.node, filters, p.page)
+	if err != nil {
+		return nil, err
+	}
+	if p.collapse {
+		if err := ss.attachDuplicates(p.node, matching, result.Contents); err != nil {
+			return nil, err
+		}
+	}
+
+	// Key the cursors by the stored columns the database sorted by, before
+	// the scores are recalculated, relevance falls back to the stored score
+	keyField := p.page.Sort
+	if keyField == models.SortRelevance {
+		keyField = models.SortScore
+	}
+	setCursors(result, p.page, keyField, p.now)
+
 	// Calculate scores for all results
 	for i := range result.Contents {
-		ss.scoringService.CalculateScore(&result.Contents[i])
+		hit := &result.Contents[i]
+		ss.scoringService.CalculateScoreWith(p.profile, &hit.Content, p.now)
+		hit.PopularityScore = hit.FinalScore
+	}
+	ss.blendScores(result.Contents)
+	if p.explain {
+		e := ss.newExplainer(nil, p, result.Contents, nil, nil)
+		for i := range result.Contents {
+			explanation := e.explain(&result.Contents[i])
+			result.Contents[i].Explanation = &explanation
+		}
+	}
+
+	// Facet the full match set rather than the current page
+	facetFields, err := ss.contentRepo.FindFacetFields(p.node, filters)
+	if err != nil {
+		return nil, err
 	}
+	result.Facets = ComputeFacets(facetFields, time.Now())
 
 	return result, nil
 }
 
+// hydrateHits replaces the ranking fields of a page of hits with the full
+// rows, keeping the scores computed while ranking. A content deleted since it
+// was ranked keeps its ranking fields.
+func (ss *SearchService) hydrateHits(hits []models.SearchHit) error {
+	if len(hits) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(hits))
+	for i := range hits {
+		ids[i] = hits[i].ID
+	}
+	contents, err := ss.contentRepo.FindByIDs(ids, nil)
+	if err != nil {
+		return err
+	}
+	byID := make(map[uint]*models.Content, len(contents))
+	for i := range contents {
+		byID[contents[i].ID] = &contents[i]
+	}
+
+	for i := range hits {
+		content, ok := byID[hits[i].ID]
+		if !ok {
+			continue
+		}
+		ranked := &hits[i].Content
+		content.BaseScore = ranked.BaseScore
+		content.TypeMultiplier = ranked.TypeMultiplier
+		content.FreshnessScore = ranked.FreshnessScore
+		content.EngagementScore = ranked.EngagementScore
+		content.FinalScore = ranked.FinalScore
+		content.ScoringProfile = ranked.ScoringProfile
+		content.ScoringVersion = ranked.ScoringVersion
+		hits[i].Content = *content
+	}
+	return nil
+}
+
+// rankHits scores the contents with the profile and orders them by
+// blended score
+func (ss *SearchService) rankHits(matches []index.Hit, contents []models.Content, profile *scoring.Profile, now time.Time) []models.SearchHit {
+	relevance := make(map[uint]float64, len(matches))
+	for _, match := range matches {
+		relevance[match.DocID] = match.Score
+	}
+
+	hits := make([]models.SearchHit, len(contents))
+	for i := range contents {
+		ss.scoringService.CalculateScoreWith(profile, &contents[i], now)
+		hits[i] = models.SearchHit{
+			Content:         contents[i],
+			RelevanceScore:  relevance[contents[i].ID],
+			PopularityScore: contents[i].FinalScore,
+		}
+	}
+	ss.blendScores(hits)
+	sortByScore(hits)
+	return hits
+}
+
+// sortByScore orders hits by descending blended score, ties by ascending ID
+func sortByScore(hits []models.SearchHit) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+}
+
+// blendScores combines the relevance and popularity of each hit, both
+// normalized against the best hit, using the configured relevance weight
+func (ss *SearchService) blendScores(hits []models.SearchHit) {
+	maxRelevance, maxPopularity := maxScores(hits)
+	for i := range hits {
+		hits[i].Score = ss.blendScore(&hits[i], maxRelevance, maxPopularity)
+	}
+}
+
+// maxScores returns the best relevance and popularity of the hits
+func maxScores(hits []models.SearchHit) (maxRelevance, maxPopularity float64) {
+	for _, hit := range hits {
+		maxRelevance = math.Max(maxRelevance, hit.RelevanceScore)
+		maxPopularity = math.Max(maxPopularity, hit.PopularityScore)
+	}
+	return maxRelevance, maxPopularity
+}
+
+// blendScore combines the relevance and popularity of a hit normalized
+// against the given maximums
+func (ss *SearchService) blendScore(hit *models.SearchHit, maxRelevance, maxPopularity float64) float64 {
+	relevance, popularity := 0.0, 0.0
+	if maxRelevance > 0 {
+		relevance = hit.RelevanceScore / maxRelevance
+	}
+	if maxPopularity > 0 {
+		popularity = hit.PopularityScore / maxPopularity
+	}
+	weight := ss.searchConfig.RelevanceWeight
+	return weight*relevance + (1-weight)*popularity
+}
+
+// highlightHits adds the highlighted fragments of the query terms to the
+// hits, highlighting needs the index to resolve fuzzy matches
+func (ss *SearchService) highlightHits(node query.Node, hits []models.SearchHit, options models.HighlightOptions) {
+	idx, err := ss.searchIndex()
+	if err != nil {
+		return
+	}
+
+	highlighter := idx.Highlighter(node, ss.indexOptions())
+	for i := range hits {
+		hits[i].Highlights = highlighter.Highlight(&hits[i].Content, options)
+	}
+}
+
+// indexOptions returns the index query parameters from the search configuration
+func (ss *SearchService) indexOptions() index.Options {
+	return index.Options{
+		BM25: index.BM25Params{
+			K1:                ss.searchConfig.BM25K1,
+			B:                 ss.searchConfig.BM25B,
+			TitleWeight:       ss.searchConfig.TitleWeight,
+			DescriptionWeight: ss.searchConfig.DescriptionWeight,
+			TagsWeight:        ss.searchConfig.TagsWeight,
+		},
+		Fuzzy: index.FuzzyParams{
+			OneEditMinLength:  ss.searchConfig.FuzzyOneEditMinLength,
+			TwoEditsMinLength: ss.searchConfig.FuzzyTwoEditsMinLength,
+		},
+	}
+}
+
+// didYouMean rewrites the query with every misspelled term replaced by the
+// closest more frequent term of the indexed vocabulary
+func (ss *SearchService) didYouMean(queryString string, node query.Node) string {
+	idx, err := ss.searchIndex()
+	if err != nil {
+		return ""
+	}
+	fuzzy := ss.indexOptions().Fuzzy
+
+	type replacement struct {
+		start, end int
+		term       string
+	}
+	var replacements []replacement
+
+	query.Walk(node, func(n query.Node, negated bool) {
+		var text string
+		var offset int
+		switch n := n.(type) {
+		case *query.TermNode:
+			if n.Field == query.FieldType {
+				return
+			}
+			text, offset = n.Text, n.Position
+		case *query.PhraseNode:
+			// Skip the opening quote
+			text, offset = n.Text, n.Position+1
+		default:
+			return
+		}
+
+		for _, token := range analysis.Tokenize(text) {
+			if correction, ok := idx.Correct(token.Term, fuzzy); ok {
+				replacements = append(replacements, replacement{
+					start: offset + token.Start,
+					end:   offset + token.End,
+					term:  correction,
+				})
+			}
+		}
+	})
+
+	if len(replacements) == 0 {
+		return ""
+	}
+
+	// Apply the replacements from the end so earlier offsets stay valid
+	sort.Slice(replacements, func(i, j int) bool { return replacements[i].start > replacements[j].start })
+	suggestion := queryString
+	for _, r := range replacements {
+		suggestion = suggestion[:r.start] + r.term + suggestion[r.end:]
+	}
+	return suggestion
+}
+
 // GetContentByID retrieves a specific content by ID
 func (ss *SearchService) GetContentByID(id uint) (*models.Content, error) {
 	content, err := ss.contentRepo.FindByID(id)
@@ -87,26 +800,81 @@ func (ss *SearchService) GetPopularContent(limit int) ([]models.Content, error)
 	return contents, nil
 }
 
-// RefreshContent fetches fresh content from all providers and updates the database
-func (ss *SearchService) RefreshContent(ctx context.Context) error {
+// RefreshContent fetches fresh content from all providers and updates the
+// database with the content of the providers that succeeded. The report
+// holds the outcome of every provider, the error is set when every provider
+// failed or the content could not be stored.
+func (ss *SearchService) RefreshContent(ctx context.Context) (*providers.FetchReport, error) {
 	log.Println("Starting content refresh...")
 
 	// Fetch content from all providers
-	contents, err := ss.providerManager.FetchAllContent(ctx)
+	report, err := ss.providerManager.FetchAllContent(ctx)
 	if err != nil {
-		return err
+		return report, err
+	}
+	return report, ss.storeReport(report)
+}
+
+// RefreshDueContent fetches fresh content from the providers whose schedule
+// elapsed and updates the database
+func (ss *SearchService) RefreshDueContent(ctx context.Context) (*providers.FetchReport, error) {
+	report, err := ss.providerManager.FetchDueContent(ctx, time.Now())
+	if err != nil {
+		return report, err
 	}
+	return report, ss.storeReport(report)
+}
 
+// storeReport stores the contents of a fetch report, logging the providers
+// that failed
+func (ss *SearchService) storeReport(report *providers.FetchReport) error {
+	for _, result := range report.Results {
+		if result.Err != nil {
+			log.Printf("Provider %s failed: %v", result.Provider, result.Err)
+		}
+	}
+
+	contents := report.Contents()
+	if len(contents) == 0 {
+		return nil
+	}
+	return ss.storeContents(contents)
+}
+
+// storeContents scores, stores and indexes fetched contents. Only the fetched
+// contents are scored, the priors they shift for the rest of the corpus apply
+// on the next periodic rescore.
+func (ss *SearchService) storeContents(contents []models.Content) error {
 	log.Printf("Fetched %d content items from providers", len(contents))
 
-	// Calculate scores for all content
+	// Score with the engagement priors of the current corpus
+	if err := ss.RefreshPriors(); err != nil {
+		log.Printf("Failed to refresh engagement priors: %v", err)
+	}
+
+	// Calculate scores, embeddings and fingerprints for all content
 	ss.scoringService.CalculateScoresForBatch(contents)
+	ss.embedContents(contents)
+	fingerprintContents(contents)
 
 	// Bulk upsert to database
 	if err := ss.contentRepo.BulkUpsert(contents); err != nil {
 		return err
 	}
 
+	// Snapshot the engagement counters the upsert overwrote, for trending
+	ss.recordSnapshots(contents, time.Now())
+
+	// Group the stored contents into near-duplicate clusters
+	if clustered, err := ss.clusterDuplicates(); err != nil {
+		log.Printf("Failed to cluster duplicate content: %v", err)
+	} else {
+		log.Printf("Found %d near-duplicate content items", clustered)
+	}
+
+	// Keep the search index and completions in sync with the upserted rows
+	ss.syncContents(contents)
+
 	log.Printf("Successfully updated %d content items", len(contents))
 	return nil
 }
@@ -117,16 +885,28 @@ func (ss *SearchService) GetProviders() []map[string]interface{} {
 	var result []map[string]interface{}
 
 	for _, provider := range providers {
-		result = append(result, map[string]interface{}{
+		info := map[string]interface{}{
 			"name":    provider.GetName(),
 			"url":     provider.GetURL(),
 			"timeout": provider.GetTimeout().String(),
-		})
+		}
+		if instance, ok := ss.providerManager.GetInstance(provider.GetName()); ok {
+			info["kind"] = instance.Kind
+			info["content_type"] = instance.ContentType
+			info["schedule"] = time.Duration(instance.Schedule).String()
+		}
+		result = append(result, info)
 	}
 
 	return result
 }
 
+// GetProviderHealth returns the health of every provider and of all of them
+func (ss *SearchService) GetProviderHealth() (providers.HealthStatus, []providers.ProviderHealth) {
+	health := ss.providerManager.Health()
+	return providers.OverallHealth(health), health
+}
+
 // GetContentStats returns statistics about the content
 func (ss *SearchService) GetContentStats() (map[string]interface{}, error) {
 	// This is a simplified implementation
@@ -142,40 +922,27 @@ func (ss *SearchService) GetContentStats() (map[string]interface{}, error) {
 	return stats, nil
 }
 
-// SearchWithFilters performs a search with additional filters
-func (ss *SearchService) SearchWithFilters(query string, filters map[string]interface{}, page, limit int) (*models.SearchResult, error) {
-	// Extract content type from filters
-	contentType := models.ContentType("")
-	if typeStr, ok := filters["type"].(string); ok {
-		contentType = models.ContentType(typeStr)
-	}
-
-	// Perform basic search
-	result, err := ss.Search(query, contentType, page, limit)
-	if err != nil {
-		return nil, err
-	}
-
-	// Apply additional filters if needed
-	// This is where you could add more sophisticated filtering logic
-
-	return result, nil
-}
-
-// AutoRefresh starts a background goroutine to periodically refresh content
-func (ss *SearchService) AutoRefresh(ctx context.Context, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-
+// AutoRefresh refreshes every scheduled provider on its own schedule until
+// the context is done, starting with a refresh of all of them. It returns
+// right away when no provider is scheduled.
+func (ss *SearchService) AutoRefresh(ctx context.Context) {
 	for {
+		next, ok := ss.providerManager.NextDue()
+		if !ok {
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
 		select {
 		case <-ctx.Done():
+			timer.Stop()
 			log.Println("Auto refresh stopped")
 			return
-		case <-ticker.C:
-			if err := ss.RefreshContent(ctx); err != nil {
-				log.Printf("Auto refresh failed: %v", err)
-			}
+		case <-timer.C:
+		}
+
+		if _, err := ss.RefreshDueContent(ctx); err != nil {
+			log.Printf("Auto refresh failed: %v", err)
 		}
 	}
 } 
\ No newline at end of file
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"strings"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/embedding"
+	"search-engine-service/internal/index"
+	"search-engine-service/internal/query"
+)
+
+// embeddingText returns the text a content is embedded from
+func embeddingText(content *models.Content) string {
+	return content.Title + "\n" + content.Description + "\n" + content.Tags
+}
+
+// embedContents computes the embeddings of new or updated contents
+func (ss *SearchService) embedContents(contents []models.Content) {
+	for i := range contents {
+		contents[i].Embedding = ss.embedder.Embed(embeddingText(&contents[i]))
+		contents[i].EmbeddingModel = ss.embedder.Name()
+	}
+}
+
+// contentVector returns the stored embedding of a content, computing it when
+// it is missing or from another model, stale reports whether it was computed
+func (ss *SearchService) contentVector(content *models.Content) (vector []float32, stale bool) {
+	if content.EmbeddingModel == ss.embedder.Name() && len(content.Embedding) == ss.embedder.Dimensions() {
+		return content.Embedding, false
+	}
+
+	content.Embedding = ss.embedder.Embed(embeddingText(content))
+	content.EmbeddingModel = ss.embedder.Name()
+	return content.Embedding, true
+}
+
+// vectorMatches returns the contents nearest to the embedding of the query
+// text, most similar first
+func (ss *SearchService) vectorMatches(node query.Node) []index.Hit {
+	vector := ss.embedder.Embed(queryText(node))
+	if embedding.IsZero(vector) {
+		return nil
+	}
+
+	ss.indexMu.Lock()
+	vectors := ss.vectors
+	ss.indexMu.Unlock()
+
+	var hits []index.Hit
+	for _, result := range vectors.Search(vector, ss.searchConfig.VectorCandidates) {
+		if result.Similarity < ss.searchConfig.VectorMinSimilarity {
+			break
+		}
+		hits = append(hits, index.Hit{DocID: result.ID, Score: result.Similarity})
+	}
+	return hits
+}
+
+// queryText joins the words of the positive terms and phrases of a query,
+// the text a vector search embeds
+func queryText(node query.Node) string {
+	var words []string
+	query.Walk(node, func(n query.Node, negated bool) {
+		if negated {
+			return
+		}
+		switch n := n.(type) {
+		case *query.TermNode:
+			if n.Field != query.FieldType {
+				words = append(words, n.Text)
+			}
+		case *query.PhraseNode:
+			words = append(words, n.Text)
+		}
+	})
+	return strings.Join(words, " ")
+}
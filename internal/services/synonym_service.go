@@ -0,0 +1,96 @@
+package services
+
+import (
+	"time"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/database/repository"
+	"search-engine-service/internal/synonyms"
+
+	"gorm.io/gorm"
+)
+
+// SynonymService manages the synonym rules and keeps a compiled copy of them
+// for the query pipeline, reloaded when a rule changes or the copy expires
+type SynonymService struct {
+	repo  models.SynonymRepository
+	rules *reloader[*synonyms.Set]
+}
+
+// NewSynonymService creates a new synonym service
+func NewSynonymService(db *gorm.DB, reloadInterval time.Duration) *SynonymService {
+	s := &SynonymService{repo: repository.NewSynonymRepository(db)}
+	s.rules = newReloader("synonym rules", reloadInterval, func() (*synonyms.Set, error) {
+		rules, err := s.repo.FindAll()
+		if err != nil {
+			return nil, err
+		}
+		return synonyms.Compile(rules), nil
+	})
+	return s
+}
+
+// Rules returns the compiled rules, reloading them from the database once
+// the reload interval has passed so changes made by other instances apply
+// without a restart. A failed reload keeps serving the previous rules.
+func (s *SynonymService) Rules() *synonyms.Set {
+	return s.rules.Get()
+}
+
+// Reload compiles the rules currently stored in the database
+func (s *SynonymService) Reload() error {
+	return s.rules.Reload()
+}
+
+// List returns every synonym rule
+func (s *SynonymService) List() ([]models.SynonymRule, error) {
+	return s.repo.FindAll()
+}
+
+// Get returns a synonym rule by ID
+func (s *SynonymService) Get(id uint) (*models.SynonymRule, error) {
+	return s.repo.FindByID(id)
+}
+
+// Create validates and stores a new rule
+func (s *SynonymService) Create(rule *models.SynonymRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	rule.ID = 0
+	if err := s.repo.Create(rule); err != nil {
+		return err
+	}
+	s.rules.reloadAfterWrite()
+	return nil
+}
+
+// Update validates and replaces an existing rule
+func (s *SynonymService) Update(id uint, rule *models.SynonymRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	existing, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	rule.ID = existing.ID
+	rule.CreatedAt = existing.CreatedAt
+	if err := s.repo.Update(rule); err != nil {
+		return err
+	}
+	s.rules.reloadAfterWrite()
+	return nil
+}
+
+// Delete removes a rule
+func (s *SynonymService) Delete(id uint) error {
+	if _, err := s.repo.FindByID(id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	s.rules.reloadAfterWrite()
+	return nil
+}
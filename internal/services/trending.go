@@ -0,0 +1,143 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/trending"
+)
+
+// trendingWindow resolves the window a trending request ranks by
+func (ss *SearchService) trendingWindow(name string) (string, time.Duration, error) {
+	if name == "" {
+		name = trending.FormatWindow(ss.searchConfig.TrendingDefaultWindow)
+	}
+
+	names := make([]string, len(ss.searchConfig.TrendingWindows))
+	for i, window := range ss.searchConfig.TrendingWindows {
+		names[i] = trending.FormatWindow(window)
+	}
+	if window, err := trending.ParseWindow(name); err == nil {
+		for i, configured := range ss.searchConfig.TrendingWindows {
+			if configured == window {
+				return names[i], window, nil
+			}
+		}
+	}
+	return "", 0, &models.ParameterError{
+		Key:     "window",
+		Message: fmt.Sprintf("must be one of '%s'", strings.Join(names, "', '")),
+	}
+}
+
+// GetTrendingContent returns the contents whose engagement grew fastest
+// relative to their engagement at the start of the window, with their trend
+// in every configured window
+func (ss *SearchService) GetTrendingContent(req *models.TrendingRequest) (*models.TrendingResult, error) {
+	if err := req.Filters.Validate(); err != nil {
+		return nil, err
+	}
+	name, window, err := ss.trendingWindow(req.Window)
+	if err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	now := time.Now()
+	snapshots, err := ss.metricsRepo.FindSince(now.Add(-trending.Lookback*window), &req.Filters)
+	if err != nil {
+		return nil, err
+	}
+	trends := trending.Rank(snapshots, window, ss.searchConfig.TrendingSmoothing)
+	if len(trends) > limit {
+		trends = trends[:limit]
+	}
+
+	ids := make([]uint, len(trends))
+	for i, trend := range trends {
+		ids[i] = trend.ContentID
+	}
+	contents, err := ss.contentRepo.FindByIDs(ids, nil)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Content, len(contents))
+	for i := range contents {
+		byID[contents[i].ID] = &contents[i]
+	}
+	history, err := ss.metricsRepo.FindForContents(ids, now.Add(-trending.Lookback*ss.longestTrendingWindow()))
+	if err != nil {
+		return nil, err
+	}
+	windows := ss.trendsByWindow(history)
+
+	result := &models.TrendingResult{Window: name, Contents: make([]models.TrendingHit, 0, len(trends))}
+	for _, trend := range trends {
+		content, ok := byID[trend.ContentID]
+		if !ok {
+			continue
+		}
+		ss.scoringService.CalculateScoreAt(content, now)
+		result.Contents = append(result.Contents, models.TrendingHit{
+			Content: *content,
+			Trend:   windows[trend.ContentID],
+		})
+	}
+	return result, nil
+}
+
+// trendsByWindow computes the trend of every content of the snapshots,
+// ordered by content, in every configured window
+func (ss *SearchService) trendsByWindow(snapshots []models.MetricSnapshot) map[uint]map[string]models.TrendMetrics {
+	trends := make(map[uint]map[string]models.TrendMetrics)
+	for _, history := range trending.Histories(snapshots) {
+		byWindow := make(map[string]models.TrendMetrics, len(ss.searchConfig.TrendingWindows))
+		for _, window := range ss.searchConfig.TrendingWindows {
+			byWindow[trending.FormatWindow(window)] = trending.Compute(history, window, ss.searchConfig.TrendingSmoothing)
+		}
+		trends[history[0].ContentID] = byWindow
+	}
+	return trends
+}
+
+// longestTrendingWindow returns the longest configured trending window
+func (ss *SearchService) longestTrendingWindow() time.Duration {
+	var longest time.Duration
+	for _, window := range ss.searchConfig.TrendingWindows {
+		longest = max(longest, window)
+	}
+	return longest
+}
+
+// recordSnapshots stores the engagement counters of refreshed contents and
+// drops the snapshots too old for any trending window
+func (ss *SearchService) recordSnapshots(contents []models.Content, now time.Time) {
+	snapshots := make([]models.MetricSnapshot, len(contents))
+	for i := range contents {
+		snapshots[i] = models.MetricSnapshot{
+			ContentID:  contents[i].ID,
+			Views:      contents[i].Views,
+			Likes:      contents[i].Likes,
+			Reactions:  contents[i].Reactions,
+			CapturedAt: now,
+		}
+	}
+	if err := ss.metricsRepo.CreateBatch(snapshots); err != nil {
+		log.Printf("Failed to store %d metric snapshots: %v", len(snapshots), err)
+		return
+	}
+
+	if longest := ss.longestTrendingWindow(); longest > 0 {
+		if deleted, err := ss.metricsRepo.DeleteBefore(now.Add(-trending.Lookback * longest)); err != nil {
+			log.Printf("Failed to delete old metric snapshots: %v", err)
+		} else if deleted > 0 {
+			log.Printf("Deleted %d old metric snapshots", deleted)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Package suggest implements search-as-you-type completions over content
+// titles and tags using a prefix trie weighted by content scores
+package suggest
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MaxSuggestions is the largest number of completions returned for a prefix
+const MaxSuggestions = 20
+
+// Suggestion types
+const (
+	TypeTitle = "title"
+	TypeTag   = "tag"
+)
+
+// Document is the part of a content item completions are built from
+type Document struct {
+	ID    uint
+	Title string
+	Tags  string
+	Score float64
+}
+
+// Suggestion is a single completion
+type Suggestion struct {
+	Text  string  `json:"text"`
+	Type  string  `json:"type"`
+	Count int     `json:"count"`
+	Score float64 `json:"score"`
+}
+
+// entry is a distinct completion with its aggregated weight
+type entry struct {
+	Suggestion
+}
+
+type node struct {
+	children map[rune]*node
+	entries  []*entry
+	top      []*entry
+}
+
+// Trie is an immutable prefix trie answering completions in time
+// proportional to the prefix length
+type Trie struct {
+	root *node
+	size int
+}
+
+// Build creates a trie from the titles and tags of the documents, each
+// completion is weighted by the summed score of the documents it came from
+func Build(docs []Document) *Trie {
+	entries := make(map[string]*entry)
+	add := func(kind, text string, score float64) {
+		normalized := normalize(text)
+		if normalized == "" {
+			return
+		}
+		key := kind + "\x00" + normalized
+		e, ok := entries[key]
+		if !ok {
+			display := strings.TrimSpace(text)
+			if kind == TypeTag {
+				display = normalized
+			}
+			e = &entry{Suggestion{Text: display, Type: kind}}
+			entries[key] = e
+		}
+		e.Count++
+		e.Score += score
+	}
+
+	for _, doc := range docs {
+		add(TypeTitle, doc.Title, doc.Score)
+		for _, tag := range strings.Split(doc.Tags, ",") {
+			add(TypeTag, tag, doc.Score)
+		}
+	}
+
+	trie := &Trie{root: &node{}, size: len(entries)}
+	for _, e := range entries {
+		// Index every word start so "tutorial" completes "Go Tutorial"
+		normalized := normalize(e.Text)
+		for _, start := range wordStarts(normalized) {
+			trie.insert(normalized[start:], e)
+		}
+	}
+	trie.root.computeTop()
+
+	return trie
+}
+
+// Len returns the number of distinct completions
+func (t *Trie) Len() int {
+	return t.size
+}
+
+// Suggest returns up to limit completions for a prefix, best first
+func (t *Trie) Suggest(prefix string, limit int) []Suggestion {
+	if limit < 1 || limit > MaxSuggestions {
+		limit = MaxSuggestions
+	}
+
+	n := t.root
+	for _, r := range normalize(prefix) {
+		n = n.children[r]
+		if n == nil {
+			return []Suggestion{}
+		}
+	}
+
+	result := make([]Suggestion, 0, limit)
+	for _, e := range n.top {
+		if len(result) == limit {
+			break
+		}
+		result = append(result, e.Suggestion)
+	}
+	return result
+}
+
+// insert adds an entry under a key
+func (t *Trie) insert(key string, e *entry) {
+	n := t.root
+	for _, r := range key {
+		child, ok := n.children[r]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[rune]*node)
+			}
+			child = &node{}
+			n.children[r] = child
+		}
+		n = child
+	}
+	n.entries = append(n.entries, e)
+}
+
+// computeTop caches the best completions of every subtree
+func (n *node) computeTop() []*entry {
+	candidates := append([]*entry(nil), n.entries...)
+	for _, child := range n.children {
+		candidates = append(candidates, child.computeTop()...)
+	}
+
+	// The same entry can be reachable through several word starts
+	seen := make(map[*entry]bool, len(candidates))
+	unique := candidates[:0]
+	for _, e := range candidates {
+		if !seen[e] {
+			seen[e] = true
+			unique = append(unique, e)
+		}
+	}
+
+	sort.Slice(unique, func(i, j int) bool {
+		a, b := unique[i], unique[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Text < b.Text
+	})
+	if len(unique) > MaxSuggestions {
+		unique = unique[:MaxSuggestions]
+	}
+
+	n.top = append([]*entry(nil), unique...)
+	return n.top
+}
+
+// normalize lowercases text and collapses white space
+func normalize(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// wordStarts returns the byte offsets at which words begin
+func wordStarts(text string) []int {
+	var starts []int
+	previous := ' '
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		wasWord := unicode.IsLetter(previous) || unicode.IsDigit(previous)
+		if isWord && !wasWord {
+			starts = append(starts, i)
+		}
+		previous = r
+	}
+	return starts
+}
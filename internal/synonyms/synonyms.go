@@ -0,0 +1,301 @@
+// Package synonyms applies the synonym and rewrite rules to search queries.
+//
+// Rewrite rules replace a phrase of the raw query string before it is
+// parsed, equivalent and expansion rules widen the terms of the parsed
+// query to also match their synonyms.
+package synonyms
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/query"
+)
+
+// rewrite is a compiled rewrite rule
+type rewrite struct {
+	from        string
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// Set is an immutable compiled set of rules, safe for concurrent use
+type Set struct {
+	rewrites []rewrite
+	// expansions maps a normalized term or phrase to the alternatives it
+	// also matches and the type of the rule they come from
+	expansions map[string]expansion
+	// maxWords is the longest expansion key in words
+	maxWords int
+}
+
+// expansion is the alternatives of a term or phrase
+type expansion struct {
+	ruleType models.SynonymRuleType
+	to       []string
+}
+
+// Compile builds a rule set, invalid rules are skipped
+func Compile(rules []models.SynonymRule) *Set {
+	s := &Set{expansions: make(map[string]expansion)}
+
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Validate() != nil {
+			continue
+		}
+
+		switch rule.Type {
+		case models.SynonymEquivalent:
+			synonyms := rule.SynonymList()
+			for _, synonym := range synonyms {
+				var others []string
+				for _, other := range synonyms {
+					if other != synonym {
+						others = append(others, other)
+					}
+				}
+				s.addExpansion(synonym, models.SynonymEquivalent, others)
+			}
+		case models.SynonymExpansion:
+			s.addExpansion(rule.Input, models.SynonymExpansion, rule.SynonymList())
+		case models.SynonymRewrite:
+			from := normalize(rule.Input)
+			words := strings.Fields(regexp.QuoteMeta(from))
+			s.rewrites = append(s.rewrites, rewrite{
+				from:        from,
+				pattern:     regexp.MustCompile(`(?i)` + strings.Join(words, `\s+`)),
+				replacement: strings.Join(strings.Fields(rule.Replacement), " "),
+			})
+		}
+	}
+
+	// Longer phrases win over the words they contain
+	sort.SliceStable(s.rewrites, func(i, j int) bool {
+		return len(s.rewrites[i].from) > len(s.rewrites[j].from)
+	})
+	return s
+}
+
+// addExpansion merges alternatives into the expansion of a key
+func (s *Set) addExpansion(key string, ruleType models.SynonymRuleType, to []string) {
+	key = normalize(key)
+	current, ok := s.expansions[key]
+	if !ok {
+		current.ruleType = ruleType
+	}
+	for _, alternative := range to {
+		alternative = normalize(alternative)
+		if alternative != key && !contains(current.to, alternative) {
+			current.to = append(current.to, alternative)
+		}
+	}
+	if len(current.to) == 0 {
+		return
+	}
+
+	s.expansions[key] = current
+	if words := len(strings.Fields(key)); words > s.maxWords {
+		s.maxWords = words
+	}
+}
+
+// Len returns the number of compiled rewrites and expansion keys
+func (s *Set) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.rewrites) + len(s.expansions)
+}
+
+// Rewrite replaces every whole-word occurrence of the rewrite inputs in the
+// query string, case-insensitively, and reports the rules that applied
+func (s *Set) Rewrite(queryString string) (string, []models.QueryRewrite) {
+	if s == nil {
+		return queryString, nil
+	}
+
+	var applied []models.QueryRewrite
+	for _, rule := range s.rewrites {
+		rewritten, ok := replaceWords(queryString, rule.pattern, rule.replacement)
+		if !ok {
+			continue
+		}
+		queryString = rewritten
+		to := []string{}
+		if rule.replacement != "" {
+			to = append(to, rule.replacement)
+		}
+		applied = append(applied, models.QueryRewrite{Type: models.SynonymRewrite, From: rule.from, To: to})
+	}
+
+	if len(applied) > 0 {
+		queryString = strings.Join(strings.Fields(queryString), " ")
+	}
+	return queryString, applied
+}
+
+// replaceWords replaces the matches of the pattern that start and end on word
+// boundaries, reporting whether any was replaced
+func replaceWords(s string, pattern *regexp.Regexp, replacement string) (string, bool) {
+	var b strings.Builder
+	last, replaced := 0, false
+	for _, loc := range pattern.FindAllStringIndex(s, -1) {
+		if !wordBoundary(s, loc[0], loc[1]) {
+			continue
+		}
+		b.WriteString(s[last:loc[0]])
+		b.WriteString(replacement)
+		last, replaced = loc[1], true
+	}
+	if !replaced {
+		return s, false
+	}
+	b.WriteString(s[last:])
+	return b.String(), true
+}
+
+// wordBoundary reports whether s[start:end] is not part of a longer word
+func wordBoundary(s string, start, end int) bool {
+	if before, _ := utf8.DecodeLastRuneInString(s[:start]); start > 0 && isWordRune(before) {
+		return false
+	}
+	if after, _ := utf8.DecodeRuneInString(s[end:]); end < len(s) && isWordRune(after) {
+		return false
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Expand widens every term and phrase of the query with a synonym into an OR
+// of the original and its alternatives, runs of adjacent terms are matched
+// against multi-word synonyms, type: terms are never expanded
+func (s *Set) Expand(node query.Node) (query.Node, []models.QueryRewrite) {
+	if s == nil || len(s.expansions) == 0 || node == nil {
+		return node, nil
+	}
+
+	e := &expander{set: s, seen: make(map[string]bool)}
+	return e.expand(node), e.applied
+}
+
+// expander rewrites a query tree and collects the applied expansions
+type expander struct {
+	set     *Set
+	applied []models.QueryRewrite
+	seen    map[string]bool
+}
+
+func (e *expander) expand(node query.Node) query.Node {
+	switch n := node.(type) {
+	case *query.TermNode:
+		if n.Field == query.FieldType {
+			return n
+		}
+		return e.alternatives(n, n.Field, n.Text, n.Position)
+	case *query.PhraseNode:
+		return e.alternatives(n, n.Field, n.Text, n.Position)
+	case *query.AndNode:
+		return &query.AndNode{Children: e.expandRuns(n.Children), Position: n.Position}
+	case *query.OrNode:
+		children := make([]query.Node, len(n.Children))
+		for i, child := range n.Children {
+			children[i] = e.expand(child)
+		}
+		return &query.OrNode{Children: children, Position: n.Position}
+	case *query.NotNode:
+		return &query.NotNode{Child: e.expand(n.Child), Position: n.Position}
+	default:
+		return node
+	}
+}
+
+// expandRuns expands the children of an AND, preferring the longest run of
+// adjacent terms of the same field that matches a multi-word synonym
+func (e *expander) expandRuns(children []query.Node) []query.Node {
+	var result []query.Node
+	for i := 0; i < len(children); {
+		consumed := 0
+		for size := min(e.set.maxWords, len(children)-i); size > 1; size-- {
+			run, field, text, ok := termRun(children[i : i+size])
+			if !ok {
+				continue
+			}
+			if _, found := e.set.expansions[normalize(text)]; !found {
+				continue
+			}
+			original := &query.AndNode{Children: run, Position: run[0].Pos()}
+			result = append(result, e.alternatives(original, field, text, run[0].Pos()))
+			consumed = size
+			break
+		}
+
+		if consumed == 0 {
+			result = append(result, e.expand(children[i]))
+			consumed = 1
+		}
+		i += consumed
+	}
+	return result
+}
+
+// termRun joins a run of plain terms sharing a field
+func termRun(nodes []query.Node) ([]query.Node, string, string, bool) {
+	words := make([]string, len(nodes))
+	var field string
+	for i, node := range nodes {
+		term, ok := node.(*query.TermNode)
+		if !ok || term.Field == query.FieldType || (i > 0 && term.Field != field) {
+			return nil, "", "", false
+		}
+		field = term.Field
+		words[i] = term.Text
+	}
+	return nodes, field, strings.Join(words, " "), true
+}
+
+// alternatives returns the original node ORed with the synonyms of its text,
+// or the original node when it has none
+func (e *expander) alternatives(original query.Node, field, text string, position int) query.Node {
+	key := normalize(text)
+	exp, ok := e.set.expansions[key]
+	if !ok {
+		return original
+	}
+
+	children := []query.Node{original}
+	for _, alternative := range exp.to {
+		if strings.Contains(alternative, " ") {
+			children = append(children, &query.PhraseNode{Field: field, Text: alternative, Position: position})
+		} else {
+			children = append(children, &query.TermNode{Field: field, Text: alternative, Position: position})
+		}
+	}
+
+	if !e.seen[key] {
+		e.seen[key] = true
+		e.applied = append(e.applied, models.QueryRewrite{Type: exp.ruleType, From: key, To: exp.to})
+	}
+	return &query.OrNode{Children: children, Position: position}
+}
+
+// normalize lowercases a phrase and collapses its whitespace
+func normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
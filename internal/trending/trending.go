@@ -0,0 +1,153 @@
+// Package trending measures how fast the engagement of contents grows from
+// the metric snapshots taken at every refresh.
+//
+// The trend of a content over a window compares its latest snapshot with the
+// latest snapshot taken at least a window earlier. Velocity is the engagement
+// gained per hour, acceleration the change of velocity from the window
+// before, and the growth rate the gain relative to the starting engagement.
+package trending
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"search-engine-service/internal/database/models"
+)
+
+// Lookback is how many windows of history the trend of a window reads: the
+// window, the one before for the acceleration and one of slack for refreshes
+// that ran late
+const Lookback = 3
+
+// ParseWindow parses a window such as 30m, 24h or 7d
+func ParseWindow(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("invalid window %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	window, err := time.ParseDuration(s)
+	if err != nil || window <= 0 {
+		return 0, fmt.Errorf("invalid window %q", s)
+	}
+	return window, nil
+}
+
+// FormatWindow formats a window the way ParseWindow reads it, in whole days
+// past a day or in whole hours when possible
+func FormatWindow(window time.Duration) string {
+	day := 24 * time.Hour
+	switch {
+	case window > day && window%day == 0:
+		return fmt.Sprintf("%dd", window/day)
+	case window%time.Hour == 0:
+		return fmt.Sprintf("%dh", window/time.Hour)
+	default:
+		return window.String()
+	}
+}
+
+// Compute returns the trend of a content over a window from its snapshots
+// ordered by time. Smoothing is added to the starting engagement of the
+// growth rate so contents with little engagement cannot grow by orders of
+// magnitude. A content with a single snapshot has no trend.
+func Compute(snapshots []models.MetricSnapshot, window time.Duration, smoothing float64) models.TrendMetrics {
+	if len(snapshots) < 2 {
+		return models.TrendMetrics{}
+	}
+
+	end := len(snapshots) - 1
+	start := before(snapshots, end, window)
+	if start < 0 {
+		// Contents younger than the window trend over their whole history
+		start = 0
+	}
+
+	delta, velocity := gain(snapshots, start, end)
+	trend := models.TrendMetrics{
+		Delta:      delta,
+		Velocity:   velocity,
+		GrowthRate: delta / (snapshots[start].Engagement() + smoothing),
+	}
+
+	if previous := before(snapshots, start, window); previous >= 0 {
+		_, previousVelocity := gain(snapshots, previous, start)
+		trend.Acceleration = (velocity - previousVelocity) / window.Hours()
+	}
+	return trend
+}
+
+// before returns the index of the latest snapshot taken at least a window
+// before the snapshot at index i, -1 if there is none
+func before(snapshots []models.MetricSnapshot, i int, window time.Duration) int {
+	cutoff := snapshots[i].CapturedAt.Add(-window)
+	for j := i - 1; j >= 0; j-- {
+		if !snapshots[j].CapturedAt.After(cutoff) {
+			return j
+		}
+	}
+	return -1
+}
+
+// gain returns the engagement gained between two snapshots, in total and
+// per hour
+func gain(snapshots []models.MetricSnapshot, from, to int) (delta, perHour float64) {
+	delta = snapshots[to].Engagement() - snapshots[from].Engagement()
+	hours := snapshots[to].CapturedAt.Sub(snapshots[from].CapturedAt).Hours()
+	if hours <= 0 {
+		return delta, 0
+	}
+	return delta, delta / hours
+}
+
+// Histories splits snapshots ordered by content into the snapshots of each
+// content
+func Histories(snapshots []models.MetricSnapshot) [][]models.MetricSnapshot {
+	var histories [][]models.MetricSnapshot
+	for start := 0; start < len(snapshots); {
+		end := start
+		for end < len(snapshots) && snapshots[end].ContentID == snapshots[start].ContentID {
+			end++
+		}
+		histories = append(histories, snapshots[start:end])
+		start = end
+	}
+	return histories
+}
+
+// Trend is the trend of a content over a window
+type Trend struct {
+	ContentID uint
+	models.TrendMetrics
+}
+
+// Rank computes the trend of every content of the snapshots, ordered by
+// content, and returns the contents that gained engagement ordered by
+// descending growth rate, ties by velocity and ascending ID
+func Rank(snapshots []models.MetricSnapshot, window time.Duration, smoothing float64) []Trend {
+	var trends []Trend
+	for _, history := range Histories(snapshots) {
+		metrics := Compute(history, window, smoothing)
+		if metrics.Delta > 0 {
+			trends = append(trends, Trend{ContentID: history[0].ContentID, TrendMetrics: metrics})
+		}
+	}
+
+	sort.Slice(trends, func(i, j int) bool {
+		if trends[i].GrowthRate != trends[j].GrowthRate {
+			return trends[i].GrowthRate > trends[j].GrowthRate
+		}
+		if trends[i].Velocity != trends[j].Velocity {
+			return trends[i].Velocity > trends[j].Velocity
+		}
+		return trends[i].ContentID < trends[j].ContentID
+	})
+	return trends
+}
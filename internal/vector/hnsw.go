@@ -0,0 +1,333 @@
+// Package vector implements an in-process approximate nearest neighbour index
+// over unit-length vectors using a hierarchical navigable small world graph
+package vector
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// Params tunes the graph
+type Params struct {
+	// M is the number of links per node on the upper layers, the bottom
+	// layer keeps twice as many
+	M int
+	// EfConstruction is the candidate list size while inserting
+	EfConstruction int
+	// EfSearch is the candidate list size while searching
+	EfSearch int
+}
+
+// DefaultParams returns the default graph parameters
+func DefaultParams() Params {
+	return Params{M: 16, EfConstruction: 200, EfSearch: 64}
+}
+
+// Result is a nearest neighbour with its cosine similarity to the query
+type Result struct {
+	ID         uint
+	Similarity float64
+}
+
+// node is a vector in the graph, removed nodes stay in the graph as
+// waypoints but are never returned
+type node struct {
+	id      uint
+	vector  []float32
+	links   [][]int
+	deleted bool
+}
+
+// HNSW is a thread-safe hierarchical navigable small world graph. Vectors
+// must be normalized, similarity is their dot product.
+type HNSW struct {
+	mu     sync.RWMutex
+	params Params
+
+	nodes    []*node
+	ids      map[uint]int
+	entry    int
+	maxLevel int
+
+	levelMult float64
+	rng       *rand.Rand
+}
+
+// New creates an empty graph
+func New(params Params) *HNSW {
+	defaults := DefaultParams()
+	if params.M < 2 {
+		params.M = defaults.M
+	}
+	if params.EfConstruction < params.M {
+		params.EfConstruction = defaults.EfConstruction
+	}
+	if params.EfSearch < 1 {
+		params.EfSearch = defaults.EfSearch
+	}
+
+	return &HNSW{
+		params:    params,
+		ids:       make(map[uint]int),
+		entry:     -1,
+		levelMult: 1 / math.Log(float64(params.M)),
+		// A fixed seed keeps the graph of a given insertion order reproducible
+		rng: rand.New(rand.NewSource(1)),
+	}
+}
+
+// Len returns the number of vectors that can be returned
+func (h *HNSW) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.ids)
+}
+
+// Add inserts a vector, replacing any previous vector of the ID
+func (h *HNSW) Add(id uint, vector []float32) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(id)
+	h.insert(id, vector)
+}
+
+// Remove deletes the vector of an ID
+func (h *HNSW) Remove(id uint) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(id)
+}
+
+// Search returns up to k vectors most similar to the query, most similar first
+func (h *HNSW) Search(query []float32, k int) []Result {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.entry < 0 || k < 1 {
+		return nil
+	}
+
+	entry := h.entry
+	for level := h.maxLevel; level > 0; level-- {
+		entry = h.greedy(query, entry, level)
+	}
+
+	ef := h.params.EfSearch
+	if k > ef {
+		ef = k
+	}
+	var results []Result
+	for _, c := range h.searchLayer(query, entry, ef, 0) {
+		if n := h.nodes[c.node]; !n.deleted {
+			results = append(results, Result{ID: n.id, Similarity: c.similarity})
+		}
+	}
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+// remove marks the node of an ID deleted, the caller must hold the write lock
+func (h *HNSW) remove(id uint) {
+	if i, ok := h.ids[id]; ok {
+		h.nodes[i].deleted = true
+		delete(h.ids, id)
+	}
+}
+
+// insert adds a node, the caller must hold the write lock
+func (h *HNSW) insert(id uint, vector []float32) {
+	level := int(-math.Log(1-h.rng.Float64()) * h.levelMult)
+	n := &node{id: id, vector: vector, links: make([][]int, level+1)}
+	index := len(h.nodes)
+	h.nodes = append(h.nodes, n)
+	h.ids[id] = index
+
+	if h.entry < 0 {
+		h.entry, h.maxLevel = index, level
+		return
+	}
+
+	entry := h.entry
+	for l := h.maxLevel; l > level; l-- {
+		entry = h.greedy(vector, entry, l)
+	}
+
+	for l := min(level, h.maxLevel); l >= 0; l-- {
+		candidates := h.searchLayer(vector, entry, h.params.EfConstruction, l)
+		n.links[l] = h.selectNeighbors(candidates, h.maxLinks(l))
+
+		for _, neighbor := range n.links[l] {
+			h.link(neighbor, index, l)
+		}
+		entry = candidates[0].node
+	}
+
+	if level > h.maxLevel {
+		h.entry, h.maxLevel = index, level
+	}
+}
+
+// link adds a link from a node, pruning its links when over capacity
+func (h *HNSW) link(from, to, level int) {
+	n := h.nodes[from]
+	n.links[level] = append(n.links[level], to)
+	if len(n.links[level]) <= h.maxLinks(level) {
+		return
+	}
+
+	candidates := make([]candidate, len(n.links[level]))
+	for i, link := range n.links[level] {
+		candidates[i] = candidate{node: link, similarity: dot(n.vector, h.nodes[link].vector)}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].similarity > candidates[j].similarity })
+	n.links[level] = h.selectNeighbors(candidates, h.maxLinks(level))
+}
+
+// maxLinks returns the link capacity of a layer
+func (h *HNSW) maxLinks(level int) int {
+	if level == 0 {
+		return 2 * h.params.M
+	}
+	return h.params.M
+}
+
+// selectNeighbors picks up to m of the candidates, sorted by descending
+// similarity, skipping those closer to an already picked neighbour than to
+// the base so links spread in all directions. Skipped candidates fill any
+// remaining room.
+func (h *HNSW) selectNeighbors(candidates []candidate, m int) []int {
+	selected := make([]int, 0, m)
+	var skipped []int
+	for _, c := range candidates {
+		if len(selected) == m {
+			break
+		}
+		diverse := true
+		for _, s := range selected {
+			if dot(h.nodes[c.node].vector, h.nodes[s].vector) > c.similarity {
+				diverse = false
+				break
+			}
+		}
+		if diverse {
+			selected = append(selected, c.node)
+		} else {
+			skipped = append(skipped, c.node)
+		}
+	}
+
+	for _, s := range skipped {
+		if len(selected) == m {
+			break
+		}
+		selected = append(selected, s)
+	}
+	return selected
+}
+
+// greedy walks a layer towards the query and returns the closest node found
+func (h *HNSW) greedy(query []float32, entry, level int) int {
+	best := dot(query, h.nodes[entry].vector)
+	for changed := true; changed; {
+		changed = false
+		for _, link := range h.nodes[entry].links[level] {
+			if similarity := dot(query, h.nodes[link].vector); similarity > best {
+				entry, best, changed = link, similarity, true
+			}
+		}
+	}
+	return entry
+}
+
+// searchLayer returns up to ef nodes of a layer closest to the query, most
+// similar first
+func (h *HNSW) searchLayer(query []float32, entry, ef, level int) []candidate {
+	start := candidate{node: entry, similarity: dot(query, h.nodes[entry].vector)}
+	visited := map[int]bool{entry: true}
+	frontier := &maxHeap{start}
+	results := &minHeap{start}
+
+	for frontier.Len() > 0 {
+		current := heap.Pop(frontier).(candidate)
+		if results.Len() >= ef && current.similarity < (*results)[0].similarity {
+			break
+		}
+
+		for _, link := range h.nodes[current.node].links[level] {
+			if visited[link] {
+				continue
+			}
+			visited[link] = true
+
+			c := candidate{node: link, similarity: dot(query, h.nodes[link].vector)}
+			if results.Len() < ef || c.similarity > (*results)[0].similarity {
+				heap.Push(frontier, c)
+				heap.Push(results, c)
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	sorted := make([]candidate, results.Len())
+	for i := len(sorted) - 1; i >= 0; i-- {
+		sorted[i] = heap.Pop(results).(candidate)
+	}
+	return sorted
+}
+
+// dot returns the dot product of two vectors, their cosine similarity when
+// both are normalized
+func dot(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+// candidate is a node with its similarity to the current query
+type candidate struct {
+	node       int
+	similarity float64
+}
+
+// maxHeap pops the most similar candidate first
+type maxHeap []candidate
+
+func (h maxHeap) Len() int            { return len(h) }
+func (h maxHeap) Less(i, j int) bool  { return h[i].similarity > h[j].similarity }
+func (h maxHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *maxHeap) Push(x interface{}) { *h = append(*h, x.(candidate)) }
+func (h *maxHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+// minHeap pops the least similar candidate first
+type minHeap []candidate
+
+func (h minHeap) Len() int            { return len(h) }
+func (h minHeap) Less(i, j int) bool  { return h[i].similarity < h[j].similarity }
+func (h minHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *minHeap) Push(x interface{}) { *h = append(*h, x.(candidate)) }
+func (h *minHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
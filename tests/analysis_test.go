@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"search-engine-service/internal/analysis"
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/index"
+	"search-engine-service/internal/query"
+)
+
+func TestStandardAnalyzer(t *testing.T) {
+	tokens := analysis.Standard.Analyze("Go, Gin & REST-APIs")
+
+	expected := []analysis.Token{
+		{Term: "go", Position: 0, Start: 0, End: 2},
+		{Term: "gin", Position: 1, Start: 4, End: 7},
+		{Term: "rest", Position: 2, Start: 10, End: 14},
+		{Term: "apis", Position: 3, Start: 15, End: 19},
+	}
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("Analyze() = %+v, expected %+v", tokens, expected)
+	}
+}
+
+func TestEnglishAnalyzer(t *testing.T) {
+	// Stop words are dropped but keep their positions
+	tokens := analysis.English.Analyze("Running the Café")
+	expected := []analysis.Token{
+		{Term: "run", Position: 0, Start: 0, End: 7},
+		{Term: "cafe", Position: 2, Start: 12, End: 17},
+	}
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("Analyze() = %+v, expected %+v", tokens, expected)
+	}
+
+	stems := map[string]string{
+		"caresses":       "caress",
+		"ponies":         "poni",
+		"agreed":         "agre",
+		"hopping":        "hop",
+		"filing":         "file",
+		"relational":     "relat",
+		"generalization": "gener",
+		"runs":           "run",
+		"patterns":       "pattern",
+	}
+	for word, stem := range stems {
+		if got := analysis.PorterStem(word); got != stem {
+			t.Errorf("PorterStem(%q) = %q, expected %q", word, got, stem)
+		}
+	}
+}
+
+func TestTurkishAnalyzer(t *testing.T) {
+	// Dotted and dotless i follow the Turkish casing rules before folding
+	terms := []string{}
+	for _, token := range analysis.Turkish.Analyze("İstanbul ve IŞIK kitaplarından") {
+		terms = append(terms, token.Term)
+	}
+	expected := []string{"istanbul", "isik", "kitap"}
+	if !reflect.DeepEqual(terms, expected) {
+		t.Errorf("Analyze() terms = %q, expected %q", terms, expected)
+	}
+
+	stems := map[string]string{
+		"kitap":    "kitap",
+		"kitaplar": "kitap",
+		"evde":     "ev",
+		"evlere":   "ev",
+		"araba":    "araba",
+		"arabaya":  "araba",
+		"dilinin":  "dil",
+		"dili":     "dil",
+	}
+	for word, stem := range stems {
+		if got := analysis.TurkishStem(word); got != stem {
+			t.Errorf("TurkishStem(%q) = %q, expected %q", word, got, stem)
+		}
+	}
+}
+
+func TestAnalyzerFor(t *testing.T) {
+	cases := map[string]*analysis.Analyzer{
+		"en":    analysis.English,
+		"tr-TR": analysis.Turkish,
+		"TR":    analysis.Turkish,
+		"de":    analysis.Standard,
+		"":      analysis.Standard,
+	}
+	for language, expected := range cases {
+		if got := analysis.For(language); got != expected {
+			t.Errorf("For(%q) = %s, expected %s", language, got.Name(), expected.Name())
+		}
+	}
+}
+
+func TestIndexAnalyzesPerLanguage(t *testing.T) {
+	idx := index.New()
+	idx.AddBatch([]models.Content{
+		{ID: 1, Language: "en", Title: "Running Go services", Tags: "golang"},
+		{ID: 2, Language: "tr", Title: "İstanbul'da Go geliştiricileri", Tags: "golang"},
+		{ID: 3, Language: "en", Title: "The state of the art", Tags: "research"},
+	})
+
+	tests := []struct {
+		query    string
+		expected []uint
+	}{
+		{"run", []uint{1}},
+		{"services", []uint{1}},
+		{"istanbul", []uint{2}},
+		{"ISTANBUL", []uint{2}},
+		{"geliştiriciler", []uint{2}},
+		{`"state of the art"`, []uint{3}},
+		{`"state art"`, nil},
+	}
+	for _, tc := range tests {
+		node, err := query.Parse(tc.query)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tc.query, err)
+		}
+		var ids []uint
+		for _, hit := range idx.Execute(node, index.DefaultOptions()) {
+			ids = append(ids, hit.DocID)
+		}
+		if !reflect.DeepEqual(ids, tc.expected) {
+			t.Errorf("Execute(%q) = %v, expected %v", tc.query, ids, tc.expected)
+		}
+	}
+
+	// Corrections are reported with the indexed spelling, not the stem
+	if correction, ok := idx.Correct("servises", index.DefaultFuzzyParams()); !ok || correction != "services" {
+		t.Errorf("Correct(servises) = %q, %v, expected services", correction, ok)
+	}
+}
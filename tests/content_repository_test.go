@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/database/repository"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// dryRunQueries returns a database that builds queries without running them
+// and the SQL of the queries built so far
+func dryRunQueries(t *testing.T) (*gorm.DB, func() []string) {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "root@tcp(127.0.0.1:1)/search_engine",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: gormlogger.Discard})
+	if err != nil {
+		t.Fatalf("Failed to open the database: %v", err)
+	}
+
+	var statements []string
+	db.Callback().Query().After("gorm:query").Register("tests:record", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	})
+	return db, func() []string { return statements }
+}
+
+func TestContentRepositoryFindByIDs(t *testing.T) {
+	db, statements := dryRunQueries(t)
+	repo := repository.NewContentRepository(db)
+
+	ids := make([]uint, 2500)
+	for i := range ids {
+		ids[i] = uint(i + 1)
+	}
+	if _, err := repo.FindByIDs(ids, &models.SearchFilters{Language: "en"}); err != nil {
+		t.Fatalf("FindByIDs failed: %v", err)
+	}
+
+	// The IDs are bound in batches, each filtered once
+	queries := statements()
+	if len(queries) != 3 {
+		t.Fatalf("Expected 3 batched queries, got %d", len(queries))
+	}
+	for _, sql := range queries {
+		if strings.Count(sql, "id IN") != 1 || strings.Count(sql, "language = ?") != 1 {
+			t.Errorf("Expected a single IN list and filter, got %s", sql)
+		}
+		if strings.Contains(sql, "embedding") || !strings.Contains(sql, "description") {
+			t.Errorf("Expected every column but the embedding, got %s", sql)
+		}
+	}
+	if strings.Count(queries[2], "?") != 501 {
+		t.Errorf("Expected the last batch to bind 500 IDs, got %s", queries[2])
+	}
+}
+
+func TestContentRepositoryExcludeIDs(t *testing.T) {
+	db, statements := dryRunQueries(t)
+	repo := repository.NewContentRepository(db)
+
+	// Contents hidden by curation are left out of the search, its count and facets
+	filters := &models.SearchFilters{ExcludeIDs: []uint{4, 9}}
+	page := models.PageRequest{Sort: models.SortScore, Order: models.OrderDesc, Page: 1, Limit: 10}
+	if _, err := repo.Search(nil, filters, page); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if _, err := repo.FindFacetFields(nil, filters); err != nil {
+		t.Fatalf("FindFacetFields failed: %v", err)
+	}
+
+	queries := statements()
+	if len(queries) < 2 {
+		t.Fatalf("Expected the search and facet queries, got %d", len(queries))
+	}
+	for _, sql := range queries {
+		if !strings.Contains(sql, "id NOT IN (?,?)") {
+			t.Errorf("Expected the hidden contents to be excluded, got %s", sql)
+		}
+	}
+}
+
+func TestContentRepositoryFindRankFields(t *testing.T) {
+	db, statements := dryRunQueries(t)
+	repo := repository.NewContentRepository(db)
+
+	if _, err := repo.FindRankFields([]uint{1, 2, 3}, nil); err != nil {
+		t.Fatalf("FindRankFields failed: %v", err)
+	}
+	if contents, err := repo.FindRankFields(nil, nil); err != nil || len(contents) != 0 {
+		t.Errorf("Expected no query without IDs, got %v, %v", contents, err)
+	}
+
+	queries := statements()
+	if len(queries) != 1 {
+		t.Fatalf("Expected 1 query, got %d", len(queries))
+	}
+	for _, column := range []string{"description", "embedding", "title"} {
+		if strings.Contains(queries[0], column) {
+			t.Errorf("Expected the ranking fields only, got %s", queries[0])
+		}
+	}
+	if !strings.Contains(queries[0], "cluster_id") || !strings.Contains(queries[0], "published_at") {
+		t.Errorf("Expected the ranking fields, got %s", queries[0])
+	}
+}
@@ -0,0 +1,162 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"search-engine-service/internal/curation"
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/services"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func testCurationRules(now time.Time) *curation.Set {
+	yesterday, tomorrow := now.AddDate(0, 0, -1), now.AddDate(0, 0, 1)
+	return curation.Compile([]models.CurationRule{
+		{ID: 1, Match: models.CurationExact, Query: "Go Tutorial", Pins: models.CurationPins{{ID: 7, Position: 1}}},
+		{ID: 2, Match: models.CurationPattern, Query: `^go\b`, Boosts: models.CurationBoosts{
+			{Target: models.BoostTargetProvider, Value: "json_provider", Factor: 2},
+			{Target: models.BoostTargetTag, Value: "spam", Factor: 0.1},
+		}},
+		{ID: 3, Match: models.CurationPattern, Query: "tutorial", Hidden: models.IDList{9}, Pins: models.CurationPins{{ID: 7, Position: 3}}},
+		// Scheduled rules only apply between their dates
+		{ID: 4, Match: models.CurationExact, Query: "go tutorial", Hidden: models.IDList{5}, StartsAt: &tomorrow},
+		{ID: 5, Match: models.CurationExact, Query: "go tutorial", Hidden: models.IDList{6}, EndsAt: &yesterday},
+		// Invalid rules are skipped
+		{ID: 6, Match: models.CurationPattern, Query: "(go", Hidden: models.IDList{1}},
+		{ID: 7, Match: models.CurationExact, Query: "go"},
+	})
+}
+
+func TestCurationMatch(t *testing.T) {
+	now := time.Now()
+	rules := testCurationRules(now)
+	if rules.Len() != 5 {
+		t.Errorf("Expected 5 valid rules, got %d", rules.Len())
+	}
+
+	tests := []struct {
+		query string
+		rules []uint
+	}{
+		{"go tutorial", []uint{1, 2, 3}},
+		{"  GO   Tutorial ", []uint{1, 2, 3}},
+		{"go tutorials", []uint{2, 3}},
+		{"golang", nil},
+		{"rust tutorial", []uint{3}},
+		{"", nil},
+	}
+	for _, test := range tests {
+		plan := rules.Match(test.query, now)
+		if !reflect.DeepEqual(plan.Rules, test.rules) {
+			t.Errorf("Match(%q) = %v, expected %v", test.query, plan.Rules, test.rules)
+		}
+	}
+
+	// The first rule pinning a content wins
+	plan := rules.Match("go tutorial", now)
+	if pin, ok := plan.Pinned(7); !ok || pin.Position != 1 || pin.Rule != 1 {
+		t.Errorf("Expected content 7 pinned first by rule 1, got %+v", pin)
+	}
+	if rule, ok := plan.Hidden(9); !ok || rule != 3 {
+		t.Errorf("Expected content 9 hidden by rule 3, got %d", rule)
+	}
+	for _, id := range []uint{5, 6} {
+		if _, ok := plan.Hidden(id); ok {
+			t.Errorf("Expected the rule hiding content %d to be inactive", id)
+		}
+	}
+	if hidden := plan.HiddenIDs(); !reflect.DeepEqual(hidden, []uint{9}) {
+		t.Errorf("Expected content 9 hidden, got %v", hidden)
+	}
+
+	content := &models.Content{ID: 3, Provider: "json_provider", Tags: "golang, Spam"}
+	if factor := curation.Factor(plan.Boosts(content)); factor != 0.2 {
+		t.Errorf("Expected a combined factor of 0.2, got %f", factor)
+	}
+	if boosts := plan.Boosts(&models.Content{ID: 4, Provider: "xml_provider"}); len(boosts) != 0 {
+		t.Errorf("Expected no boosts, got %+v", boosts)
+	}
+}
+
+func TestCurationPlace(t *testing.T) {
+	plan := curation.Compile([]models.CurationRule{
+		{ID: 1, Match: models.CurationExact, Query: "go", Pins: models.CurationPins{{ID: 4, Position: 1}, {ID: 8, Position: 2}, {ID: 9, Position: 1}}},
+		{ID: 2, Match: models.CurationExact, Query: "go", Pins: models.CurationPins{{ID: 5, Position: 10}, {ID: 6, Position: 2}}, Hidden: models.IDList{6}},
+	}).Match("go", time.Now())
+
+	hits := []models.SearchHit{{Content: models.Content{ID: 1}}, {Content: models.Content{ID: 2}}, {Content: models.Content{ID: 3}}, {Content: models.Content{ID: 4}}}
+	extra := []models.SearchHit{{Content: models.Content{ID: 5}}, {Content: models.Content{ID: 9}}, {Content: models.Content{ID: 6}}}
+
+	// 8 is neither matched nor loaded and 6 is hidden, 9 follows 4 at its
+	// position and 5 goes last
+	placed := plan.Place(hits, extra)
+	var ids []uint
+	for _, hit := range placed {
+		ids = append(ids, hit.ID)
+		if pinned := hit.ID == 4 || hit.ID == 5 || hit.ID == 9; hit.Pinned != pinned {
+			t.Errorf("Expected content %d pinned %v", hit.ID, pinned)
+		}
+	}
+	if expected := []uint{4, 9, 1, 2, 3, 5}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected %v, got %v", expected, ids)
+	}
+}
+
+func TestCurationRuleValidate(t *testing.T) {
+	now := time.Now()
+	before := now.Add(-time.Hour)
+
+	tests := []struct {
+		rule models.CurationRule
+		key  string
+	}{
+		{models.CurationRule{Match: "fuzzy", Query: "go", Hidden: models.IDList{1}}, "match"},
+		{models.CurationRule{Match: models.CurationExact, Query: "  ", Hidden: models.IDList{1}}, "query"},
+		{models.CurationRule{Match: models.CurationPattern, Query: "[go", Hidden: models.IDList{1}}, "query"},
+		{models.CurationRule{Match: models.CurationExact, Query: "go"}, "pins"},
+		{models.CurationRule{Match: models.CurationExact, Query: "go", Pins: models.CurationPins{{ID: 1}}}, "pins"},
+		{models.CurationRule{Match: models.CurationExact, Query: "go", Boosts: models.CurationBoosts{{Target: models.BoostTargetID, Value: "abc", Factor: 2}}}, "boosts"},
+		{models.CurationRule{Match: models.CurationExact, Query: "go", Boosts: models.CurationBoosts{{Target: "language", Value: "en", Factor: 2}}}, "boosts"},
+		{models.CurationRule{Match: models.CurationExact, Query: "go", Boosts: models.CurationBoosts{{Target: models.BoostTargetTag, Value: "go", Factor: 0}}}, "boosts"},
+		{models.CurationRule{Match: models.CurationExact, Query: "go", Hidden: models.IDList{0}}, "hidden"},
+		{models.CurationRule{Match: models.CurationExact, Query: "go", Hidden: models.IDList{1}, StartsAt: &now, EndsAt: &before}, "ends_at"},
+		{models.CurationRule{Match: models.CurationPattern, Query: "go|rust", Boosts: models.CurationBoosts{{Target: models.BoostTargetID, Value: "12", Factor: 0.5}}}, ""},
+	}
+	for _, test := range tests {
+		err := test.rule.Validate()
+		if test.key == "" {
+			if err != nil {
+				t.Errorf("Expected %+v to be valid, got %v", test.rule, err)
+			}
+			continue
+		}
+		paramErr, ok := err.(*models.ParameterError)
+		if !ok || paramErr.Key != test.key {
+			t.Errorf("Expected a %q error for %+v, got %v", test.key, test.rule, err)
+		}
+	}
+}
+
+func TestCurationRulesUnavailable(t *testing.T) {
+	// A database that cannot be reached fails the first load of the rules
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "root@tcp(127.0.0.1:1)/search_engine?timeout=1s",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DisableAutomaticPing: true, Logger: gormlogger.Discard})
+	if err != nil {
+		t.Fatalf("Failed to open the database: %v", err)
+	}
+
+	rules := services.NewCurationService(db, time.Minute).Rules()
+	if rules.Len() != 0 {
+		t.Errorf("Expected no rules, got %d", rules.Len())
+	}
+	if plan := rules.Match("go tutorial", time.Now()); !plan.Empty() {
+		t.Errorf("Expected an empty plan, got rules %v", plan.Rules)
+	}
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"math/rand"
+	"testing"
+
+	"search-engine-service/internal/config"
+	"search-engine-service/internal/dedup"
+)
+
+func TestFingerprint(t *testing.T) {
+	maxDistance := config.DefaultSearchConfig().DuplicateMaxDistance
+
+	original := dedup.Fingerprint("Understanding Go Modules and Dependency Management\nA comprehensive guide to Go modules, dependency management, and best practices for modern Go development.")
+	if original == 0 {
+		t.Fatal("Expected a non-zero fingerprint")
+	}
+
+	republished := dedup.Fingerprint("Understanding Go Modules and Dependency Management\nA comprehensive guide to Go modules, dependency management and best practices for modern Go development!")
+	if d := dedup.Distance(original, republished); d != 0 {
+		t.Errorf("Expected punctuation and case to be ignored, got distance %d", d)
+	}
+
+	edited := dedup.Fingerprint("Understanding Go Modules & Dependency Management\nA comprehensive guide to Go modules, dependency management, and the best practices for modern Go development.")
+	if d := dedup.Distance(original, edited); d > maxDistance {
+		t.Errorf("Expected a lightly edited copy within %d bits, got %d", maxDistance, d)
+	}
+
+	other := dedup.Fingerprint("Microservices Architecture with Go\nLearn how to design and implement microservices using Go, including service discovery and communication patterns.")
+	if d := dedup.Distance(original, other); d <= maxDistance {
+		t.Errorf("Expected a different story beyond %d bits, got %d", maxDistance, d)
+	}
+
+	if dedup.Fingerprint(" - ") != 0 {
+		t.Error("Expected a zero fingerprint for text without words")
+	}
+}
+
+func TestCluster(t *testing.T) {
+	items := []dedup.Item{
+		{ID: 5, Fingerprint: 0xF0F0},
+		{ID: 2, Fingerprint: 0xF0F1},             // 1 bit from 5
+		{ID: 9, Fingerprint: 0xF0F1 | 0x3<<32},   // 2 bits from 2, 3 from 5
+		{ID: 3, Fingerprint: 0xFFFFFFFF00000000}, // unrelated
+		{ID: 4, Fingerprint: 0},                  // never grouped
+		{ID: 7, Fingerprint: 0},
+	}
+
+	canonical := dedup.Cluster(items, 2)
+	expected := map[uint]uint{5: 5, 2: 5, 9: 5, 3: 3, 4: 4, 7: 7}
+	for id, want := range expected {
+		if canonical[id] != want {
+			t.Errorf("Expected %d to belong to cluster %d, got %d", id, want, canonical[id])
+		}
+	}
+}
+
+func TestClusterMatchesPairwise(t *testing.T) {
+	const maxDistance = 4
+	rng := rand.New(rand.NewSource(7))
+
+	// Random fingerprints with a few flipped-bit copies
+	var items []dedup.Item
+	for i := 0; i < 200; i++ {
+		fingerprint := rng.Uint64()
+		items = append(items, dedup.Item{ID: uint(len(items) + 1), Fingerprint: fingerprint})
+		if i%4 == 0 {
+			copied := fingerprint
+			for flips := rng.Intn(maxDistance + 1); flips > 0; flips-- {
+				copied ^= 1 << rng.Intn(64)
+			}
+			items = append(items, dedup.Item{ID: uint(len(items) + 1), Fingerprint: copied})
+		}
+	}
+
+	canonical := dedup.Cluster(items, maxDistance)
+	for i := range items {
+		for j := i + 1; j < len(items); j++ {
+			if dedup.Distance(items[i].Fingerprint, items[j].Fingerprint) <= maxDistance &&
+				canonical[items[i].ID] != canonical[items[j].ID] {
+				t.Fatalf("Expected %d and %d in the same cluster", items[i].ID, items[j].ID)
+			}
+		}
+	}
+}
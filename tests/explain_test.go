@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/index"
+	"search-engine-service/internal/query"
+	"search-engine-service/internal/services"
+)
+
+func TestIndexExplainMatchesExecute(t *testing.T) {
+	idx := index.New()
+	idx.AddBatch([]models.Content{
+		{ID: 1, Language: "en", Title: "Go Concurrency Patterns", Description: "Goroutines and channels in Go", Tags: "golang,concurrency"},
+		{ID: 2, Language: "en", Title: "Kubernetes Deployment Tutorial", Description: "Deploy Go services to Kubernetes", Tags: "kubernetes,devops"},
+		{ID: 3, Language: "en", Title: "Docker for Beginners", Description: "Containers explained", Tags: "docker"},
+	})
+	opts := index.DefaultOptions()
+
+	for _, q := range []string{"go", "kubernetes deployment", "title:go", `"go services"`, "kubernets", "go -docker", "go OR docker"} {
+		node, err := query.Parse(q)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", q, err)
+		}
+
+		for _, hit := range idx.Execute(node, opts) {
+			explanation, matched := idx.Explain(node, opts, hit.DocID)
+			if !matched {
+				t.Errorf("Explain(%q, %d) reported no match", q, hit.DocID)
+			}
+			if math.Abs(explanation.Value-hit.Score) > 1e-9 {
+				t.Errorf("Explain(%q, %d) = %f, Execute scored %f", q, hit.DocID, explanation.Value, hit.Score)
+			}
+			if len(explanation.Details) == 0 {
+				t.Errorf("Explain(%q, %d) has no matched terms", q, hit.DocID)
+			}
+		}
+	}
+
+	node, _ := query.Parse("kubernetes")
+	if explanation, matched := idx.Explain(node, opts, 3); matched || explanation.Value != 0 {
+		t.Errorf("Expected no match for content 3, got %+v", explanation)
+	}
+}
+
+func TestExplainScore(t *testing.T) {
+	scoringService := services.NewScoringService()
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	content := &models.Content{
+		Type:        models.ContentTypeVideo,
+		Views:       10000,
+		Likes:       800,
+		PublishedAt: now.AddDate(0, 0, -3),
+	}
+
+	explanation := scoringService.ExplainScore(content, now)
+	if math.Abs(explanation.Value-32.465165) > 1e-6 {
+		t.Errorf("Expected final score 32.465165, got %f", explanation.Value)
+	}
+	if len(explanation.Details) != 4 {
+		t.Fatalf("Expected 4 scoring factors, got %d", len(explanation.Details))
+	}
+	if freshness := explanation.Details[2]; math.Abs(freshness.Value-4.665165) > 1e-6 || freshness.Details[0].Value != 3 {
+		t.Errorf("Expected freshness 4.665165 at 3 days, got %+v", freshness)
+	}
+	if content.FinalScore != 0 {
+		t.Error("Expected ExplainScore to leave the content unchanged")
+	}
+
+	// The same content is stale a year later
+	if later := scoringService.ExplainScore(content, now.AddDate(1, 0, 0)); math.Abs(later.Value-27.801015) > 1e-6 {
+		t.Errorf("Expected final score 27.801015 a year later, got %f", later.Value)
+	}
+}
+
+func TestFilterCheck(t *testing.T) {
+	now := time.Now()
+	after := now.AddDate(0, 0, -10)
+	content := &models.Content{
+		Type:        models.ContentTypeVideo,
+		Provider:    "json_provider",
+		Views:       500,
+		Duration:    900,
+		Tags:        "golang, Concurrency",
+		FinalScore:  42,
+		PublishedAt: now.AddDate(0, 0, -3),
+	}
+	filters := models.SearchFilters{
+		Type:            models.ContentTypeVideo,
+		Provider:        "xml_provider",
+		PublishedAfter:  &after,
+		MinViews:        1000,
+		DurationBetween: []int{600, 1200},
+		TagsAll:         []string{"concurrency", "golang"},
+		TagsAny:         []string{"rust"},
+		MinScore:        40,
+	}
+
+	expected := map[string]bool{
+		"type":             true,
+		"provider":         false,
+		"published_after":  true,
+		"min_views":        false,
+		"duration_between": true,
+		"tags_any":         false,
+		"tags_all":         true,
+		"min_score":        true,
+	}
+	checks := filters.Check(content, now)
+	if len(checks) != len(expected) {
+		t.Fatalf("Expected %d checks, got %+v", len(expected), checks)
+	}
+	for _, check := range checks {
+		if passed, ok := expected[check.Filter]; !ok || passed != check.Passed {
+			t.Errorf("Unexpected check %+v", check)
+		}
+	}
+
+	if checks := (&models.SearchFilters{Type: "all"}).Check(content, now); len(checks) != 0 {
+		t.Errorf("Expected no checks without active filters, got %+v", checks)
+	}
+}
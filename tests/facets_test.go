@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/services"
+)
+
+func TestComputeFacets(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	facets := services.ComputeFacets([]models.Content{
+		{ID: 1, Type: models.ContentTypeVideo, Provider: "json_provider", Language: "en", Tags: "golang,tutorial", PublishedAt: now.AddDate(0, 0, -2)},
+		{ID: 2, Type: models.ContentTypeVideo, Provider: "json_provider", Language: "tr", Tags: "golang, Golang", PublishedAt: now.AddDate(0, 0, -20)},
+		{ID: 3, Type: models.ContentTypeText, Provider: "xml_provider", Language: "en", Tags: "rust", PublishedAt: now.AddDate(-1, 0, 0)},
+	}, now)
+
+	expectFacet(t, "type", facets.Type, []models.FacetValue{
+		{Value: "video", Count: 2, Param: "type"},
+		{Value: "text", Count: 1, Param: "type"},
+	})
+	expectFacet(t, "provider", facets.Provider, []models.FacetValue{
+		{Value: "json_provider", Count: 2, Param: "provider"},
+		{Value: "xml_provider", Count: 1, Param: "provider"},
+	})
+	expectFacet(t, "language", facets.Language, []models.FacetValue{
+		{Value: "en", Count: 2, Param: "language"},
+		{Value: "tr", Count: 1, Param: "language"},
+	})
+	// Repeated tags count once per content
+	expectFacet(t, "tags", facets.Tags, []models.FacetValue{
+		{Value: "golang", Count: 2, Param: "tag"},
+		{Value: "rust", Count: 1, Param: "tag"},
+		{Value: "tutorial", Count: 1, Param: "tag"},
+	})
+	// Date buckets are cumulative and always present
+	expectFacet(t, "published", facets.Published, []models.FacetValue{
+		{Value: models.PublishedLastWeek, Count: 1, Param: "published"},
+		{Value: models.PublishedLastMonth, Count: 2, Param: "published"},
+		{Value: models.PublishedLastQuarter, Count: 2, Param: "published"},
+		{Value: models.PublishedOlder, Count: 1, Param: "published"},
+	})
+}
+
+func TestPublishedRange(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	after, before, ok := models.PublishedRange(models.PublishedLastWeek, now)
+	if !ok || !after.Equal(now.AddDate(0, 0, -7)) || !before.IsZero() {
+		t.Errorf("Unexpected last_week range: %v - %v (%v)", after, before, ok)
+	}
+
+	after, before, ok = models.PublishedRange(models.PublishedOlder, now)
+	if !ok || !after.IsZero() || !before.Equal(now.AddDate(0, 0, -90)) {
+		t.Errorf("Unexpected older range: %v - %v (%v)", after, before, ok)
+	}
+
+	if _, _, ok := models.PublishedRange("yesterday", now); ok {
+		t.Error("Expected unknown bucket to be rejected")
+	}
+}
+
+func TestSearchFiltersValidate(t *testing.T) {
+	valid := models.SearchFilters{Type: models.ContentTypeText, Published: models.PublishedLastMonth}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected valid filters, got %v", err)
+	}
+
+	invalid := models.SearchFilters{Published: "yesterday"}
+	err := invalid.Validate()
+	filterErr, ok := err.(*models.FilterError)
+	if !ok || filterErr.Key != "published" {
+		t.Errorf("Expected published filter error, got %v", err)
+	}
+}
+
+func expectFacet(t *testing.T, name string, actual, expected []models.FacetValue) {
+	t.Helper()
+
+	if len(actual) != len(expected) {
+		t.Fatalf("Facet %s = %+v, expected %+v", name, actual, expected)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("Facet %s value %d = %+v, expected %+v", name, i, actual[i], expected[i])
+		}
+	}
+}
+
+func TestSearchFiltersUnmarshal(t *testing.T) {
+	var request struct {
+		Filters models.SearchFilters `json:"filters"`
+	}
+
+	body := `{"filters": {"language": "en", "published_after": "2024-01-01T00:00:00Z", "duration_between": [60, 600], "tags_any": ["go", "rust"], "min_score": 50}}`
+	if err := json.Unmarshal([]byte(body), &request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	filters := request.Filters
+	if filters.Language != "en" || filters.PublishedAfter == nil || len(filters.DurationBetween) != 2 || len(filters.TagsAny) != 2 || filters.MinScore != 50 {
+		t.Errorf("Unexpected filters: %+v", filters)
+	}
+	if err := filters.Validate(); err != nil {
+		t.Errorf("Expected valid filters, got %v", err)
+	}
+
+	cases := []struct {
+		body string
+		key  string
+	}{
+		{`{"filters": {"max_score": 100}}`, "max_score"},
+		{`{"filters": {"min_views": "many"}}`, "min_views"},
+	}
+	for _, tc := range cases {
+		err := json.Unmarshal([]byte(tc.body), &request)
+		var filterErr *models.FilterError
+		if !errors.As(err, &filterErr) || filterErr.Key != tc.key {
+			t.Errorf("Unmarshal(%s) error = %v, expected filter error for %s", tc.body, err, tc.key)
+		}
+	}
+}
+
+func TestSearchFiltersValidateRanges(t *testing.T) {
+	cases := []struct {
+		filters models.SearchFilters
+		key     string
+	}{
+		{models.SearchFilters{DurationBetween: []int{600}}, "duration_between"},
+		{models.SearchFilters{ReadingTimeBetween: []int{10, 5}}, "reading_time_between"},
+		{models.SearchFilters{MinViews: -1}, "min_views"},
+	}
+	for _, tc := range cases {
+		err := tc.filters.Validate()
+		var filterErr *models.FilterError
+		if !errors.As(err, &filterErr) || filterErr.Key != tc.key {
+			t.Errorf("Validate(%+v) = %v, expected filter error for %s", tc.filters, err, tc.key)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/scoring"
+)
+
+// TestDecayCurves checks the decay curves against golden tables
+func TestDecayCurves(t *testing.T) {
+	halfLife := scoring.Decay{Scale: 10, Decay: scoring.DefaultDecay}
+	withOffset := scoring.Decay{Scale: 10, Offset: 3, Decay: scoring.DefaultDecay}
+	shifted := scoring.Decay{Origin: 2, Scale: 10, Decay: scoring.DefaultDecay}
+	quarter := scoring.Decay{Scale: 10, Decay: 0.25}
+
+	tests := []struct {
+		curve    scoring.Decay
+		golden   map[scoring.DecayFunction][]float64
+		inputs   []float64
+		describe string
+	}{
+		{
+			halfLife,
+			map[scoring.DecayFunction][]float64{
+				scoring.DecayExp:    {1, 0.707107, 0.5, 0.25, 0.0625, 0.5},
+				scoring.DecayGauss:  {1, 0.840896, 0.5, 0.0625, 0.000015, 0.5},
+				scoring.DecayLinear: {1, 0.75, 0.5, 0, 0, 0.5},
+			},
+			[]float64{0, 5, 10, 20, 40, -10},
+			"scale 10",
+		},
+		{
+			withOffset,
+			map[scoring.DecayFunction][]float64{
+				scoring.DecayExp:    {1, 1, 0.757858, 0.5},
+				scoring.DecayGauss:  {1, 1, 0.895025, 0.5},
+				scoring.DecayLinear: {1, 1, 0.8, 0.5},
+			},
+			[]float64{0, 3, 7, 13},
+			"offset 3",
+		},
+		{
+			shifted,
+			map[scoring.DecayFunction][]float64{
+				scoring.DecayExp:    {1, 0.5, 0.5},
+				scoring.DecayGauss:  {1, 0.5, 0.5},
+				scoring.DecayLinear: {1, 0.5, 0.5},
+			},
+			[]float64{2, 12, -8},
+			"origin 2",
+		},
+		{
+			quarter,
+			map[scoring.DecayFunction][]float64{
+				scoring.DecayExp:    {0.5, 0.25, 0.0625},
+				scoring.DecayGauss:  {0.707107, 0.25, 0.003906},
+				scoring.DecayLinear: {0.625, 0.25, 0},
+			},
+			[]float64{5, 10, 20},
+			"decay 0.25",
+		},
+	}
+	for _, test := range tests {
+		for function, golden := range test.golden {
+			curve := test.curve
+			curve.Function = function
+			for i, x := range test.inputs {
+				if got := curve.Value(x); math.Abs(got-golden[i]) > 1e-6 {
+					t.Errorf("%s %s at %g = %f, expected %f", function, test.describe, x, got, golden[i])
+				}
+			}
+		}
+	}
+
+	invalid := []scoring.Decay{
+		{Function: scoring.DecayExp, Scale: 0, Decay: 0.5},
+		{Function: scoring.DecayExp, Scale: 10, Decay: 1},
+		{Function: scoring.DecayGauss, Scale: 10, Decay: 0},
+		{Function: scoring.DecayLinear, Scale: 10, Offset: -1, Decay: 0.5},
+		{Function: "step", Scale: 10, Decay: 0.5},
+	}
+	for _, curve := range invalid {
+		if got := curve.Value(0); got != 0 {
+			t.Errorf("Expected invalid curve %+v to evaluate to 0, got %f", curve, got)
+		}
+	}
+}
+
+// TestDecayExpressions checks the expression functions match the curves
+func TestDecayExpressions(t *testing.T) {
+	env := &scoring.Env{}
+	env[scoring.VarAgeDays] = 7
+
+	tests := []struct {
+		expr     string
+		expected float64
+	}{
+		{"decay_exp(age_days, 0, 7)", 0.5},
+		{"decay_gauss(age_days, 0, 7)", 0.5},
+		{"decay_linear(age_days, 0, 7)", 0.5},
+		{"decay_exp(age_days, 0, 10, 3)", 0.757858},
+		{"decay_linear(age_days, 0, 14, 0, 0)", 0},
+		{"decay_gauss(age_days, 7, 1)", 1},
+		{"decay_exp(age_days, 0, 0)", 0},
+	}
+	for _, test := range tests {
+		expr, err := scoring.Compile(test.expr, scoring.ContentVariables...)
+		if err != nil {
+			t.Errorf("Compile(%q) failed: %v", test.expr, err)
+			continue
+		}
+		if got := expr.Eval(env); math.Abs(got-test.expected) > 1e-6 {
+			t.Errorf("Eval(%q) = %f, expected %f", test.expr, got, test.expected)
+		}
+	}
+
+	for _, source := range []string{"decay_exp(age_days, 7)", "decay_gauss(age_days, 0, 7, 1, 0.5, 2)"} {
+		if _, err := scoring.Compile(source, scoring.ContentVariables...); err == nil {
+			t.Errorf("Expected Compile(%q) to fail", source)
+		}
+	}
+}
+
+// TestFreshnessCurves checks the freshness of the standard profile against
+// golden tables per content type
+func TestFreshnessCurves(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	profile := scoring.Builtin().Default()
+
+	ages := []float64{0, 1, 7, 8, 14, 30, 31, 90, 365}
+	golden := map[models.ContentType][]float64{
+		// Half-life of 30 days
+		models.ContentTypeVideo: {5, 4.885800, 4.253336, 4.156189, 3.618173, 2.5, 2.442900, 0.625, 0.001088},
+		// Half-life of 7 days
+		models.ContentTypeText: {5, 4.528618, 2.5, 2.264309, 1.25, 0.256355, 0.232187, 0.000674, 0},
+		// Half-life of 14 days
+		"podcast": {5, 4.758476, 3.535534, 3.364750, 2.5, 1.132155, 1.077466, 0.058047, 0},
+	}
+	for contentType, values := range golden {
+		for i, age := range ages {
+			content := &models.Content{Type: contentType, PublishedAt: now.Add(-time.Duration(age * 24 * float64(time.Hour)))}
+			if got := profile.Score(content, now, nil).Freshness; math.Abs(got-values[i]) > 1e-6 {
+				t.Errorf("Freshness of %s at %g days = %f, expected %f", contentType, age, got, values[i])
+			}
+		}
+	}
+
+	// Freshness never jumps: an hour of age costs at most a fraction of a point
+	for _, contentType := range []models.ContentType{models.ContentTypeVideo, models.ContentTypeText} {
+		previous := 5.0
+		for hours := 1; hours <= 120*24; hours++ {
+			content := &models.Content{Type: contentType, PublishedAt: now.Add(-time.Duration(hours) * time.Hour)}
+			freshness := profile.Score(content, now, nil).Freshness
+			if freshness > previous || previous-freshness > 0.05 {
+				t.Fatalf("Freshness of %s jumps from %f to %f at %d hours", contentType, previous, freshness, hours)
+			}
+			previous = freshness
+		}
+	}
+}
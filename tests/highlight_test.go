@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/index"
+	"search-engine-service/internal/query"
+)
+
+func TestHighlight(t *testing.T) {
+	content := &models.Content{
+		ID:          1,
+		Title:       "Kubernetes Deployment Tutorial",
+		Description: "This tutorial walks through a complete deployment. You will deploy a service to Kubernetes, scale it and roll back a failed release.",
+		Tags:        "kubernetes, devops,cloud native",
+	}
+	idx := index.New()
+	idx.Add(content)
+
+	options := models.HighlightOptions{PreTag: "<em>", PostTag: "</em>", FragmentSize: 60, MaxFragments: 2}
+	highlight := func(q string) map[string][]string {
+		node, err := query.Parse(q)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", q, err)
+		}
+		return idx.Highlighter(node, index.DefaultOptions()).Highlight(content, options)
+	}
+
+	// Fuzzy matches are highlighted with the indexed spelling
+	highlights := highlight("kubernetis")
+	expected := map[string][]string{
+		"title":       {"<em>Kubernetes</em> Deployment Tutorial"},
+		"description": {"will deploy a service to <em>Kubernetes</em>, scale it and roll back"},
+		"tags":        {"<em>kubernetes</em>"},
+	}
+	if !reflect.DeepEqual(highlights, expected) {
+		t.Errorf("Highlight(kubernetis) = %q, expected %q", highlights, expected)
+	}
+
+	// Phrases are highlighted as a whole and negated terms not at all
+	highlights = highlight(`"cloud native" -deployment`)
+	expected = map[string][]string{
+		"tags": {"<em>cloud native</em>"},
+	}
+	if !reflect.DeepEqual(highlights, expected) {
+		t.Errorf("Highlight(phrase) = %q, expected %q", highlights, expected)
+	}
+
+	// Field scoped terms only highlight their field
+	highlights = highlight("title:tutorial")
+	expected = map[string][]string{
+		"title": {"Kubernetes Deployment <em>Tutorial</em>"},
+	}
+	if !reflect.DeepEqual(highlights, expected) {
+		t.Errorf("Highlight(title:tutorial) = %q, expected %q", highlights, expected)
+	}
+
+	if highlights := highlight("python"); highlights != nil {
+		t.Errorf("Expected no highlights, got %q", highlights)
+	}
+}
+
+func TestHighlightFragments(t *testing.T) {
+	content := &models.Content{
+		ID:          1,
+		Description: "Go is fast. It compiles quickly and ships a single binary. Many teams pick Go for services because the tooling is simple and Go code is easy to read.",
+	}
+	idx := index.New()
+	idx.Add(content)
+
+	node, err := query.Parse("go")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	options := models.HighlightOptions{PreTag: "[", PostTag: "]", FragmentSize: 40, MaxFragments: 2}
+	fragments := idx.Highlighter(node, index.DefaultOptions()).Highlight(content, options)["description"]
+
+	expected := []string{
+		"[Go] is fast. It compiles quickly and",
+		"Many teams pick [Go] for services",
+	}
+	if !reflect.DeepEqual(fragments, expected) {
+		t.Errorf("Fragments = %q, expected %q", fragments, expected)
+	}
+}
+
+func TestHighlightOptionsValidate(t *testing.T) {
+	options := models.HighlightOptions{FragmentSize: 5}.WithDefaults()
+	if options.PreTag != "<mark>" || options.MaxFragments != 3 {
+		t.Errorf("Expected defaults to fill unset values, got %+v", options)
+	}
+	if err := options.Validate(); err == nil {
+		t.Error("Expected fragment size below the minimum to be rejected")
+	}
+}
@@ -0,0 +1,221 @@
+package tests
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/index"
+	"search-engine-service/internal/query"
+)
+
+func newTestIndex() *index.Index {
+	idx := index.New()
+	idx.AddBatch([]models.Content{
+		{ID: 1, Title: "Go Programming Tutorial", Description: "Learn the basics of Go", Tags: "golang,programming,tutorial"},
+		{ID: 2, Title: "Advanced Go Concurrency", Description: "Goroutines and channels in Go", Tags: "golang,concurrency"},
+		{ID: 3, Title: "Kubernetes for Beginners", Description: "Deploying containers", Tags: "kubernetes,devops"},
+	})
+	return idx
+}
+
+// matchIDs returns the documents matching a query without fuzzy matching,
+// ordered by ID
+func matchIDs(t *testing.T, idx *index.Index, queryString string) []uint {
+	t.Helper()
+	node, err := query.Parse(queryString)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", queryString, err)
+	}
+
+	var ids []uint
+	for _, hit := range idx.Execute(node, index.Options{BM25: index.DefaultBM25Params()}) {
+		ids = append(ids, hit.DocID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func TestIndexMatch(t *testing.T) {
+	idx := newTestIndex()
+
+	tests := []struct {
+		query    string
+		expected []uint
+	}{
+		{"go", []uint{1, 2}},
+		{"GO concurrency", []uint{2}},
+		{"kubernetes", []uint{3}},
+		{"tutorial golang", []uint{1}},
+		{"python", nil},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		result := matchIDs(t, idx, test.query)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("Match(%q) = %v, expected %v", test.query, result, test.expected)
+		}
+	}
+}
+
+func TestIndexStopWordClauses(t *testing.T) {
+	idx := index.New()
+	idx.AddBatch([]models.Content{
+		{ID: 1, Language: "en", Title: "The Go Programming Language", Tags: "golang"},
+		{ID: 2, Language: "en", Title: "Advanced Go Concurrency", Tags: "golang"},
+		{ID: 3, Language: "en", Title: "Kubernetes for Beginners", Tags: "devops"},
+		{ID: 4, Language: "tr", Title: "The Go rehberi", Tags: "golang"},
+	})
+
+	// Clauses analyzing to nothing are skipped, "the" is only a stop word in
+	// English and still matches the Turkish content
+	tests := []struct {
+		query    string
+		expected []uint
+	}{
+		{"the", []uint{4}},
+		{"of the", nil},
+		{"the OR kubernetes", []uint{3, 4}},
+		{"go the", []uint{1, 2, 4}},
+		{"go -the", []uint{1, 2}},
+		{"kubernetes -the", []uint{3}},
+		{"-the", nil},
+		{"(the OR of) AND kubernetes", []uint{3}},
+	}
+
+	for _, test := range tests {
+		result := matchIDs(t, idx, test.query)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("Match(%q) = %v, expected %v", test.query, result, test.expected)
+		}
+	}
+}
+
+func TestIndexUpdateAndRemove(t *testing.T) {
+	idx := newTestIndex()
+
+	// Re-adding a document replaces its previous postings
+	idx.Add(&models.Content{ID: 3, Title: "Helm Charts", Tags: "kubernetes"})
+	if result := matchIDs(t, idx, "beginners"); len(result) != 0 {
+		t.Errorf("Expected stale terms to be removed, got %v", result)
+	}
+	if result := matchIDs(t, idx, "helm kubernetes"); !reflect.DeepEqual(result, []uint{3}) {
+		t.Errorf("Expected updated document to match, got %v", result)
+	}
+
+	idx.Remove(2)
+	if result := matchIDs(t, idx, "go"); !reflect.DeepEqual(result, []uint{1}) {
+		t.Errorf("Expected removed document to disappear, got %v", result)
+	}
+	if idx.DocFreq("concurrency") != 0 {
+		t.Errorf("Expected empty posting lists to be dropped")
+	}
+	if idx.Len() != 2 {
+		t.Errorf("Expected 2 documents, got %d", idx.Len())
+	}
+}
+
+func TestPostingsPositions(t *testing.T) {
+	idx := newTestIndex()
+
+	postings := idx.Postings("golang")
+	if len(postings) != 2 {
+		t.Fatalf("Expected 2 postings, got %d", len(postings))
+	}
+	if postings[0].DocID != 1 || postings[0].Freq(index.FieldTags) != 1 || postings[0].Freq(index.FieldTitle) != 0 {
+		t.Errorf("Unexpected posting %+v", postings[0])
+	}
+
+	postings = idx.Postings("go")
+	if postings[0].TotalFreq() != 2 {
+		t.Errorf("Expected term frequency 2, got %d", postings[0].TotalFreq())
+	}
+}
+
+func TestBM25FieldWeights(t *testing.T) {
+	idx := index.New()
+	idx.AddBatch([]models.Content{
+		{ID: 1, Title: "Cloud Native Deployments", Description: "Running services on kubernetes", Tags: "cloud"},
+		{ID: 2, Title: "Cloud Native Deployments", Description: "Running services", Tags: "cloud,kubernetes"},
+		{ID: 3, Title: "Kubernetes", Description: "Running services", Tags: "cloud"},
+		{ID: 4, Title: "Serverless", Description: "Functions as a service", Tags: "cloud"},
+	})
+
+	node, err := query.Parse("kubernetes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hits := idx.Execute(node, index.Options{BM25: index.DefaultBM25Params()})
+	if len(hits) != 3 {
+		t.Fatalf("Expected 3 hits, got %d", len(hits))
+	}
+
+	// Title matches rank above tag matches, which rank above description matches
+	expected := []uint{3, 2, 1}
+	for i, hit := range hits {
+		if hit.DocID != expected[i] {
+			t.Errorf("Expected hit %d to be document %d, got %d", i, expected[i], hit.DocID)
+		}
+		if hit.Score <= 0 {
+			t.Errorf("Expected positive score for document %d, got %f", hit.DocID, hit.Score)
+		}
+	}
+}
+
+func TestFuzzyMatching(t *testing.T) {
+	idx := index.New()
+	idx.AddBatch([]models.Content{
+		{ID: 1, Title: "Kubernetes Operators", Tags: "kubernetes"},
+		{ID: 2, Title: "Kubernetes Networking", Tags: "kubernetes"},
+		{ID: 3, Title: "Go Tutorial", Tags: "golang"},
+	})
+
+	node, err := query.Parse("kuberntes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hits := idx.Execute(node, index.Options{BM25: index.DefaultBM25Params()}); len(hits) != 0 {
+		t.Errorf("Expected no hits without fuzzy matching, got %d", len(hits))
+	}
+	if hits := idx.Execute(node, index.DefaultOptions()); len(hits) != 2 {
+		t.Errorf("Expected 2 fuzzy hits, got %d", len(hits))
+	}
+
+	// Short terms must match exactly
+	node, _ = query.Parse("ga")
+	if hits := idx.Execute(node, index.DefaultOptions()); len(hits) != 0 {
+		t.Errorf("Expected short terms not to match fuzzily, got %d hits", len(hits))
+	}
+
+	if correction, ok := idx.Correct("kuberntes", index.DefaultFuzzyParams()); !ok || correction != "kubernetes" {
+		t.Errorf("Correct(kuberntes) = %q, %v, expected kubernetes", correction, ok)
+	}
+	if _, ok := idx.Correct("kubernetes", index.DefaultFuzzyParams()); ok {
+		t.Errorf("Expected no correction for an indexed term")
+	}
+}
+
+func TestFuzzyMaxDistance(t *testing.T) {
+	params := index.DefaultFuzzyParams()
+
+	tests := []struct {
+		term     string
+		expected int
+	}{
+		{"go", 0},
+		{"api", 0},
+		{"rust", 1},
+		{"golang", 1},
+		{"kubernetes", 2},
+		{"İstanbul", 2},
+	}
+
+	for _, test := range tests {
+		if distance := params.MaxDistance(test.term); distance != test.expected {
+			t.Errorf("MaxDistance(%q) = %d, expected %d", test.term, distance, test.expected)
+		}
+	}
+}
@@ -48,7 +48,7 @@ func setupTestEnvironment(t *testing.T) (*gin.Engine, func()) {
 	
 	// Initialize services
 	scoringService := services.NewScoringService()
-	searchService := services.NewSearchService(db.DB, nil) // Provider manager is nil for tests
+	searchService := services.NewSearchService(db.DB, nil, config.DefaultSearchConfig()) // Provider manager is nil for tests
 	
 	// Initialize handlers
 	searchHandler := handlers.NewSearchHandler(searchService, scoringService)
@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/providers"
+)
+
+// fetchFeed serves a feed and fetches it with a provider instance
+func fetchFeed(t *testing.T, kind, feed string, mapping *providers.Mapping) []models.Content {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(feed))
+	}))
+	defer server.Close()
+
+	provider, err := providers.New(providers.InstanceConfig{
+		Name:    "feed",
+		Kind:    kind,
+		URL:     server.URL,
+		Timeout: providers.Duration(5 * time.Second),
+		Mapping: mapping,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	contents, err := provider.FetchContent(context.Background())
+	if err != nil {
+		t.Fatalf("FetchContent failed: %v", err)
+	}
+	return contents
+}
+
+func TestDefaultMappings(t *testing.T) {
+	published := "2024-05-01T10:00:00Z"
+	videos := fetchFeed(t, "json", `{"videos": [{"id": "v1", "title": "Go", "url": "https://example.com/v1",
+		"views": 1500, "likes": 120, "duration": 600, "tags": "golang, tutorial", "language": "en",
+		"published_at": "`+published+`"}]}`, nil)
+	articles := fetchFeed(t, "xml", `<articles><article><id>a1</id><title>Go Modules</title>
+		<reading_time>8</reading_time><reactions>45</reactions><tags>golang,modules</tags>
+		<published_at>`+published+`</published_at></article></articles>`, nil)
+
+	if len(videos) != 1 || len(articles) != 1 {
+		t.Fatalf("Expected one video and one article, got %d and %d", len(videos), len(articles))
+	}
+	video, article := videos[0], articles[0]
+	if video.ProviderID != "v1" || video.Type != models.ContentTypeVideo || video.Views != 1500 || video.Likes != 120 ||
+		video.Duration != 600 || video.Tags != "golang,tutorial" || video.Language != "en" ||
+		video.PublishedAt.Format(time.RFC3339) != published {
+		t.Errorf("Unexpected video %+v", video)
+	}
+	if article.ProviderID != "a1" || article.Type != models.ContentTypeText || article.ReadingTime != 8 ||
+		article.Reactions != 45 || article.Tags != "golang,modules" || article.PublishedAt.Format(time.RFC3339) != published {
+		t.Errorf("Unexpected article %+v", article)
+	}
+}
+
+func TestJSONMapping(t *testing.T) {
+	var mapping providers.Mapping
+	err := json.Unmarshal([]byte(`{
+		"items": "data.items",
+		"fields": {
+			"id": "key",
+			"title": "snippet.title",
+			"url": "links[0].href",
+			"type": {"value": "video"},
+			"views": "stats.views",
+			"likes": {"path": "stats.likes", "default": "0"},
+			"duration": "stats.seconds",
+			"tags": "tags[*].name",
+			"language": {"path": "lang", "default": "en"},
+			"published_at": {"path": "pubDate", "layouts": ["Mon, 02 Jan 2006 15:04:05 -0700", "unix"]}
+		}
+	}`), &mapping)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	contents := fetchFeed(t, "json", `{"data": {"items": [
+		{"key": "k1", "snippet": {"title": "Nested"}, "links": [{"href": "https://a"}, {"href": "https://b"}],
+		 "stats": {"views": "2500", "seconds": 90.6}, "tags": [{"name": "go"}, {"name": " web "}],
+		 "pubDate": "Wed, 01 May 2024 10:00:00 +0000"},
+		{"key": "k2", "stats": {"views": 10, "likes": "7"}, "lang": "tr", "pubDate": 1714557600},
+		{"key": "k3", "stats": {"views": 10}, "pubDate": "yesterday"},
+		{"snippet": {"title": "No key"}}
+	]}}`, &mapping)
+
+	// The third item has an unparsable date without default and the fourth
+	// no id
+	if len(contents) != 2 {
+		t.Fatalf("Expected 2 contents, got %d: %+v", len(contents), contents)
+	}
+
+	first, second := contents[0], contents[1]
+	expectedTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	if first.ProviderID != "k1" || first.Title != "Nested" || first.URL != "https://a" || first.Type != models.ContentTypeVideo ||
+		first.Duration != 91 || first.Likes != 0 || first.Tags != "go,web" || first.Language != "en" ||
+		!first.PublishedAt.Equal(expectedTime) {
+		t.Errorf("Unexpected first content %+v", first)
+	}
+	if first.Views != 2500 {
+		t.Errorf("Expected views coerced from a string, got %d", first.Views)
+	}
+	if second.ProviderID != "k2" || second.Views != 10 || second.Likes != 7 || second.Language != "tr" ||
+		!second.PublishedAt.Equal(expectedTime) {
+		t.Errorf("Unexpected second content %+v", second)
+	}
+}
+
+func TestXMLMapping(t *testing.T) {
+	mapping := &providers.Mapping{
+		Items: "rss/channel/item",
+		Fields: map[string]providers.FieldMapping{
+			"id":           {Path: "guid"},
+			"title":        {Path: "title"},
+			"url":          {Path: "link/@href"},
+			"type":         {Path: "@kind", Default: "text"},
+			"reading_time": {Path: "stats/minutes"},
+			"tags":         {Path: "category"},
+			"published_at": {Path: "pubDate", Layouts: []string{time.RFC1123Z}},
+		},
+	}
+
+	contents := fetchFeed(t, "xml", `<?xml version="1.0"?>
+		<rss><channel>
+			<item kind="podcast">
+				<guid>p1</guid><title><![CDATA[Go & Gin]]></title><link href="https://a"/>
+				<stats><minutes>12</minutes></stats>
+				<category>go</category><category>web</category>
+				<pubDate>Wed, 01 May 2024 10:00:00 +0000</pubDate>
+			</item>
+			<item><guid>p2</guid><title>Plain</title></item>
+		</channel></rss>`, mapping)
+
+	if len(contents) != 2 {
+		t.Fatalf("Expected 2 contents, got %d", len(contents))
+	}
+	first, second := contents[0], contents[1]
+	if first.ProviderID != "p1" || first.Title != "Go & Gin" || first.URL != "https://a" || first.Type != "podcast" ||
+		first.ReadingTime != 12 || first.Tags != "go,web" || first.PublishedAt.Year() != 2024 {
+		t.Errorf("Unexpected first content %+v", first)
+	}
+	if second.ProviderID != "p2" || second.Type != models.ContentTypeText || second.URL != "" || !second.PublishedAt.IsZero() {
+		t.Errorf("Unexpected second content %+v", second)
+	}
+}
+
+func TestInvalidMappings(t *testing.T) {
+	tests := map[string]providers.Mapping{
+		"id is required":           {Fields: map[string]providers.FieldMapping{"title": {Path: "title"}}},
+		"unknown field":            {Fields: map[string]providers.FieldMapping{"id": {Path: "id"}, "rating": {Path: "rating"}}},
+		"either a path or a value": {Fields: map[string]providers.FieldMapping{"id": {Path: "id"}, "type": {Path: "kind", Value: "video"}}},
+		"takes no layouts":         {Fields: map[string]providers.FieldMapping{"id": {Path: "id", Layouts: []string{"2006"}}}},
+		"is not a number":          {Fields: map[string]providers.FieldMapping{"id": {Path: "id"}, "views": {Path: "views", Default: "many"}}},
+		"does not match":           {Fields: map[string]providers.FieldMapping{"id": {Path: "id"}, "published_at": {Value: "May 2024"}}},
+	}
+	for message, mapping := range tests {
+		mapping := mapping
+		_, err := providers.New(providers.InstanceConfig{Name: "feed", Kind: "json", URL: "http://a", Mapping: &mapping})
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("Expected an error containing %q, got %v", message, err)
+		}
+	}
+}
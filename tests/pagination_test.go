@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/services"
+)
+
+func TestParseSort(t *testing.T) {
+	cases := []struct {
+		field, order string
+		hasQuery     bool
+		sort         models.SortField
+		sortOrder    models.SortOrder
+	}{
+		{"", "", true, models.SortRelevance, models.OrderDesc},
+		{"", "", false, models.SortScore, models.OrderDesc},
+		{"newest", "asc", true, models.SortNewest, models.OrderAsc},
+		{"views", "", false, models.SortViews, models.OrderDesc},
+	}
+	for _, tc := range cases {
+		sort, order, err := models.ParseSort(tc.field, tc.order, tc.hasQuery)
+		if err != nil || sort != tc.sort || order != tc.sortOrder {
+			t.Errorf("ParseSort(%q, %q, %v) = %s, %s, %v", tc.field, tc.order, tc.hasQuery, sort, order, err)
+		}
+	}
+
+	var paramErr *models.ParameterError
+	if _, _, err := models.ParseSort("popularity", "", true); !errors.As(err, &paramErr) || paramErr.Key != "sort" {
+		t.Errorf("Expected sort parameter error, got %v", err)
+	}
+	if _, _, err := models.ParseSort("score", "up", true); !errors.As(err, &paramErr) || paramErr.Key != "order" {
+		t.Errorf("Expected order parameter error, got %v", err)
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	published := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	cursor := &models.Cursor{Sort: models.SortNewest, Order: models.OrderDesc, Time: published, ID: 42, Backward: true}
+
+	decoded, err := models.DecodeCursor(cursor.Encode(), models.SortNewest, models.OrderDesc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !decoded.Time.Equal(published) || decoded.ID != 42 || !decoded.Backward {
+		t.Errorf("Decoded cursor = %+v, expected %+v", decoded, cursor)
+	}
+
+	// Cursors only continue the sort they were issued for
+	var paramErr *models.ParameterError
+	if _, err := models.DecodeCursor(cursor.Encode(), models.SortNewest, models.OrderAsc); !errors.As(err, &paramErr) {
+		t.Errorf("Expected cursor mismatch error, got %v", err)
+	}
+	if _, err := models.DecodeCursor("not-a-cursor", models.SortNewest, models.OrderDesc); !errors.As(err, &paramErr) {
+		t.Errorf("Expected malformed cursor error, got %v", err)
+	}
+}
+
+// relevanceHits scores texts published a day apart at a time, ranked by
+// relevance the way a search does
+func relevanceHits(scoringService *services.ScoringService, published time.Time, now time.Time) []models.SearchHit {
+	hits := make([]models.SearchHit, 10)
+	for i := range hits {
+		content := models.Content{
+			ID:          uint(i + 1),
+			Type:        models.ContentTypeText,
+			Reactions:   i * 10,
+			PublishedAt: published.AddDate(0, 0, -i),
+		}
+		scoringService.CalculateScoreAt(&content, now)
+		hits[i] = models.SearchHit{Content: content, Score: content.FinalScore}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	return hits
+}
+
+func hitIDs(hits []models.SearchHit) []uint {
+	ids := make([]uint, len(hits))
+	for i := range hits {
+		ids[i] = hits[i].ID
+	}
+	return ids
+}
+
+func TestRelevanceCursorWhileClockAdvances(t *testing.T) {
+	scoringService := services.NewScoringService()
+	published := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	first, later := published, published.AddDate(0, 0, 10)
+
+	// Freshness decays as the clock advances, reordering the ranking
+	if before, after := hitIDs(relevanceHits(scoringService, published, first)), hitIDs(relevanceHits(scoringService, published, later)); equalIDs(before, after) {
+		t.Fatalf("Expected the advanced clock to reorder the hits, got %v", after)
+	}
+
+	page := models.PageRequest{Sort: models.SortRelevance, Order: models.OrderDesc, Page: 1, Limit: 4}
+	result := services.Paginate(relevanceHits(scoringService, published, first), page, first)
+	seen := make(map[uint]bool)
+	for result != nil {
+		for _, hit := range result.Contents {
+			if seen[hit.ID] {
+				t.Fatalf("Hit %d listed on two pages", hit.ID)
+			}
+			seen[hit.ID] = true
+		}
+		if !result.HasNext {
+			break
+		}
+
+		// The next page is requested once the clock advanced, and scored at
+		// the time the cursor was issued
+		cursor, err := models.DecodeCursor(result.NextCursor, page.Sort, page.Order)
+		if err != nil {
+			t.Fatalf("DecodeCursor failed: %v", err)
+		}
+		next := models.PageRequest{Sort: page.Sort, Order: page.Order, Page: 1, Limit: page.Limit, After: cursor}
+		now := next.ScoreTime(later)
+		if !now.Equal(first) {
+			t.Fatalf("Expected the cursor to pin the scoring time %s, got %s", first, now)
+		}
+		result = services.Paginate(relevanceHits(scoringService, published, now), next, now)
+	}
+	if len(seen) != 10 {
+		t.Errorf("Expected every hit once across the pages, got %d", len(seen))
+	}
+
+	// Other sorts don't depend on the time and aren't pinned
+	newest := models.PageRequest{Sort: models.SortNewest, Order: models.OrderDesc, Page: 1, Limit: 4}
+	result = services.Paginate(relevanceHits(scoringService, published, first), newest, first)
+	cursor, err := models.DecodeCursor(result.NextCursor, newest.Sort, newest.Order)
+	if err != nil {
+		t.Fatalf("DecodeCursor failed: %v", err)
+	}
+	if cursor.ScoredAt != nil {
+		t.Errorf("Expected no scoring time on a newest cursor, got %s", cursor.ScoredAt)
+	}
+}
+
+func equalIDs(a, b []uint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
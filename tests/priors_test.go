@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/scoring"
+	"search-engine-service/internal/services"
+)
+
+// corpusStats returns the engagement statistics of a small corpus
+func corpusStats() []models.EngagementStats {
+	return []models.EngagementStats{
+		{Provider: "json_provider", Type: models.ContentTypeVideo, Contents: 4, Views: 40000, Likes: 2000},
+		{Provider: "xml_provider", Type: models.ContentTypeText, Contents: 2, ReadingTime: 20, Reactions: 300},
+		{Provider: "xml_provider", Type: models.ContentTypeVideo, Contents: 1},
+		{Provider: "json_provider", Type: "podcast", Contents: 3, Views: 900, Likes: 90},
+	}
+}
+
+func TestComputePriors(t *testing.T) {
+	priors := scoring.ComputePriors(corpusStats(), 0.25)
+
+	tests := []struct {
+		provider    string
+		contentType models.ContentType
+		expected    scoring.Prior
+	}{
+		// Rate of likes per view, weighing a quarter of the average views
+		{"json_provider", models.ContentTypeVideo, scoring.Prior{Rate: 0.05, Weight: 2500}},
+		{"xml_provider", models.ContentTypeText, scoring.Prior{Rate: 15, Weight: 2.5}},
+		// Groups without trials and unknown providers fall back to the type
+		{"xml_provider", models.ContentTypeVideo, scoring.Prior{Rate: 0.05, Weight: 2000}},
+		{"other_provider", models.ContentTypeText, scoring.Prior{Rate: 15, Weight: 2.5}},
+		// Types without an engagement rate have no prior
+		{"json_provider", "podcast", scoring.Prior{}},
+	}
+	for _, test := range tests {
+		if prior := priors.Lookup(test.provider, test.contentType); prior != test.expected {
+			t.Errorf("Lookup(%s, %s) = %+v, expected %+v", test.provider, test.contentType, prior, test.expected)
+		}
+	}
+
+	var none *scoring.Priors
+	if prior := none.Lookup("json_provider", models.ContentTypeVideo); prior != (scoring.Prior{}) {
+		t.Errorf("Expected no prior without priors, got %+v", prior)
+	}
+}
+
+func TestEngagementExpressions(t *testing.T) {
+	env := &scoring.Env{}
+	tests := []struct {
+		expr     string
+		expected float64
+	}{
+		{"bayes(2, 2, 0.05, 2500)", 0.050759},
+		{"bayes(8000, 100000, 0.05, 2500)", 0.079268},
+		// Without a prior the average is the observed rate
+		{"bayes(50, 1000, 0, 0)", 0.05},
+		{"bayes(0, 0, 0, 0)", 0},
+		{"wilson(2, 2)", 0.342372},
+		{"wilson(50, 1000)", 0.038130},
+		{"wilson(50, 1000, 1)", 0.043546},
+		{"wilson(0, 10)", 0},
+		{"wilson(1, 0)", 0},
+	}
+	for _, test := range tests {
+		expr, err := scoring.Compile(test.expr, scoring.ContentVariables...)
+		if err != nil {
+			t.Errorf("Compile(%q) failed: %v", test.expr, err)
+			continue
+		}
+		if got := expr.Eval(env); math.Abs(got-test.expected) > 1e-6 {
+			t.Errorf("Eval(%q) = %f, expected %f", test.expr, got, test.expected)
+		}
+	}
+}
+
+func TestEngagementPriors(t *testing.T) {
+	scoringService := services.NewScoringService()
+	scoringService.SetPriors(scoring.ComputePriors(corpusStats(), 0.25))
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// Two likes out of two views no longer earn the top engagement
+	lucky := &models.Content{Type: models.ContentTypeVideo, Provider: "json_provider", Views: 2, Likes: 2, PublishedAt: now}
+	scoringService.CalculateScoreAt(lucky, now)
+	if math.Abs(lucky.EngagementScore-0.507594) > 1e-6 {
+		t.Errorf("Expected engagement 0.507594 for 2 of 2 likes, got %f", lucky.EngagementScore)
+	}
+
+	// Well-sampled contents keep close to their observed rate
+	popular := &models.Content{Type: models.ContentTypeVideo, Provider: "json_provider", Views: 100000, Likes: 8000, PublishedAt: now}
+	scoringService.CalculateScoreAt(popular, now)
+	if math.Abs(popular.EngagementScore-0.792683) > 1e-6 {
+		t.Errorf("Expected engagement 0.792683 for 8000 of 100000 likes, got %f", popular.EngagementScore)
+	}
+	if popular.EngagementScore <= lucky.EngagementScore {
+		t.Error("Expected the well-sampled content to outrank the lucky one")
+	}
+
+	breakdown := scoringService.GetScoreBreakdown(lucky)
+	if breakdown["prior_rate"] != 0.05 || breakdown["prior_weight"] != 2500 {
+		t.Errorf("Expected the prior in the breakdown, got %v", breakdown)
+	}
+
+	explanation := scoringService.ExplainScore(lucky, now)
+	engagement := explanation.Details[3]
+	if len(engagement.Details) != 4 || engagement.Details[2].Description != "prior_rate" || engagement.Details[3].Value != 2500 {
+		t.Errorf("Expected the prior in the engagement explanation, got %+v", engagement)
+	}
+}
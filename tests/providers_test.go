@@ -0,0 +1,342 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"search-engine-service/internal/config"
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/providers"
+)
+
+// staticProvider is a third-party provider kind returning fixed contents
+type staticProvider struct {
+	config providers.InstanceConfig
+}
+
+func (sp *staticProvider) GetName() string           { return sp.config.Name }
+func (sp *staticProvider) GetURL() string            { return sp.config.URL }
+func (sp *staticProvider) GetTimeout() time.Duration { return time.Duration(sp.config.Timeout) }
+func (sp *staticProvider) FetchContent(context.Context) ([]models.Content, error) {
+	return []models.Content{{ProviderID: "s1", Title: "Static", Type: sp.config.ContentType}}, nil
+}
+
+func init() {
+	providers.Register("static", func(config providers.InstanceConfig) (providers.Provider, error) {
+		return &staticProvider{config: config}, nil
+	})
+}
+
+func TestProviderRegistry(t *testing.T) {
+	kinds := strings.Join(providers.Kinds(), ",")
+	if kinds != "json,static,xml" {
+		t.Errorf("Expected kinds json,static,xml, got %s", kinds)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected registering a kind twice to panic")
+		}
+	}()
+	providers.Register("json", func(config providers.InstanceConfig) (providers.Provider, error) {
+		return nil, nil
+	})
+}
+
+func TestProviderManagerInstances(t *testing.T) {
+	var mu sync.Mutex
+	var authorization string
+	videos := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if header := r.Header.Get("Authorization"); header != "" {
+			mu.Lock()
+			authorization = header
+			mu.Unlock()
+		}
+		w.Write([]byte(`{"videos": [{"id": "v1", "title": "Go"}]}`))
+	}))
+	defer videos.Close()
+
+	manager, err := providers.NewManager(providers.ProviderConfig{
+		Instances: []providers.InstanceConfig{
+			{Name: "videos_a", Kind: "json", URL: videos.URL},
+			{Name: "videos_b", Kind: "json", URL: videos.URL, Headers: map[string]string{"Authorization": "Bearer b"}, Timeout: providers.Duration(time.Second)},
+			{Name: "podcasts", Kind: "json", URL: videos.URL, ContentType: "podcast"},
+			{Name: "static", Kind: "static", URL: "memory://static", ContentType: models.ContentTypeText},
+		},
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	if got := manager.GetProviderByName("videos_a").GetTimeout(); got != 5*time.Second {
+		t.Errorf("Expected the default timeout, got %s", got)
+	}
+	if got := manager.GetProviderByName("videos_b").GetTimeout(); got != time.Second {
+		t.Errorf("Expected the instance timeout, got %s", got)
+	}
+
+	report, err := manager.FetchAllContent(context.Background())
+	if err != nil {
+		t.Fatalf("FetchAllContent failed: %v", err)
+	}
+	contents := report.Contents()
+	expected := map[string]models.ContentType{
+		"videos_a": models.ContentTypeVideo,
+		"videos_b": models.ContentTypeVideo,
+		"podcasts": "podcast",
+		"static":   models.ContentTypeText,
+	}
+	if len(contents) != len(expected) {
+		t.Fatalf("Expected %d contents, got %d", len(expected), len(contents))
+	}
+	for _, content := range contents {
+		if contentType, ok := expected[content.Provider]; !ok || content.Type != contentType {
+			t.Errorf("Unexpected %s content from %s", content.Type, content.Provider)
+		}
+	}
+	if authorization != "Bearer b" {
+		t.Errorf("Expected the instance headers to be sent, got %q", authorization)
+	}
+}
+
+func TestProviderManagerInvalidInstances(t *testing.T) {
+	tests := map[string][]providers.InstanceConfig{
+		"declared twice": {
+			{Name: "videos", Kind: "json", URL: "http://a"},
+			{Name: "videos", Kind: "xml", URL: "http://b"},
+		},
+		"unknown kind":     {{Name: "videos", Kind: "yaml", URL: "http://a"}},
+		"url is required":  {{Name: "videos", Kind: "json"}},
+		"name is required": {{Kind: "json", URL: "http://a"}},
+	}
+	for message, instances := range tests {
+		_, err := providers.NewManager(providers.ProviderConfig{Instances: instances})
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("Expected an error containing %q, got %v", message, err)
+		}
+	}
+}
+
+func TestProviderSchedules(t *testing.T) {
+	manager, err := providers.NewManager(providers.ProviderConfig{
+		Instances: []providers.InstanceConfig{
+			{Name: "hourly", Kind: "static", URL: "memory://hourly", Schedule: providers.Duration(time.Hour)},
+			{Name: "daily", Kind: "static", URL: "memory://daily", Schedule: providers.Duration(24 * time.Hour)},
+			{Name: "manual", Kind: "static", URL: "memory://manual"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	fetched := func(at time.Time) []string {
+		report, err := manager.FetchDueContent(context.Background(), at)
+		if err != nil {
+			t.Fatalf("FetchDueContent failed: %v", err)
+		}
+		var names []string
+		for _, content := range report.Contents() {
+			names = append(names, content.Provider)
+		}
+		return names
+	}
+
+	// Scheduled providers never fetched are due right away
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	if got := strings.Join(fetched(start), ","); got != "hourly,daily" {
+		t.Errorf("Expected hourly,daily on start, got %s", got)
+	}
+	if next, ok := manager.NextDue(); !ok || !next.Equal(start.Add(time.Hour)) {
+		t.Errorf("Expected the next refresh an hour later, got %s", next)
+	}
+	if got := fetched(start.Add(30 * time.Minute)); len(got) != 0 {
+		t.Errorf("Expected nothing due after 30 minutes, got %v", got)
+	}
+	if got := strings.Join(fetched(start.Add(time.Hour)), ","); got != "hourly" {
+		t.Errorf("Expected hourly after an hour, got %s", got)
+	}
+	if got := strings.Join(fetched(start.Add(24*time.Hour)), ","); got != "hourly,daily" {
+		t.Errorf("Expected hourly,daily after a day, got %s", got)
+	}
+
+	unscheduled, err := providers.NewManager(providers.ProviderConfig{
+		Instances: []providers.InstanceConfig{{Name: "manual", Kind: "static", URL: "memory://manual"}},
+	})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	if _, ok := unscheduled.NextDue(); ok {
+		t.Error("Expected no refresh due without scheduled providers")
+	}
+}
+
+func TestProviderConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "providers.json")
+	data := `{"providers": [
+		{"name": "videos", "kind": "json", "url": "http://a", "timeout": "10s", "schedule": "15m", "headers": {"Accept": "application/json"}},
+		{"name": "articles", "kind": "xml", "url": "http://b"}
+	]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	instances, err := providers.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if len(instances) != 2 || instances[0].Timeout != providers.Duration(10*time.Second) ||
+		instances[0].Schedule != providers.Duration(15*time.Minute) || instances[0].Headers["Accept"] != "application/json" {
+		t.Errorf("Unexpected instances %+v", instances)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"providers": [{"name": "videos", "schedule": 15}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := providers.LoadConfig(path); err == nil {
+		t.Error("Expected a numeric schedule to be rejected")
+	}
+
+	// The bundled configuration builds
+	instances, err = providers.LoadConfig("../configs/providers.json")
+	if err != nil {
+		t.Fatalf("LoadConfig of the bundled file failed: %v", err)
+	}
+	if _, err := providers.NewManager(providers.ProviderConfig{Instances: instances}); err != nil {
+		t.Errorf("NewManager of the bundled file failed: %v", err)
+	}
+}
+
+func TestProviderConfigURLOverrides(t *testing.T) {
+	t.Setenv("PROVIDERS_FILE", "../configs/providers.json")
+	t.Setenv("PROVIDER_JSON_URL", "http://mock-server:3001/api/videos")
+	t.Setenv("PROVIDER_XML_URL", "")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	urls := make(map[string]string)
+	for _, instance := range cfg.Providers.Instances {
+		urls[instance.Name] = instance.URL
+	}
+	if urls[providers.DefaultJSONName] != "http://mock-server:3001/api/videos" {
+		t.Errorf("Expected PROVIDER_JSON_URL to override the file, got %s", urls[providers.DefaultJSONName])
+	}
+	if urls[providers.DefaultXMLName] != "http://localhost:3001/api/articles" {
+		t.Errorf("Expected the file URL without PROVIDER_XML_URL, got %s", urls[providers.DefaultXMLName])
+	}
+}
+
+// fetchLog records the provider fetches it is given
+type fetchLog struct {
+	mu      sync.Mutex
+	fetches map[string]int
+}
+
+func (l *fetchLog) LogProviderFetch(provider string, count int, duration time.Duration, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.fetches[provider] = count
+}
+
+func TestProviderFetchReport(t *testing.T) {
+	var mu sync.Mutex
+	var active, peak int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		peak = max(peak, active)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			active--
+			mu.Unlock()
+		}()
+
+		time.Sleep(20 * time.Millisecond)
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"videos": [{"id": "v1"}, {"id": "v2"}]}`))
+	}))
+	defer server.Close()
+
+	instances := []providers.InstanceConfig{
+		{Name: "a", Kind: "json", URL: server.URL + "/a"},
+		{Name: "down", Kind: "json", URL: server.URL + "/down"},
+		{Name: "b", Kind: "json", URL: server.URL + "/b"},
+		{Name: "c", Kind: "json", URL: server.URL + "/c"},
+		{Name: "unreachable", Kind: "json", URL: "http://127.0.0.1:1/feed"},
+	}
+	logs := &fetchLog{fetches: make(map[string]int)}
+	manager, err := providers.NewManager(providers.ProviderConfig{
+		Instances:   instances,
+		Timeout:     5 * time.Second,
+		Concurrency: 2,
+		Logger:      logs,
+		Retry:       providers.RetryPolicy{MaxAttempts: 1},
+	})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	report, err := manager.FetchAllContent(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error while some providers succeed, got %v", err)
+	}
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent fetches, got %d", peak)
+	}
+	if report.Status() != providers.FetchPartial || report.Failed() != 2 || len(report.Contents()) != 6 {
+		t.Errorf("Expected a partial report with 2 failures and 6 contents, got %s, %d, %d",
+			report.Status(), report.Failed(), len(report.Contents()))
+	}
+
+	expected := []struct {
+		provider   string
+		status     providers.FetchStatus
+		items      int
+		statusCode int
+	}{
+		{"a", providers.FetchOK, 2, http.StatusOK},
+		{"down", providers.FetchFailed, 0, http.StatusServiceUnavailable},
+		{"b", providers.FetchOK, 2, http.StatusOK},
+		{"c", providers.FetchOK, 2, http.StatusOK},
+		{"unreachable", providers.FetchFailed, 0, 0},
+	}
+	for i, result := range report.Results {
+		e := expected[i]
+		if result.Provider != e.provider || result.Status != e.status || result.Items != e.items || result.StatusCode != e.statusCode {
+			t.Errorf("Unexpected result %+v, expected %+v", result, e)
+		}
+		if (result.Error != "") != (e.status == providers.FetchFailed) || result.Duration <= 0 {
+			t.Errorf("Unexpected error or duration in %+v", result)
+		}
+		if count, ok := logs.fetches[e.provider]; !ok || count != e.items {
+			t.Errorf("Expected the fetch of %s to be logged with %d items, got %d", e.provider, e.items, count)
+		}
+	}
+
+	// Every provider failing is an error
+	failing, err := providers.NewManager(providers.ProviderConfig{
+		Instances: instances[1:2],
+		Retry:     providers.RetryPolicy{MaxAttempts: 1},
+	})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	report, err = failing.FetchAllContent(context.Background())
+	if err == nil || report.Status() != providers.FetchFailed || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Expected all providers to fail with the status code, got %v, %s", err, report.Status())
+	}
+}
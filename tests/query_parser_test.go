@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/index"
+	"search-engine-service/internal/query"
+)
+
+func TestQueryParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`golang`, `golang`},
+		{`go tutorial`, `(go AND tutorial)`},
+		{`go AND tutorial`, `(go AND tutorial)`},
+		{`go OR rust tutorial`, `(go OR (rust AND tutorial))`},
+		{`"exact phrase"`, `"exact phrase"`},
+		{`title:go tags:kubernetes`, `(title:go AND tags:kubernetes)`},
+		{`title:"go tutorial"`, `title:"go tutorial"`},
+		{`golang -beginner`, `(golang AND NOT beginner)`},
+		{`NOT java`, `NOT java`},
+		{`(go OR rust) AND type:video`, `((go OR rust) AND type:video)`},
+		{`tags:(go OR rust)`, `(tags:go OR tags:rust)`},
+		{`rest-api and more`, `(rest-api AND and AND more)`},
+		{`http://example.com`, `http://example.com`},
+		{``, ``},
+	}
+
+	for _, test := range tests {
+		node, err := query.Parse(test.input)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", test.input, err)
+			continue
+		}
+		result := ""
+		if node != nil {
+			result = node.String()
+		}
+		if result != test.expected {
+			t.Errorf("Parse(%q) = %s, expected %s", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestQueryParseErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		position int
+	}{
+		{`"unterminated`, 0},
+		{`go "`, 3},
+		{`(go OR rust`, 0},
+		{`go)`, 2},
+		{`go AND`, 6},
+		{`OR go`, 0},
+		{`title:`, 6},
+		{`go NOT`, 6},
+	}
+
+	for _, test := range tests {
+		_, err := query.Parse(test.input)
+		var syntaxErr *query.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Parse(%q) expected a syntax error, got %v", test.input, err)
+			continue
+		}
+		if syntaxErr.Position != test.position {
+			t.Errorf("Parse(%q) error position = %d, expected %d (%v)", test.input, syntaxErr.Position, test.position, err)
+		}
+	}
+}
+
+func TestIndexExecute(t *testing.T) {
+	idx := index.New()
+	idx.AddBatch([]models.Content{
+		{ID: 1, Type: models.ContentTypeVideo, Title: "Go Programming Tutorial", Description: "Learn Go step by step", Tags: "golang,tutorial"},
+		{ID: 2, Type: models.ContentTypeText, Title: "Tutorial: Programming in Rust", Description: "A Go developer learns Rust", Tags: "rust,tutorial"},
+		{ID: 3, Type: models.ContentTypeVideo, Title: "Kubernetes Deep Dive", Description: "Operating clusters", Tags: "kubernetes,devops"},
+	})
+
+	tests := []struct {
+		input    string
+		expected []uint
+	}{
+		{`"programming tutorial"`, []uint{1}},
+		{`tutorial -rust`, []uint{1}},
+		{`tutorial NOT title:rust`, []uint{1}},
+		{`go OR kubernetes`, []uint{1, 2, 3}},
+		{`title:go`, []uint{1}},
+		{`tags:kubernetes type:video`, []uint{3}},
+		{`type:text`, []uint{2}},
+		{`(rust OR kubernetes) -type:text`, []uint{3}},
+		{`-tutorial`, []uint{3}},
+		{`"learn go"`, []uint{1}},
+		{`"golang tutorial"`, nil}, // phrases never span two tags
+	}
+
+	for _, test := range tests {
+		node, err := query.Parse(test.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", test.input, err)
+		}
+
+		var ids []uint
+		for _, hit := range idx.Execute(node, index.Options{BM25: index.DefaultBM25Params()}) {
+			ids = append(ids, hit.DocID)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		if !reflect.DeepEqual(ids, test.expected) {
+			t.Errorf("Execute(%q) = %v, expected %v", test.input, ids, test.expected)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"testing"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/index"
+)
+
+func relatedIDs(hits []index.Hit) []uint {
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.DocID
+	}
+	return ids
+}
+
+func TestRelated(t *testing.T) {
+	idx := index.New()
+	idx.AddBatch([]models.Content{
+		{ID: 1, Type: models.ContentTypeVideo, Language: "en", Title: "Go Concurrency Patterns", Description: "Goroutines and channels explained", Tags: "golang,concurrency"},
+		{ID: 2, Type: models.ContentTypeVideo, Language: "en", Title: "Advanced Concurrency in Go", Description: "Channels, select and worker pools", Tags: "golang,concurrency,advanced"},
+		{ID: 3, Type: models.ContentTypeVideo, Language: "en", Title: "Building REST APIs", Description: "Handlers and routing with Gin", Tags: "golang,api"},
+		{ID: 4, Type: models.ContentTypeText, Language: "en", Title: "Understanding Go Channels", Description: "How channels synchronize goroutines", Tags: "golang,concurrency"},
+		{ID: 5, Type: models.ContentTypeVideo, Language: "en", Title: "Baking Bread", Description: "Flour, water and patience", Tags: "cooking"},
+		{ID: 6, Type: models.ContentTypeVideo, Language: "tr", Title: "Go Concurrency Patterns", Description: "Goroutines and channels", Tags: "golang,concurrency"},
+	})
+
+	t.Run("Same type by default", func(t *testing.T) {
+		ids := relatedIDs(idx.Related(1, false, 10))
+		if len(ids) != 2 || ids[0] != 2 || ids[1] != 3 {
+			t.Errorf("Expected [2 3], got %v", ids)
+		}
+	})
+
+	t.Run("Cross type", func(t *testing.T) {
+		ids := relatedIDs(idx.Related(1, true, 10))
+		if len(ids) != 3 || ids[2] != 3 {
+			t.Errorf("Expected the article among the related contents, got %v", ids)
+		}
+		for _, id := range ids {
+			if id == 1 {
+				t.Error("Expected the source content to be excluded")
+			}
+			if id == 5 || id == 6 {
+				t.Errorf("Expected unrelated and other language contents to be excluded, got %d", id)
+			}
+		}
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		if hits := idx.Related(1, true, 1); len(hits) != 1 {
+			t.Errorf("Expected 1 hit, got %d", len(hits))
+		}
+	})
+
+	t.Run("Unknown content", func(t *testing.T) {
+		if hits := idx.Related(42, true, 10); len(hits) != 0 {
+			t.Errorf("Expected no hits, got %v", hits)
+		}
+	})
+}
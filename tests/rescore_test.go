@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/services"
+)
+
+func TestScoringServiceRescore(t *testing.T) {
+	scoringService := services.NewScoringService()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	content := &models.Content{
+		Type:        models.ContentTypeVideo,
+		Views:       10000,
+		Likes:       800,
+		PublishedAt: now.AddDate(0, 0, -3),
+	}
+
+	// Contents never scored, or scored by an older profile version, change
+	if !scoringService.Rescore(content, now) {
+		t.Error("Expected an unscored content to change")
+	}
+	content.ScoringVersion--
+	if !scoringService.Rescore(content, now) || content.ScoringVersion != scoringService.DefaultProfile().Version {
+		t.Errorf("Expected a content of an older profile version to change, got v%d", content.ScoringVersion)
+	}
+
+	// A second later the freshness differs below the stored precision
+	if scoringService.Rescore(content, now.Add(time.Second)) {
+		t.Error("Expected no change a second later")
+	}
+
+	// Scores stored with 4 decimals are unchanged
+	stored := *content
+	stored.FinalScore = float64(int64(stored.FinalScore*1e4+0.5)) / 1e4
+	if scoringService.Rescore(&stored, now) {
+		t.Errorf("Expected the stored score %f to be unchanged", stored.FinalScore)
+	}
+
+	// A day later the freshness decayed
+	before := content.FinalScore
+	if !scoringService.Rescore(content, now.AddDate(0, 0, 1)) || content.FinalScore >= before {
+		t.Errorf("Expected the score to decay from %f, got %f", before, content.FinalScore)
+	}
+
+	// New engagement changes the scores
+	content.Likes = 900
+	if !scoringService.Rescore(content, now.AddDate(0, 0, 1)) {
+		t.Error("Expected new likes to change the scores")
+	}
+}
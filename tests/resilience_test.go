@@ -0,0 +1,250 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"search-engine-service/internal/faults"
+	"search-engine-service/internal/providers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// faultyFeed starts a JSON feed behind a fault injector, counting the
+// requests that reach the server
+func faultyFeed(t *testing.T) (*httptest.Server, *faults.Injector, *atomic.Int32) {
+	gin.SetMode(gin.TestMode)
+	injector := faults.New()
+	requests := &atomic.Int32{}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		requests.Add(1)
+		c.Next()
+	})
+	router.Use(injector.Middleware())
+	router.GET("/feed", func(c *gin.Context) {
+		c.String(http.StatusOK, `{"videos": [{"id": "v1"}, {"id": "v2"}]}`)
+	})
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server, injector, requests
+}
+
+func TestFeedClientRetries(t *testing.T) {
+	server, injector, requests := faultyFeed(t)
+	client := providers.NewFeedClient(providers.InstanceConfig{
+		Name: "videos",
+		URL:  server.URL + "/feed",
+		Retry: providers.RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   providers.Duration(10 * time.Millisecond),
+			MaxDelay:    providers.Duration(2 * time.Second),
+		},
+	})
+
+	tests := []struct {
+		name     string
+		fault    faults.Fault
+		fails    bool
+		requests int32
+	}{
+		{"5xx retried", faults.Fault{Status: http.StatusServiceUnavailable, Count: 2}, false, 3},
+		{"retries exhausted", faults.Fault{Status: http.StatusBadGateway, Count: 3}, true, 3},
+		{"4xx not retried", faults.Fault{Status: http.StatusNotFound, Count: 1}, true, 1},
+		{"dropped connection retried", faults.Fault{Drop: true, Count: 1}, false, 2},
+		{"429 retried", faults.Fault{Status: http.StatusTooManyRequests, Count: 1}, false, 2},
+		{"Retry-After beyond the maximum delay", faults.Fault{Status: http.StatusServiceUnavailable, RetryAfter: 60, Count: 1}, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			injector.Clear("")
+			requests.Store(0)
+			tt.fault.Path = "/feed"
+			if err := injector.Set(tt.fault); err != nil {
+				t.Fatalf("Set failed: %v", err)
+			}
+
+			body, err := client.Fetch(context.Background(), "application/json")
+			if (err != nil) != tt.fails {
+				t.Errorf("Expected failure %v, got %v", tt.fails, err)
+			}
+			if !tt.fails && len(body) == 0 {
+				t.Error("Expected the feed body")
+			}
+			if got := requests.Load(); got != tt.requests {
+				t.Errorf("Expected %d requests, got %d", tt.requests, got)
+			}
+		})
+	}
+
+	// Retry-After sets the delay before the next attempt
+	injector.Clear("")
+	injector.Set(faults.Fault{Path: "/feed", Status: http.StatusServiceUnavailable, RetryAfter: 1, Count: 1})
+	start := time.Now()
+	if _, err := client.Fetch(context.Background(), "application/json"); err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected Retry-After to delay the retry by 1s, got %s", elapsed)
+	}
+}
+
+func TestFeedClientRequestErrors(t *testing.T) {
+	retry := providers.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   providers.Duration(time.Second),
+		MaxDelay:    providers.Duration(2 * time.Second),
+	}
+
+	// Requests that cannot be made fail without backing off
+	for _, url := range []string{"http://[::1/feed", "ftp://example.com/feed"} {
+		client := providers.NewFeedClient(providers.InstanceConfig{Name: "videos", URL: url, Retry: retry})
+		start := time.Now()
+		if _, err := client.Fetch(context.Background(), "application/json"); err == nil {
+			t.Errorf("Expected fetching %s to fail", url)
+		}
+		if elapsed := time.Since(start); elapsed >= time.Second/2 {
+			t.Errorf("Expected fetching %s to fail without retries, took %s", url, elapsed)
+		}
+	}
+
+	// Attempt timeouts are retried
+	server, injector, requests := faultyFeed(t)
+	injector.Set(faults.Fault{Path: "/feed", Delay: "300ms", Count: 1})
+	client := providers.NewFeedClient(providers.InstanceConfig{
+		Name:    "videos",
+		URL:     server.URL + "/feed",
+		Timeout: providers.Duration(100 * time.Millisecond),
+		Retry:   providers.RetryPolicy{MaxAttempts: 2, BaseDelay: providers.Duration(10 * time.Millisecond)},
+	})
+	if _, err := client.Fetch(context.Background(), "application/json"); err != nil {
+		t.Fatalf("Expected the retry after the timeout to succeed, got %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected 2 requests, got %d", got)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := providers.RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   providers.Duration(100 * time.Millisecond),
+		MaxDelay:    providers.Duration(time.Second),
+	}
+
+	tests := []struct {
+		retry   int
+		ceiling time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{5, time.Second},
+		{100, time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 50; i++ {
+			delay := policy.Backoff(tt.retry)
+			if delay < tt.ceiling/2 || delay > tt.ceiling {
+				t.Fatalf("Expected the backoff of retry %d between %s and %s, got %s", tt.retry, tt.ceiling/2, tt.ceiling, delay)
+			}
+		}
+	}
+}
+
+func TestProviderCircuitBreaker(t *testing.T) {
+	server, injector, requests := faultyFeed(t)
+	injector.Set(faults.Fault{Path: "/feed", Status: http.StatusInternalServerError})
+
+	manager, err := providers.NewManager(providers.ProviderConfig{
+		Instances: []providers.InstanceConfig{
+			{Name: "videos", Kind: "json", URL: server.URL + "/feed"},
+		},
+		Retry: providers.RetryPolicy{MaxAttempts: 1},
+		Breaker: providers.BreakerPolicy{
+			FailureThreshold: 2,
+			OpenTimeout:      providers.Duration(100 * time.Millisecond),
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	circuit := func() (providers.HealthStatus, providers.BreakerState) {
+		health := manager.Health()
+		return health[0].Status, health[0].Circuit.State
+	}
+
+	manager.FetchAllContent(context.Background())
+	if status, state := circuit(); status != providers.HealthDegraded || state != providers.BreakerClosed {
+		t.Errorf("Expected a degraded provider with a closed circuit, got %s, %s", status, state)
+	}
+
+	// The threshold opens the circuit, which then rejects fetches without a request
+	manager.FetchAllContent(context.Background())
+	if status, state := circuit(); status != providers.HealthUnhealthy || state != providers.BreakerOpen {
+		t.Errorf("Expected an unhealthy provider with an open circuit, got %s, %s", status, state)
+	}
+	report, err := manager.FetchAllContent(context.Background())
+	if err == nil || !errors.Is(report.Results[0].Err, providers.ErrCircuitOpen) {
+		t.Errorf("Expected the open circuit to reject the fetch, got %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected 2 requests to reach the feed, got %d", got)
+	}
+
+	// After the timeout a successful trial closes the circuit
+	injector.Clear("")
+	time.Sleep(150 * time.Millisecond)
+	if status, state := circuit(); status != providers.HealthDegraded || state != providers.BreakerHalfOpen {
+		t.Errorf("Expected a degraded provider with a half-open circuit, got %s, %s", status, state)
+	}
+	if _, err := manager.FetchAllContent(context.Background()); err != nil {
+		t.Fatalf("Expected the trial fetch to succeed, got %v", err)
+	}
+	health := manager.Health()
+	if health[0].Status != providers.HealthHealthy || health[0].Circuit.State != providers.BreakerClosed || health[0].Circuit.ConsecutiveFailures != 0 {
+		t.Errorf("Expected a healthy provider with a closed circuit, got %+v", health[0])
+	}
+	if overall := providers.OverallHealth(health); overall != providers.HealthHealthy {
+		t.Errorf("Expected healthy providers, got %s", overall)
+	}
+}
+
+func TestBreakerHalfOpenTrial(t *testing.T) {
+	breaker := providers.NewBreaker(providers.BreakerPolicy{
+		FailureThreshold: 1,
+		OpenTimeout:      providers.Duration(20 * time.Millisecond),
+	})
+
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Expected a closed breaker to allow fetches, got %v", err)
+	}
+	breaker.Record(errors.New("boom"))
+	if err := breaker.Allow(); !errors.Is(err, providers.ErrCircuitOpen) {
+		t.Fatalf("Expected an open breaker, got %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Expected a trial fetch, got %v", err)
+	}
+	if err := breaker.Allow(); !errors.Is(err, providers.ErrCircuitOpen) {
+		t.Errorf("Expected a single trial fetch, got %v", err)
+	}
+
+	// A failed trial opens the breaker again
+	breaker.Record(errors.New("boom"))
+	if snapshot := breaker.Snapshot(); snapshot.State != providers.BreakerOpen || snapshot.RetryAt == nil || snapshot.LastError != "boom" {
+		t.Errorf("Expected the breaker to open again, got %+v", snapshot)
+	}
+}
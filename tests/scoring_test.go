@@ -0,0 +1,274 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/scoring"
+	"search-engine-service/internal/services"
+)
+
+func TestScoringExpressions(t *testing.T) {
+	env := scoring.NewEnv(&models.Content{
+		Type:        models.ContentTypeVideo,
+		Views:       1000,
+		Likes:       50,
+		PublishedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), nil)
+
+	tests := []struct {
+		expr     string
+		expected float64
+	}{
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 - 4 - 3", 3},
+		{"-2 * -3", 6},
+		{"views / 1000 + likes / 100", 1.5},
+		{"age_days", 14},
+		{"age_hours / 24", 14},
+		{"is_video * 2 + is_text", 2},
+		{"age_days <= 14", 1},
+		{"age_days > 14", 0},
+		{"if(age_days < 7, 5, if(age_days < 30, 3, 0))", 3},
+		{"min(3, views, 2)", 2},
+		{"max(likes, 7)", 50},
+		{"clamp(likes, 0, 10)", 10},
+		{"log1p(0)", 0},
+		{"pow(2, 10)", 1024},
+		{"decay_exp(age_days, 0, 7)", 0.25},
+		{"decay_exp(0, 0, 7)", 1},
+		// Out of range values evaluate to 0
+		{"likes / 0", 0},
+		{"log(0)", 0},
+		{"sqrt(-1)", 0},
+		{"pow(10, 400)", 0},
+		{"decay_exp(1, 0, 0)", 0},
+	}
+	for _, test := range tests {
+		expr, err := scoring.Compile(test.expr, scoring.ContentVariables...)
+		if err != nil {
+			t.Errorf("Compile(%q) failed: %v", test.expr, err)
+			continue
+		}
+		if got := expr.Eval(env); math.Abs(got-test.expected) > 1e-9 {
+			t.Errorf("Eval(%q) = %f, expected %f", test.expr, got, test.expected)
+		}
+	}
+}
+
+func TestScoringExpressionErrors(t *testing.T) {
+	tests := []struct {
+		expr     string
+		position int
+		message  string
+	}{
+		{"", 0, "empty expression"},
+		{"views +", 7, "unexpected"},
+		{"(views + 1", 10, "missing closing parenthesis"},
+		{"views likes", 6, "unexpected"},
+		{"score * 2", 0, "unknown variable"},
+		{"base * 2", 0, "not available"},
+		{"exp(views)", 0, "unknown function"},
+		{"log1p(views, likes)", 0, "takes 1 argument"},
+		{"if(views > 0, 1)", 0, "takes 3 arguments"},
+		{"1 < 2 < 3", 6, "cannot be chained"},
+		{"views = 1", 6, "unexpected"},
+		{"views % 2", 6, "unexpected"},
+		{"1..2", 0, "invalid number"},
+	}
+	for _, test := range tests {
+		_, err := scoring.Compile(test.expr, scoring.ContentVariables...)
+		var syntaxErr *scoring.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Compile(%q) = %v, expected a syntax error", test.expr, err)
+			continue
+		}
+		if syntaxErr.Position != test.position || !strings.Contains(syntaxErr.Message, test.message) {
+			t.Errorf("Compile(%q) = %v, expected %q at position %d", test.expr, err, test.message, test.position)
+		}
+	}
+
+	expr, err := scoring.Compile("base * type_multiplier + log1p(views) + base", scoring.FinalVariables...)
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	if vars := expr.Variables(); len(vars) != 3 || vars[0] != scoring.VarBase || vars[2] != scoring.VarViews {
+		t.Errorf("Unexpected variables %v", vars)
+	}
+}
+
+// TestScoringDefaultProfile checks the scores of the built-in profile
+func TestScoringDefaultProfile(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	profile := scoring.Builtin().Default()
+
+	tests := []struct {
+		content  models.Content
+		expected scoring.Scores
+	}{
+		{
+			models.Content{Type: models.ContentTypeVideo, Views: 10000, Likes: 800, PublishedAt: now.AddDate(0, 0, -3)},
+			scoring.Scores{Base: 18, TypeMultiplier: 1.5, Freshness: 4.665165, Engagement: 0.8, Final: 32.465165},
+		},
+		{
+			models.Content{Type: models.ContentTypeText, ReadingTime: 10, Reactions: 200, PublishedAt: now.AddDate(0, 0, -20)},
+			scoring.Scores{Base: 14, TypeMultiplier: 1, Freshness: 0.690056, Engagement: 100, Final: 114.690056},
+		},
+		{
+			models.Content{Type: models.ContentTypeVideo, PublishedAt: now.AddDate(0, 0, -60)},
+			scoring.Scores{TypeMultiplier: 1.5, Freshness: 1.25, Final: 1.25},
+		},
+		{
+			models.Content{Type: models.ContentTypeText, Reactions: 100, PublishedAt: now.AddDate(-1, 0, 0)},
+			scoring.Scores{Base: 2, TypeMultiplier: 1, Final: 2},
+		},
+		// Unknown types only score freshness
+		{
+			models.Content{Type: "podcast", Views: 5000, PublishedAt: now.AddDate(0, 0, -1)},
+			scoring.Scores{TypeMultiplier: 1, Freshness: 4.758476, Final: 4.758476},
+		},
+	}
+	for _, test := range tests {
+		if scores := profile.Score(&test.content, now, nil); !scoresClose(scores, test.expected) {
+			t.Errorf("Score(%+v) = %+v, expected %+v", test.content, scores, test.expected)
+		}
+	}
+}
+
+// scoresClose reports whether two scores are equal up to rounding
+func scoresClose(a, b scoring.Scores) bool {
+	close := func(x, y float64) bool { return math.Abs(x-y) < 1e-6 }
+	return close(a.Base, b.Base) && close(a.TypeMultiplier, b.TypeMultiplier) &&
+		close(a.Freshness, b.Freshness) && close(a.Engagement, b.Engagement) && close(a.Final, b.Final)
+}
+
+func TestScoringProfilesConfig(t *testing.T) {
+	profilesConfig, err := scoring.LoadConfig("../configs/scoring_profiles.json")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	profiles, err := scoring.CompileConfig(profilesConfig)
+	if err != nil {
+		t.Fatalf("CompileConfig failed: %v", err)
+	}
+	if names := profiles.Names(); len(names) != 2 || names[0] != "fresh" || names[1] != "standard" {
+		t.Errorf("Unexpected profiles %v", names)
+	}
+
+	// The shipped standard profile matches the built-in one
+	now := time.Now()
+	content := &models.Content{Type: models.ContentTypeVideo, Views: 2500, Likes: 90, PublishedAt: now.AddDate(0, 0, -12)}
+	if shipped, builtin := profiles.Default().Score(content, now, nil), scoring.Builtin().Default().Score(content, now, nil); shipped != builtin {
+		t.Errorf("Expected the shipped standard profile to score %+v, got %+v", builtin, shipped)
+	}
+
+	var expressions scoring.Expressions
+	if err := json.Unmarshal([]byte(`"age_days"`), &expressions); err != nil || expressions[scoring.AnyType] != "age_days" {
+		t.Errorf("Expected a plain string to apply to every type, got %v (%v)", expressions, err)
+	}
+
+	// with returns a configuration of a single valid profile changed by edit
+	with := func(edit func(p *scoring.ProfileConfig)) scoring.Config {
+		p := scoring.ProfileConfig{Name: "p", Version: 1, Base: scoring.Expressions{"*": "views"}, Final: "base"}
+		edit(&p)
+		return scoring.Config{Profiles: []scoring.ProfileConfig{p}}
+	}
+	duplicate := with(func(p *scoring.ProfileConfig) {})
+	duplicate.Profiles = append(duplicate.Profiles, duplicate.Profiles[0])
+	unknownDefault := with(func(p *scoring.ProfileConfig) {})
+	unknownDefault.Default = "q"
+
+	invalid := []struct {
+		config  scoring.Config
+		message string
+	}{
+		{scoring.Config{}, "no scoring profiles"},
+		{unknownDefault, `default scoring profile "q"`},
+		{duplicate, "duplicate"},
+		{with(func(p *scoring.ProfileConfig) { p.Version = 0 }), "version"},
+		{with(func(p *scoring.ProfileConfig) { p.Name = "" }), "without a name"},
+		{with(func(p *scoring.ProfileConfig) { p.Base = scoring.Expressions{"audio": "1"} }), `unknown content type "audio"`},
+		{with(func(p *scoring.ProfileConfig) { p.Freshness = scoring.Expressions{"*": "freshness"} }), "freshness[*]"},
+		{with(func(p *scoring.ProfileConfig) { p.Final = "base +" }), "final"},
+	}
+	for _, test := range invalid {
+		if _, err := scoring.CompileConfig(test.config); err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Errorf("Expected an error containing %q, got %v", test.message, err)
+		}
+	}
+}
+
+func TestScoringServiceProfiles(t *testing.T) {
+	profilesConfig := scoring.DefaultConfig()
+	profilesConfig.Profiles = append(profilesConfig.Profiles, scoring.ProfileConfig{
+		Name:    "views",
+		Version: 3,
+		Base:    scoring.Expressions{"*": "log1p(views)"},
+		Final:   "base",
+	})
+	profiles, err := scoring.CompileConfig(profilesConfig)
+	if err != nil {
+		t.Fatalf("CompileConfig failed: %v", err)
+	}
+	scoringService := services.NewScoringServiceWithProfiles(profiles)
+
+	_, err = scoringService.Profile("unknown")
+	var paramErr *models.ParameterError
+	if !errors.As(err, &paramErr) || paramErr.Key != "profile" || !strings.Contains(paramErr.Message, "'standard', 'views'") {
+		t.Errorf("Expected a profile parameter error, got %v", err)
+	}
+
+	profile, err := scoringService.Profile("views")
+	if err != nil {
+		t.Fatalf("Profile failed: %v", err)
+	}
+	content := &models.Content{Type: models.ContentTypeVideo, Views: 99, PublishedAt: time.Now()}
+	scoringService.CalculateScoreWith(profile, content, time.Now())
+	if content.FinalScore != math.Log1p(99) || content.TypeMultiplier != 1 || content.ScoringProfile != "views" || content.ScoringVersion != 3 {
+		t.Errorf("Unexpected scores %+v", content)
+	}
+
+	// Contents are stored with the default profile
+	scoringService.CalculateScore(content)
+	if content.ScoringProfile != "standard" || content.ScoringVersion != 3 {
+		t.Errorf("Expected the standard profile, got %s v%d", content.ScoringProfile, content.ScoringVersion)
+	}
+
+	explanation := profile.Explain(content, time.Now(), nil)
+	if explanation.Description != "final score of profile views v3, base" || explanation.Details[1].Description != `type multiplier, no expression for type "video"` {
+		t.Errorf("Unexpected explanation %+v", explanation)
+	}
+}
+
+func TestMaxPossibleScore(t *testing.T) {
+	profilesConfig, err := scoring.LoadConfig("../configs/scoring_profiles.json")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	// The freshness bound follows the default profile, 5 for standard and 20 for fresh
+	tests := []struct {
+		profile  string
+		expected float64
+	}{
+		{"standard", 100*1.5 + 5 + 10},
+		{"fresh", 100 + 20 + 10},
+	}
+	for _, test := range tests {
+		profilesConfig.Default = test.profile
+		profiles, err := scoring.CompileConfig(profilesConfig)
+		if err != nil {
+			t.Fatalf("CompileConfig failed: %v", err)
+		}
+		scoringService := services.NewScoringServiceWithProfiles(profiles)
+		if max := scoringService.GetMaxPossibleScore(models.ContentTypeVideo); math.Abs(max-test.expected) > 1e-6 {
+			t.Errorf("GetMaxPossibleScore(video) with %s = %f, expected %f", test.profile, max, test.expected)
+		}
+	}
+}
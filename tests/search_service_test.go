@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 
 func TestScoringService(t *testing.T) {
 	scoringService := services.NewScoringService()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
 
 	t.Run("Test Video Scoring", func(t *testing.T) {
 		content := &models.Content{
@@ -17,17 +19,17 @@ func TestScoringService(t *testing.T) {
 			Views:       10000,
 			Likes:       800,
 			Duration:    1800,
-			PublishedAt: time.Now().AddDate(0, 0, -3), // 3 days ago
+			PublishedAt: now.AddDate(0, 0, -3), // 3 days ago
 		}
 
-		scoringService.CalculateScore(content)
+		scoringService.CalculateScoreAt(content, now)
 
 		// Expected calculations:
 		// Base score: 10000/1000 + 800/100 = 10 + 8 = 18
 		// Type multiplier: 1.5
-		// Freshness score: 5 (within 1 week)
+		// Freshness score: 5 * 0.5^(3/30) = 4.665165 (video half-life of 30 days)
 		// Engagement score: (800/10000) * 10 = 0.8
-		// Final score: (18 * 1.5) + 5 + 0.8 = 27 + 5 + 0.8 = 32.8
+		// Final score: (18 * 1.5) + 4.665165 + 0.8 = 32.465165
 
 		if content.BaseScore != 18.0 {
 			t.Errorf("Expected base score 18.0, got %f", content.BaseScore)
@@ -37,16 +39,16 @@ func TestScoringService(t *testing.T) {
 			t.Errorf("Expected type multiplier 1.5, got %f", content.TypeMultiplier)
 		}
 
-		if content.FreshnessScore != 5.0 {
-			t.Errorf("Expected freshness score 5.0, got %f", content.FreshnessScore)
+		if math.Abs(content.FreshnessScore-4.665165) > 1e-6 {
+			t.Errorf("Expected freshness score 4.665165, got %f", content.FreshnessScore)
 		}
 
 		if content.EngagementScore != 0.8 {
 			t.Errorf("Expected engagement score 0.8, got %f", content.EngagementScore)
 		}
 
-		expectedFinalScore := 32.8
-		if content.FinalScore != expectedFinalScore {
+		expectedFinalScore := 32.465165
+		if math.Abs(content.FinalScore-expectedFinalScore) > 1e-6 {
 			t.Errorf("Expected final score %f, got %f", expectedFinalScore, content.FinalScore)
 		}
 	})
@@ -56,17 +58,17 @@ func TestScoringService(t *testing.T) {
 			Type:        models.ContentTypeText,
 			ReadingTime: 10,
 			Reactions:   200,
-			PublishedAt: time.Now().AddDate(0, 0, -20), // 20 days ago
+			PublishedAt: now.AddDate(0, 0, -20), // 20 days ago
 		}
 
-		scoringService.CalculateScore(content)
+		scoringService.CalculateScoreAt(content, now)
 
 		// Expected calculations:
 		// Base score: 10 + 200/50 = 10 + 4 = 14
 		// Type multiplier: 1.0
-		// Freshness score: 3 (within 1 month)
+		// Freshness score: 5 * 0.5^(20/7) = 0.690056 (text half-life of 7 days)
 		// Engagement score: (200/10) * 5 = 100
-		// Final score: (14 * 1.0) + 3 + 100 = 14 + 3 + 100 = 117
+		// Final score: (14 * 1.0) + 0.690056 + 100 = 114.690056
 
 		if content.BaseScore != 14.0 {
 			t.Errorf("Expected base score 14.0, got %f", content.BaseScore)
@@ -76,16 +78,16 @@ func TestScoringService(t *testing.T) {
 			t.Errorf("Expected type multiplier 1.0, got %f", content.TypeMultiplier)
 		}
 
-		if content.FreshnessScore != 3.0 {
-			t.Errorf("Expected freshness score 3.0, got %f", content.FreshnessScore)
+		if math.Abs(content.FreshnessScore-0.690056) > 1e-6 {
+			t.Errorf("Expected freshness score 0.690056, got %f", content.FreshnessScore)
 		}
 
 		if content.EngagementScore != 100.0 {
 			t.Errorf("Expected engagement score 100.0, got %f", content.EngagementScore)
 		}
 
-		expectedFinalScore := 117.0
-		if content.FinalScore != expectedFinalScore {
+		expectedFinalScore := 114.690056
+		if math.Abs(content.FinalScore-expectedFinalScore) > 1e-6 {
 			t.Errorf("Expected final score %f, got %f", expectedFinalScore, content.FinalScore)
 		}
 	})
@@ -95,24 +97,24 @@ func TestScoringService(t *testing.T) {
 			Type:        models.ContentTypeVideo,
 			Views:       5000,
 			Likes:       300,
-			PublishedAt: time.Now().AddDate(0, 0, -100), // 100 days ago
+			PublishedAt: now.AddDate(0, 0, -100), // 100 days ago
 		}
 
-		scoringService.CalculateScore(content)
+		scoringService.CalculateScoreAt(content, now)
 
 		// Expected calculations:
 		// Base score: 5000/1000 + 300/100 = 5 + 3 = 8
 		// Type multiplier: 1.5
-		// Freshness score: 0 (older than 3 months)
+		// Freshness score: 5 * 0.5^(100/30) = 0.496063
 		// Engagement score: (300/5000) * 10 = 0.6
-		// Final score: (8 * 1.5) + 0 + 0.6 = 12 + 0 + 0.6 = 12.6
+		// Final score: (8 * 1.5) + 0.496063 + 0.6 = 13.096063
 
-		if content.FreshnessScore != 0.0 {
-			t.Errorf("Expected freshness score 0.0, got %f", content.FreshnessScore)
+		if math.Abs(content.FreshnessScore-0.496063) > 1e-6 {
+			t.Errorf("Expected freshness score 0.496063, got %f", content.FreshnessScore)
 		}
 
-		expectedFinalScore := 12.6
-		if content.FinalScore != expectedFinalScore {
+		expectedFinalScore := 13.096063
+		if math.Abs(content.FinalScore-expectedFinalScore) > 1e-6 {
 			t.Errorf("Expected final score %f, got %f", expectedFinalScore, content.FinalScore)
 		}
 	})
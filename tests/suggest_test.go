@@ -0,0 +1,37 @@
+package tests
+
+import (
+	"testing"
+
+	"search-engine-service/internal/suggest"
+)
+
+func TestSuggestions(t *testing.T) {
+	trie := suggest.Build([]suggest.Document{
+		{ID: 1, Title: "Go Programming Tutorial", Tags: "golang,tutorial", Score: 30},
+		{ID: 2, Title: "Advanced Go Concurrency", Tags: "golang,concurrency", Score: 20},
+		{ID: 3, Title: "Gin Web Framework", Tags: "gin,web", Score: 50},
+	})
+
+	suggestions := trie.Suggest("go", 10)
+	expected := []suggest.Suggestion{
+		{Text: "golang", Type: suggest.TypeTag, Count: 2, Score: 50},
+		{Text: "Go Programming Tutorial", Type: suggest.TypeTitle, Count: 1, Score: 30},
+		{Text: "Advanced Go Concurrency", Type: suggest.TypeTitle, Count: 1, Score: 20},
+	}
+	if len(suggestions) != len(expected) {
+		t.Fatalf("Suggest(go) returned %d suggestions, expected %d: %+v", len(suggestions), len(expected), suggestions)
+	}
+	for i := range expected {
+		if suggestions[i] != expected[i] {
+			t.Errorf("Suggestion %d = %+v, expected %+v", i, suggestions[i], expected[i])
+		}
+	}
+
+	if suggestions := trie.Suggest("GI", 1); len(suggestions) != 1 || suggestions[0].Text != "Gin Web Framework" {
+		t.Errorf("Expected case-insensitive limited completion, got %+v", suggestions)
+	}
+	if suggestions := trie.Suggest("python", 10); len(suggestions) != 0 {
+		t.Errorf("Expected no completions, got %+v", suggestions)
+	}
+}
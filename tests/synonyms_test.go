@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/index"
+	"search-engine-service/internal/query"
+	"search-engine-service/internal/synonyms"
+)
+
+func testSynonymRules() *synonyms.Set {
+	return synonyms.Compile([]models.SynonymRule{
+		{Type: models.SynonymEquivalent, Synonyms: "k8s, Kubernetes"},
+		{Type: models.SynonymExpansion, Input: "js", Synonyms: "javascript,ecmascript"},
+		{Type: models.SynonymExpansion, Input: "machine learning", Synonyms: "ml"},
+		{Type: models.SynonymRewrite, Input: "golang", Replacement: "go"},
+		{Type: models.SynonymRewrite, Input: "how to"},
+		// Invalid rules are skipped
+		{Type: models.SynonymExpansion, Synonyms: "orphan"},
+	})
+}
+
+func TestSynonymRewrite(t *testing.T) {
+	rules := testSynonymRules()
+
+	tests := []struct {
+		query    string
+		expected string
+		rewrites []models.QueryRewrite
+	}{
+		{"golang tutorial", "go tutorial", []models.QueryRewrite{{Type: models.SynonymRewrite, From: "golang", To: []string{"go"}}}},
+		{"GoLang", "go", []models.QueryRewrite{{Type: models.SynonymRewrite, From: "golang", To: []string{"go"}}}},
+		{"How  To deploy", "deploy", []models.QueryRewrite{{Type: models.SynonymRewrite, From: "how to", To: []string{}}}},
+		// Only whole words are rewritten
+		{"golangci lint", "golangci lint", nil},
+		{"title:golang", "title:go", []models.QueryRewrite{{Type: models.SynonymRewrite, From: "golang", To: []string{"go"}}}},
+	}
+
+	for _, tt := range tests {
+		rewritten, rewrites := rules.Rewrite(tt.query)
+		if rewritten != tt.expected {
+			t.Errorf("Rewrite(%q) = %q, expected %q", tt.query, rewritten, tt.expected)
+		}
+		if !reflect.DeepEqual(rewrites, tt.rewrites) {
+			t.Errorf("Rewrite(%q) rewrites = %+v, expected %+v", tt.query, rewrites, tt.rewrites)
+		}
+	}
+}
+
+func TestSynonymExpand(t *testing.T) {
+	rules := testSynonymRules()
+
+	tests := []struct {
+		query    string
+		expected string
+		from     []string
+	}{
+		{"k8s", "(k8s OR kubernetes)", []string{"k8s"}},
+		{"Kubernetes tutorial", "((Kubernetes OR k8s) AND tutorial)", []string{"kubernetes"}},
+		// Expansions only apply in one direction
+		{"js", "(js OR javascript OR ecmascript)", []string{"js"}},
+		{"javascript", "javascript", nil},
+		{"title:js", "(title:js OR title:javascript OR title:ecmascript)", []string{"js"}},
+		{"machine learning basics", "(((machine AND learning) OR ml) AND basics)", []string{"machine learning"}},
+		{`"machine learning"`, `("machine learning" OR ml)`, []string{"machine learning"}},
+		{"k8s -js", `((k8s OR kubernetes) AND NOT (js OR javascript OR ecmascript))`, []string{"k8s", "js"}},
+		{"type:video k8s", "(type:video AND (k8s OR kubernetes))", []string{"k8s"}},
+	}
+
+	for _, tt := range tests {
+		node, err := query.Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tt.query, err)
+		}
+		expanded, rewrites := rules.Expand(node)
+		if expanded.String() != tt.expected {
+			t.Errorf("Expand(%q) = %s, expected %s", tt.query, expanded, tt.expected)
+		}
+
+		var from []string
+		for _, rewrite := range rewrites {
+			from = append(from, rewrite.From)
+		}
+		if !reflect.DeepEqual(from, tt.from) {
+			t.Errorf("Expand(%q) applied %v, expected %v", tt.query, from, tt.from)
+		}
+	}
+}
+
+func TestSynonymExpandMatchesIndex(t *testing.T) {
+	idx := index.New()
+	idx.AddBatch([]models.Content{
+		{ID: 1, Title: "Kubernetes in production", Language: "en"},
+		{ID: 2, Title: "Scaling k8s clusters", Language: "en"},
+		{ID: 3, Title: "Docker basics", Language: "en"},
+	})
+
+	node, err := query.Parse("k8s")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	expanded, _ := testSynonymRules().Expand(node)
+
+	var ids []uint
+	for _, hit := range idx.Execute(expanded, index.DefaultOptions()) {
+		ids = append(ids, hit.DocID)
+	}
+	if len(ids) != 2 {
+		t.Errorf("Expected both spellings to match, got %v", ids)
+	}
+}
+
+func TestSynonymRuleValidate(t *testing.T) {
+	tests := []struct {
+		rule  models.SynonymRule
+		valid bool
+		field string
+	}{
+		{models.SynonymRule{Type: models.SynonymEquivalent, Synonyms: "a,b"}, true, ""},
+		{models.SynonymRule{Type: models.SynonymEquivalent, Synonyms: "a, ,"}, false, "synonyms"},
+		{models.SynonymRule{Type: models.SynonymExpansion, Input: "a", Synonyms: "b"}, true, ""},
+		{models.SynonymRule{Type: models.SynonymExpansion, Synonyms: "b"}, false, "input"},
+		{models.SynonymRule{Type: models.SynonymExpansion, Input: "a"}, false, "synonyms"},
+		{models.SynonymRule{Type: models.SynonymRewrite, Input: "a"}, true, ""},
+		{models.SynonymRule{Type: models.SynonymRewrite, Replacement: "b"}, false, "input"},
+		{models.SynonymRule{Type: "antonym", Input: "a"}, false, "type"},
+	}
+
+	for _, tt := range tests {
+		err := tt.rule.Validate()
+		if tt.valid {
+			if err != nil {
+				t.Errorf("Validate(%+v) failed: %v", tt.rule, err)
+			}
+			continue
+		}
+
+		paramErr, ok := err.(*models.ParameterError)
+		if !ok {
+			t.Errorf("Validate(%+v) = %v, expected a parameter error", tt.rule, err)
+			continue
+		}
+		if paramErr.Key != tt.field {
+			t.Errorf("Validate(%+v) field = %q, expected %q", tt.rule, paramErr.Key, tt.field)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/trending"
+)
+
+func TestTrendingWindows(t *testing.T) {
+	valid := map[string]time.Duration{
+		"1h":   time.Hour,
+		"24h":  24 * time.Hour,
+		"90m":  90 * time.Minute,
+		"7d":   7 * 24 * time.Hour,
+		" 1d ": 24 * time.Hour,
+	}
+	for input, expected := range valid {
+		window, err := trending.ParseWindow(input)
+		if err != nil || window != expected {
+			t.Errorf("ParseWindow(%q) = %s, %v, expected %s", input, window, err, expected)
+		}
+	}
+
+	for _, input := range []string{"", "d", "0d", "-1d", "0h", "-1h", "week"} {
+		if _, err := trending.ParseWindow(input); err == nil {
+			t.Errorf("Expected ParseWindow(%q) to fail", input)
+		}
+	}
+
+	formats := map[time.Duration]string{
+		time.Hour:           "1h",
+		24 * time.Hour:      "24h",
+		48 * time.Hour:      "2d",
+		7 * 24 * time.Hour:  "7d",
+		36 * time.Hour:      "36h",
+		90 * time.Minute:    "1h30m0s",
+		30 * 24 * time.Hour: "30d",
+	}
+	for window, expected := range formats {
+		if got := trending.FormatWindow(window); got != expected {
+			t.Errorf("FormatWindow(%s) = %q, expected %q", window, got, expected)
+		}
+	}
+}
+
+// snapshotsAt builds the snapshots of a content from its engagement at hours
+// past a fixed time
+func snapshotsAt(contentID uint, engagement map[int]int) []models.MetricSnapshot {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	var hours []int
+	for hour := range engagement {
+		hours = append(hours, hour)
+	}
+	sort.Ints(hours)
+
+	snapshots := make([]models.MetricSnapshot, len(hours))
+	for i, hour := range hours {
+		snapshots[i] = models.MetricSnapshot{
+			ContentID:  contentID,
+			Views:      engagement[hour],
+			CapturedAt: start.Add(time.Duration(hour) * time.Hour),
+		}
+	}
+	return snapshots
+}
+
+func TestTrendingCompute(t *testing.T) {
+	snapshots := snapshotsAt(1, map[int]int{0: 100, 24: 300, 48: 700})
+
+	tests := []struct {
+		window   time.Duration
+		expected models.TrendMetrics
+		describe string
+	}{
+		{24 * time.Hour, models.TrendMetrics{Delta: 400, Velocity: 16.666667, Acceleration: 0.347222, GrowthRate: 1}, "a day"},
+		// The latest snapshot at least an hour earlier is a day earlier
+		{time.Hour, models.TrendMetrics{Delta: 400, Velocity: 16.666667, Acceleration: 8.333333, GrowthRate: 1}, "an hour"},
+		// Younger than the window, the trend covers the whole history
+		{7 * 24 * time.Hour, models.TrendMetrics{Delta: 600, Velocity: 12.5, GrowthRate: 3}, "a week"},
+	}
+	for _, test := range tests {
+		got := trending.Compute(snapshots, test.window, 100)
+		if math.Abs(got.Delta-test.expected.Delta) > 1e-6 ||
+			math.Abs(got.Velocity-test.expected.Velocity) > 1e-6 ||
+			math.Abs(got.Acceleration-test.expected.Acceleration) > 1e-6 ||
+			math.Abs(got.GrowthRate-test.expected.GrowthRate) > 1e-6 {
+			t.Errorf("Trend over %s = %+v, expected %+v", test.describe, got, test.expected)
+		}
+	}
+
+	// A single snapshot has no trend
+	if got := trending.Compute(snapshots[:1], 24*time.Hour, 100); got != (models.TrendMetrics{}) {
+		t.Errorf("Expected no trend for a single snapshot, got %+v", got)
+	}
+
+	// Smoothing keeps small contents from growing by orders of magnitude
+	small := snapshotsAt(2, map[int]int{0: 1, 24: 51})
+	if got := trending.Compute(small, 24*time.Hour, 0).GrowthRate; got != 50 {
+		t.Errorf("Expected an unsmoothed growth rate of 50, got %f", got)
+	}
+	if got := trending.Compute(small, 24*time.Hour, 100).GrowthRate; math.Abs(got-50.0/101) > 1e-6 {
+		t.Errorf("Expected a smoothed growth rate of %f, got %f", 50.0/101, got)
+	}
+}
+
+func TestTrendingRank(t *testing.T) {
+	var snapshots []models.MetricSnapshot
+	snapshots = append(snapshots, snapshotsAt(1, map[int]int{24: 300, 48: 700})...)
+	snapshots = append(snapshots, snapshotsAt(2, map[int]int{24: 100, 48: 500})...)
+	// No engagement gained
+	snapshots = append(snapshots, snapshotsAt(3, map[int]int{24: 50, 48: 50})...)
+	// Same growth rate as content 1, faster
+	snapshots = append(snapshots, snapshotsAt(4, map[int]int{24: 900, 48: 1900})...)
+	// Same trend as content 1
+	snapshots = append(snapshots, snapshotsAt(5, map[int]int{24: 300, 48: 700})...)
+	// A single snapshot
+	snapshots = append(snapshots, snapshotsAt(6, map[int]int{48: 10})...)
+
+	trends := trending.Rank(snapshots, 24*time.Hour, 100)
+	expected := []uint{2, 4, 1, 5}
+	if len(trends) != len(expected) {
+		t.Fatalf("Expected %d trending contents, got %+v", len(expected), trends)
+	}
+	for i, id := range expected {
+		if trends[i].ContentID != id {
+			t.Errorf("Expected content %d at rank %d, got %d", id, i+1, trends[i].ContentID)
+		}
+	}
+}
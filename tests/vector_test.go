@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"search-engine-service/internal/database/models"
+	"search-engine-service/internal/embedding"
+	"search-engine-service/internal/index"
+	"search-engine-service/internal/vector"
+)
+
+func cosine(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+func TestHashEmbedder(t *testing.T) {
+	embedder := embedding.NewHashEmbedder(256)
+
+	v := embedder.Embed("Advanced Go Concurrency Patterns")
+	if len(v) != 256 {
+		t.Fatalf("Expected 256 dimensions, got %d", len(v))
+	}
+	if norm := math.Sqrt(cosine(v, v)); math.Abs(norm-1) > 1e-6 {
+		t.Errorf("Expected a unit vector, got norm %f", norm)
+	}
+	if !reflect.DeepEqual(v, embedder.Embed("advanced go concurrency patterns")) {
+		t.Error("Expected embeddings to be deterministic and case-insensitive")
+	}
+	if !embedding.IsZero(embedder.Embed(" ,.! ")) {
+		t.Error("Expected text without words to embed to the zero vector")
+	}
+
+	// Word forms sharing n-grams are closer than unrelated words
+	query := embedder.Embed("concurrent programming")
+	related := cosine(query, embedder.Embed("concurrency patterns for programmers"))
+	unrelated := cosine(query, embedder.Embed("kubernetes deployment tutorial"))
+	if related <= unrelated {
+		t.Errorf("Expected related text to be closer, got %f <= %f", related, unrelated)
+	}
+}
+
+func TestHNSWRecall(t *testing.T) {
+	const dims, count, k = 32, 1000, 10
+	rng := rand.New(rand.NewSource(42))
+	randomVector := func() []float32 {
+		v := make([]float32, dims)
+		for i := range v {
+			v[i] = float32(rng.NormFloat64())
+		}
+		embedding.Normalize(v)
+		return v
+	}
+
+	graph := vector.New(vector.DefaultParams())
+	vectors := make([][]float32, count)
+	for i := range vectors {
+		vectors[i] = randomVector()
+		graph.Add(uint(i+1), vectors[i])
+	}
+	if graph.Len() != count {
+		t.Fatalf("Expected %d vectors, got %d", count, graph.Len())
+	}
+
+	found, total := 0, 0
+	for q := 0; q < 50; q++ {
+		query := randomVector()
+
+		ids := make([]uint, count)
+		for i := range ids {
+			ids[i] = uint(i + 1)
+		}
+		sort.Slice(ids, func(i, j int) bool {
+			return cosine(query, vectors[ids[i]-1]) > cosine(query, vectors[ids[j]-1])
+		})
+		exact := make(map[uint]bool)
+		for _, id := range ids[:k] {
+			exact[id] = true
+		}
+
+		results := graph.Search(query, k)
+		for i, result := range results {
+			if exact[result.ID] {
+				found++
+			}
+			if i > 0 && result.Similarity > results[i-1].Similarity {
+				t.Fatalf("Expected results ordered by similarity, got %v", results)
+			}
+		}
+		total += k
+	}
+
+	if recall := float64(found) / float64(total); recall < 0.9 {
+		t.Errorf("Expected recall@%d of at least 0.9, got %f", k, recall)
+	}
+}
+
+func TestHNSWUpdateAndRemove(t *testing.T) {
+	embedder := embedding.NewHashEmbedder(64)
+	graph := vector.New(vector.DefaultParams())
+	graph.Add(1, embedder.Embed("go concurrency"))
+	graph.Add(2, embedder.Embed("kubernetes deployment"))
+
+	// Replacing a vector moves the ID
+	graph.Add(1, embedder.Embed("docker images"))
+	if results := graph.Search(embedder.Embed("docker images"), 1); len(results) != 1 || results[0].ID != 1 {
+		t.Errorf("Expected the replaced vector to be found, got %v", results)
+	}
+	if graph.Len() != 2 {
+		t.Errorf("Expected 2 vectors, got %d", graph.Len())
+	}
+
+	graph.Remove(1)
+	for _, result := range graph.Search(embedder.Embed("docker images"), 5) {
+		if result.ID == 1 {
+			t.Error("Expected the removed vector not to be returned")
+		}
+	}
+	if graph.Len() != 1 {
+		t.Errorf("Expected 1 vector, got %d", graph.Len())
+	}
+}
+
+func TestFuseRanks(t *testing.T) {
+	keyword := []index.Hit{{DocID: 1, Score: 12}, {DocID: 2, Score: 8}, {DocID: 3, Score: 1}}
+	semantic := []index.Hit{{DocID: 3, Score: 0.9}, {DocID: 4, Score: 0.8}, {DocID: 1, Score: 0.5}}
+
+	hits := index.FuseRanks(60, keyword, semantic)
+
+	var ids []uint
+	for _, hit := range hits {
+		ids = append(ids, hit.DocID)
+	}
+	// 1: 1/61 + 1/63, 3: 1/63 + 1/61, 2: 1/62, 4: 1/62, ties by ID
+	if expected := []uint{1, 3, 2, 4}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected %v, got %v", expected, ids)
+	}
+	if expected := 1.0/61 + 1.0/63; math.Abs(hits[0].Score-expected) > 1e-12 {
+		t.Errorf("Expected fused score %f, got %f", expected, hits[0].Score)
+	}
+}
+
+func TestVectorColumn(t *testing.T) {
+	original := models.Vector{0.5, -1.25, 3}
+	value, err := original.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	var scanned models.Vector
+	if err := scanned.Scan(value); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !reflect.DeepEqual(scanned, original) {
+		t.Errorf("Expected %v, got %v", original, scanned)
+	}
+
+	if err := scanned.Scan([]byte{1, 2, 3}); err == nil {
+		t.Error("Expected an error for a truncated vector")
+	}
+}
+
+func TestParseSearchMode(t *testing.T) {
+	if mode, err := models.ParseSearchMode(""); err != nil || mode != models.SearchModeKeyword {
+		t.Errorf("Expected keyword by default, got %q, %v", mode, err)
+	}
+	if mode, err := models.ParseSearchMode("hybrid"); err != nil || mode != models.SearchModeHybrid {
+		t.Errorf("Expected hybrid, got %q, %v", mode, err)
+	}
+	if _, err := models.ParseSearchMode("semantic"); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+}
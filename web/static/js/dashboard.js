@@ -3,6 +3,7 @@ let currentPage = 1;
 let currentLimit = 10;
 let currentQuery = '';
 let currentType = 'all';
+let currentFilters = {};
 
 // Initialize dashboard on page load
 document.addEventListener('DOMContentLoaded', function() {
@@ -15,8 +16,46 @@ document.addEventListener('DOMContentLoaded', function() {
             performSearch();
         }
     });
+
+    // Autocomplete as the user types
+    document.getElementById('searchInput').addEventListener('input', function(e) {
+        loadSuggestions(e.target.value);
+    });
 });
 
+// Load autocomplete suggestions for the search box
+let suggestionRequest = 0;
+async function loadSuggestions(prefix) {
+    const datalist = document.getElementById('searchSuggestions');
+    const requestId = ++suggestionRequest;
+
+    if (prefix.trim() === '') {
+        datalist.innerHTML = '';
+        return;
+    }
+
+    try {
+        const params = new URLSearchParams({ q: prefix, limit: 8 });
+        const response = await fetch(`/api/search/suggestions?${params}`);
+        const data = await response.json();
+
+        // Ignore responses to outdated keystrokes
+        if (requestId !== suggestionRequest || !data.success) {
+            return;
+        }
+
+        datalist.innerHTML = '';
+        data.data.suggestions.forEach(suggestion => {
+            const option = document.createElement('option');
+            option.value = suggestion.text;
+            option.label = `${suggestion.type} (${suggestion.count})`;
+            datalist.appendChild(option);
+        });
+    } catch (error) {
+        console.error('Error loading suggestions:', error);
+    }
+}
+
 // Load dashboard data
 async function loadDashboard() {
     try {
@@ -58,6 +97,7 @@ async function performSearch() {
     
     currentQuery = query;
     currentType = contentType;
+    currentFilters = {};
     currentPage = 1;
     
     await executeSearch();
@@ -72,7 +112,13 @@ async function executeSearch() {
             q: currentQuery,
             type: currentType,
             page: currentPage,
-            limit: currentLimit
+            limit: currentLimit,
+            highlight: true,
+            fragment_size: 100,
+            max_fragments: 1
+        });
+        Object.entries(currentFilters).forEach(([param, values]) => {
+            values.forEach(value => params.append(param, value));
         });
         
         const response = await fetch(`/api/search?${params}`);
@@ -80,6 +126,7 @@ async function executeSearch() {
         
         if (data.success) {
             displaySearchResults(data.data);
+            displayFacets(data.data.facets);
             displayPagination(data.data);
         } else {
             showError('Arama sırasında bir hata oluştu');
@@ -123,6 +170,13 @@ function createContentCard(content) {
     const typeBadgeClass = content.type === 'video' ? 'bg-danger' : 'bg-primary';
     const scoreClass = content.final_score > 50 ? 'bg-success' : content.final_score > 25 ? 'bg-warning' : 'bg-secondary';
     
+    // Prefer the highlighted snippets over the truncated description
+    const highlights = content.highlights || {};
+    const title = highlights.title ? highlights.title[0] : content.title;
+    const description = highlights.description
+        ? `...${highlights.description[0]}...`
+        : `${content.description.substring(0, 100)}${content.description.length > 100 ? '...' : ''}`;
+    
     col.innerHTML = `
         <div class="card content-card h-100" onclick="showContentDetail(${content.id})">
             <div class="card-body">
@@ -134,8 +188,8 @@ function createContentCard(content) {
                         ${content.final_score.toFixed(1)} puan
                     </span>
                 </div>
-                <h6 class="card-title">${content.title}</h6>
-                <p class="card-text text-muted small">${content.description.substring(0, 100)}${content.description.length > 100 ? '...' : ''}</p>
+                <h6 class="card-title">${title}</h6>
+                <p class="card-text text-muted small">${description}</p>
                 <div class="mt-auto">
                     <small class="text-muted">
                         <i class="fas fa-calendar"></i> ${formatDate(content.published_at)}
@@ -187,6 +241,74 @@ function createPopularContentCard(content) {
     return col;
 }
 
+// Display facet counts as toggleable filters
+function displayFacets(facets) {
+    const container = document.getElementById('searchFacets');
+    container.innerHTML = '';
+
+    if (!facets) return;
+
+    const groups = [
+        ['Tür', facets.type],
+        ['Sağlayıcı', facets.provider],
+        ['Dil', facets.language],
+        ['Etiketler', facets.tags],
+        ['Yayın Tarihi', facets.published]
+    ];
+
+    groups.forEach(([label, values]) => {
+        if (!values || values.length === 0) return;
+
+        const group = document.createElement('div');
+        group.className = 'mb-2';
+        group.innerHTML = `<strong class="me-2">${label}:</strong>`;
+
+        values.forEach(facet => {
+            const selected = isFacetSelected(facet);
+            const badge = document.createElement('a');
+            badge.href = '#';
+            badge.className = `badge me-1 text-decoration-none ${selected ? 'bg-primary' : 'bg-light text-dark'}`;
+            badge.textContent = `${facet.value} (${facet.count})`;
+            badge.addEventListener('click', function(e) {
+                e.preventDefault();
+                toggleFacet(facet);
+            });
+            group.appendChild(badge);
+        });
+
+        container.appendChild(group);
+    });
+}
+
+// Check whether a facet value is part of the current filters
+function isFacetSelected(facet) {
+    if (facet.param === 'type') {
+        return currentType === facet.value;
+    }
+    return (currentFilters[facet.param] || []).includes(facet.value);
+}
+
+// Add or remove a facet value from the filters and search again
+async function toggleFacet(facet) {
+    if (facet.param === 'type') {
+        currentType = currentType === facet.value ? 'all' : facet.value;
+        document.getElementById('contentType').value = currentType;
+    } else {
+        const values = currentFilters[facet.param] || [];
+        if (values.includes(facet.value)) {
+            currentFilters[facet.param] = values.filter(value => value !== facet.value);
+        } else if (facet.param === 'tag') {
+            // Tags narrow the results together, every other facet holds one value
+            currentFilters[facet.param] = [...values, facet.value];
+        } else {
+            currentFilters[facet.param] = [facet.value];
+        }
+    }
+
+    currentPage = 1;
+    await executeSearch();
+}
+
 // Display pagination
 function displayPagination(result) {
     const container = document.getElementById('pagination');